@@ -45,8 +45,17 @@ type GrpcBackendGroupSpec struct { // nolint:revive
 
 // GrpcBackendGroupStatus defines the observed state of GrpcBackendGroup
 type GrpcBackendGroupStatus struct { // nolint:revive
-	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
-	// Important: Run "make" to regenerate code after modifying this file
+	// Health of targets of backends, updated periodically
+	// +kubebuilder:validation:Optional
+	Backends []BackendTargetStates `json:"backends,omitempty"`
+	// Generation of the backend group the conditions were set for
+	// +kubebuilder:validation:Optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Ready, Reconciling and Degraded conditions reporting the outcome of the last reconciliation
+	// +kubebuilder:validation:Optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
 type GrpcHealthCheck struct {
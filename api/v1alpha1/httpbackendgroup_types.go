@@ -107,8 +107,30 @@ type HttpBackendGroupSpec struct { // nolint:revive
 
 // HttpBackendGroupStatus defines the observed state of HttpBackendGroup
 type HttpBackendGroupStatus struct { // nolint:revive
-	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
-	// Important: Run "make" to regenerate code after modifying this file
+	// Health of targets of backends, updated periodically
+	// +kubebuilder:validation:Optional
+	Backends []BackendTargetStates `json:"backends,omitempty"`
+	// Generation of the backend group the conditions were set for
+	// +kubebuilder:validation:Optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Ready, Reconciling and Degraded conditions reporting the outcome of the last reconciliation
+	// +kubebuilder:validation:Optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// BackendTargetStates counts targets of backend by their health in availability zones
+type BackendTargetStates struct {
+	Name string `json:"name"`
+	// +kubebuilder:validation:Optional
+	Zones []ZoneTargetStates `json:"zones,omitempty"`
+}
+
+type ZoneTargetStates struct {
+	ZoneID    string `json:"zoneID"`
+	Healthy   int64  `json:"healthy"`
+	Unhealthy int64  `json:"unhealthy"`
 }
 
 type HttpHealthCheck struct { //nolint:revive
@@ -59,15 +59,181 @@ type LogOptions struct {
 	Disable bool `json:"disable"`
 }
 
+// RBACHeaderMatcher matches requests by header value.
+// If none of exact, prefix and regex is set, any request with the header matches.
+type RBACHeaderMatcher struct {
+	// Name of the request header.
+	Name string `json:"name"`
+
+	// +kubebuilder:validation:Optional
+	Exact string `json:"exact"`
+
+	// +kubebuilder:validation:Optional
+	Prefix string `json:"prefix"`
+
+	// +kubebuilder:validation:Optional
+	Regex string `json:"regex"`
+}
+
+// RBACPrincipal is a single condition checked against a request.
+// Exactly one of header, remoteIP and any must be set.
+type RBACPrincipal struct {
+	// +kubebuilder:validation:Optional
+	Header *RBACHeaderMatcher `json:"header"`
+
+	// CIDR block or IP of the request remote address, e.g. 192.0.0.0/24 or 192.0.0.4.
+	// +kubebuilder:validation:Optional
+	RemoteIP string `json:"remoteIP"`
+
+	// Matches any request.
+	// +kubebuilder:validation:Optional
+	Any bool `json:"any"`
+}
+
+// RBACPrincipals matches a request when all of its principals match.
+type RBACPrincipals struct {
+	// +kubebuilder:validation:MinItems=1
+	AndPrincipals []RBACPrincipal `json:"andPrincipals"`
+}
+
+// RBAC allows or denies requests matching at least one of the principals.
+type RBAC struct {
+	// +kubebuilder:validation:Enum=ALLOW;DENY
+	Action string `json:"action"`
+
+	// +kubebuilder:validation:MinItems=1
+	Principals []RBACPrincipals `json:"principals"`
+}
+
+// Listener is an additional listener of the balancer of the group on a custom port.
+type Listener struct {
+	// Port of the listener, must differ from 80, 443 and ports of stream listeners.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	Port int64 `json:"port"`
+
+	// HTTP listener serves plain text requests, HTTPS one terminates TLS with certificates of the group
+	// and serves its TLS hosts.
+	// +kubebuilder:validation:Enum=HTTP;HTTPS
+	Protocol string `json:"protocol"`
+
+	// Router serving requests of HTTP listener: HTTP router redirecting TLS hosts to HTTPS or TLS router
+	// serving TLS hosts without redirect. HTTP by default, HTTPS listeners always use TLS router.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=HTTP;TLS
+	Router string `json:"router"`
+}
+
+// HTTP2Options enables HTTP/2 on listeners of the group.
+type HTTP2Options struct {
+	// Maximum number of concurrent HTTP/2 streams in a connection, unlimited if not set.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	MaxConcurrentStreams int64 `json:"maxConcurrentStreams"`
+}
+
+// ProtocolSettings of HTTP handlers of the group. Only one of allowHTTP10 and http2 can be set.
+type ProtocolSettings struct {
+	// Enables HTTP/1.0 and HTTP/1.1 and disables HTTP/2.
+	// +kubebuilder:validation:Optional
+	AllowHTTP10 bool `json:"allowHTTP10"`
+
+	// +kubebuilder:validation:Optional
+	HTTP2 *HTTP2Options `json:"http2"`
+}
+
+// Addresses of the balancer. Value "auto" allocates a new address.
+type Addresses struct {
+	// +kubebuilder:validation:Optional
+	ExternalIPv4 string `json:"externalIPv4"`
+
+	// +kubebuilder:validation:Optional
+	ExternalIPv6 string `json:"externalIPv6"`
+
+	// +kubebuilder:validation:Optional
+	InternalIPv4 string `json:"internalIPv4"`
+
+	// Subnet of the internal IPv4 address, the subnet of the first location of the balancer by default.
+	// +kubebuilder:validation:Optional
+	InternalSubnetID string `json:"internalSubnetID"`
+}
+
+// AutoScale policy of the balancer resource units.
+type AutoScale struct {
+	// Minimum number of resource units in each availability zone.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	MinZoneSize *int64 `json:"minZoneSize"`
+
+	// Maximum total number of resource units.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	MaxSize *int64 `json:"maxSize"`
+}
+
+// IngressGroupSettingsStatus defines the observed state of IngressGroupSettings
+type IngressGroupSettingsStatus struct {
+	// Ingress groups using the settings.
+	// +kubebuilder:validation:Optional
+	IngressGroups []string `json:"ingressGroups,omitempty"`
+}
+
 // +kubebuilder:object:root=true
 //+kubebuilder:resource:scope=Cluster
+//+kubebuilder:subresource:status
 
+// IngressGroupSettings sets balancer level options of ingress groups referencing it by group-settings-name
+// annotation. Options set here take precedence over the ones set by ingress annotations.
 type IngressGroupSettings struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
 
 	// +kubebuilder:validation:Optional
 	LogOptions *LogOptions `json:"logOptions"`
+
+	// RBAC policy applied to virtual hosts of the group which have no RBAC set by ingress annotations.
+	// +kubebuilder:validation:Optional
+	RBAC *RBAC `json:"rbac"`
+
+	// Listeners of the balancer in addition to HTTP on port 80 and HTTPS on port 443.
+	// +kubebuilder:validation:Optional
+	Listeners []Listener `json:"listeners"`
+
+	// Protocol settings of HTTP handlers, override the ones set by ingress annotations.
+	// +kubebuilder:validation:Optional
+	ProtocolSettings *ProtocolSettings `json:"protocolSettings"`
+
+	// Subnets of the balancer locations, one per availability zone, override the ones set by ingress annotations.
+	// +kubebuilder:validation:Optional
+	Subnets []string `json:"subnets"`
+
+	// Security groups of the balancer, override the ones set by ingress annotations.
+	// +kubebuilder:validation:Optional
+	SecurityGroups []string `json:"securityGroups"`
+
+	// Addresses of the balancer, override the ones set by ingress annotations.
+	// +kubebuilder:validation:Optional
+	Addresses *Addresses `json:"addresses"`
+
+	// Auto scale policy of the balancer, overrides the one set by ingress annotations.
+	// +kubebuilder:validation:Optional
+	AutoScale *AutoScale `json:"autoScale"`
+
+	// Redirects HTTP requests of TLS hosts to HTTPS, overrides the value set by ingress annotations.
+	// +kubebuilder:validation:Optional
+	RedirectHTTPToHTTPS *bool `json:"redirectHTTPToHTTPS"`
+
+	// Smart Web Security profile of virtual hosts of the group, overrides the ones set by ingress annotations.
+	// +kubebuilder:validation:Optional
+	SecurityProfileID string `json:"securityProfileID"`
+
+	// Existing load balancer adopted by the group instead of creating a new one. It's renamed and relabeled together
+	// with its routers and then reconciled in place, so its addresses must be set in addresses of the settings to be kept.
+	// +kubebuilder:validation:Optional
+	LoadBalancerID string `json:"loadBalancerID"`
+
+	// +kubebuilder:validation:Optional
+	Status IngressGroupSettingsStatus `json:"status,omitempty"`
 }
 
 //+kubebuilder:object:root=true
@@ -38,6 +38,52 @@ type IngressGroupStatus struct {
 	BackendGroupIDs []string `json:"backendGroupIDs"`
 	// +kubebuilder:validation:Optional
 	TargetGroupIDs []string `json:"targetGroupIDs"`
+
+	// Changes of cloud resources which the controller running in dry-run mode would apply
+	// +kubebuilder:validation:Optional
+	PlannedChanges []PlannedChange `json:"plannedChanges,omitempty"`
+
+	// Ready, Reconciling and Degraded conditions reporting the outcome of the last reconciliation of the group
+	// +kubebuilder:validation:Optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// Rules of ingresses of the group accepted by the last reconciliation
+	// +kubebuilder:validation:Optional
+	Ingresses []IngressRulesStatus `json:"ingresses,omitempty"`
+}
+
+// IngressRulesStatus summarizes which rules of the ingress were accepted
+type IngressRulesStatus struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	// Generation of the ingress the rules were reconciled for
+	// +kubebuilder:validation:Optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// +kubebuilder:validation:Optional
+	Rules []IngressRuleStatus `json:"rules,omitempty"`
+}
+
+// IngressRuleStatus reports whether the path of the ingress rule is routed by the balancer, empty host and path stand
+// for the default backend
+type IngressRuleStatus struct {
+	// +kubebuilder:validation:Optional
+	Host string `json:"host,omitempty"`
+	// +kubebuilder:validation:Optional
+	Path     string `json:"path,omitempty"`
+	Accepted bool   `json:"accepted"`
+	Reason   string `json:"reason"`
+	// +kubebuilder:validation:Optional
+	Message string `json:"message,omitempty"`
+}
+
+// PlannedChange describes a skipped mutation of cloud resource, diff is set for created and updated resources
+type PlannedChange struct {
+	Action       string `json:"action"`
+	ResourceType string `json:"resourceType"`
+	Name         string `json:"name"`
+	// +kubebuilder:validation:Optional
+	Diff string `json:"diff,omitempty"`
 }
 
 //+kubebuilder:object:root=true
@@ -0,0 +1,144 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type StreamBackend struct {
+	Name string `json:"name"`
+	// +kubebuilder:default:=1
+	Weight              int64                `json:"weight,omitempty"`
+	Service             *ServiceBackend      `json:"service,omitempty"`
+	TLS                 *BackendTLS          `json:"tls,omitempty"`
+	LoadBalancingConfig *LoadBalancingConfig `json:"loadBalancingConfig,omitempty"`
+
+	// If set, proxy protocol will be enabled for this backend.
+	// +kubebuilder:validation:Optional
+	EnableProxyProtocol bool `json:"enableProxyProtocol,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	HealthChecks []*StreamHealthCheck `json:"healthChecks,omitempty"`
+}
+
+// StreamSessionAffinity only connection affinity is available for stream backend groups
+type StreamSessionAffinity struct {
+	// +kubebuilder:validation:Optional
+	Connection *SessionAffinityConnection `json:"connection"`
+}
+
+// StreamBackendGroupSpec defines the desired state of StreamBackendGroup
+type StreamBackendGroupSpec struct {
+	// +kubebuilder:validation:Optional
+	SessionAffinity *StreamSessionAffinity `json:"sessionAffinity"`
+	Backends        []*StreamBackend       `json:"backends,omitempty"`
+}
+
+// StreamBackendGroupStatus defines the observed state of StreamBackendGroup
+type StreamBackendGroupStatus struct {
+	// Health of targets of backends, updated periodically
+	// +kubebuilder:validation:Optional
+	Backends []BackendTargetStates `json:"backends,omitempty"`
+	// Generation of the backend group the conditions were set for
+	// +kubebuilder:validation:Optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Ready, Reconciling and Degraded conditions reporting the outcome of the last reconciliation
+	// +kubebuilder:validation:Optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// StreamHealthCheck is a health check of stream backend. Exactly one of stream, http and grpc checks must be set
+type StreamHealthCheck struct {
+	// +kubebuilder:validation:Optional
+	Stream *TCPHealthCheck `json:"stream"`
+	// +kubebuilder:validation:Optional
+	HTTP *HttpHealthCheck `json:"http"`
+	// +kubebuilder:validation:Optional
+	GRPC *GrpcHealthCheck `json:"grpc"`
+
+	Port *int64 `json:"port"`
+
+	// Health check timeout.
+	//
+	// The timeout is the time allowed for the target to respond to a check.
+	// If the target doesn't respond in time, the check is considered failed
+	// +kubebuilder:validation:Optional
+	Timeout *metav1.Duration `json:"timeout"`
+
+	// Base interval between consecutive health checks.
+	// +kubebuilder:validation:Optional
+	Interval *metav1.Duration `json:"interval"`
+
+	// Number of consecutive successful health checks required to mark an unhealthy target as healthy.
+	//
+	// Both `0` and `1` values amount to one successful check required.
+	//
+	// The value is ignored when a load balancer is initialized; a target is marked healthy after one successful check.
+	//
+	// Default value: `0`.
+	// +kubebuilder:validation:Optional
+	HealthyThreshold int64 `json:"healthyThreshold"`
+
+	// Number of consecutive failed health checks required to mark a healthy target as unhealthy.
+	//
+	// Both `0` and `1` values amount to one unsuccessful check required.
+	//
+	// The value is ignored if a health check is failed due to an HTTP `503 Service Unavailable` response from the target
+	// (not applicable to TCP stream health checks). The target is immediately marked unhealthy.
+	//
+	// Default value: `0`.
+	// +kubebuilder:validation:Optional
+	UnhealthyThreshold int64 `json:"unhealthyThreshold"`
+}
+
+// TCPHealthCheck checks TCP connection to the target.
+// If send is set, the payload is sent to the target after connection is established,
+// if receive is set, the target is healthy only when response contains the payload.
+type TCPHealthCheck struct {
+	// +kubebuilder:validation:Optional
+	Send string `json:"send"`
+	// +kubebuilder:validation:Optional
+	Receive string `json:"receive"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+
+// StreamBackendGroup is the Schema for the streambackendgroups API
+type StreamBackendGroup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   StreamBackendGroupSpec   `json:"spec,omitempty"`
+	Status StreamBackendGroupStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// StreamBackendGroupList contains a list of StreamBackendGroup
+type StreamBackendGroupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []StreamBackendGroup `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&StreamBackendGroup{}, &StreamBackendGroupList{})
+}
@@ -25,6 +25,46 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Addresses) DeepCopyInto(out *Addresses) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Addresses.
+func (in *Addresses) DeepCopy() *Addresses {
+	if in == nil {
+		return nil
+	}
+	out := new(Addresses)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutoScale) DeepCopyInto(out *AutoScale) {
+	*out = *in
+	if in.MinZoneSize != nil {
+		in, out := &in.MinZoneSize, &out.MinZoneSize
+		*out = new(int64)
+		**out = **in
+	}
+	if in.MaxSize != nil {
+		in, out := &in.MaxSize, &out.MaxSize
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoScale.
+func (in *AutoScale) DeepCopy() *AutoScale {
+	if in == nil {
+		return nil
+	}
+	out := new(AutoScale)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BackendTLS) DeepCopyInto(out *BackendTLS) {
 	*out = *in
@@ -40,6 +80,26 @@ func (in *BackendTLS) DeepCopy() *BackendTLS {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackendTargetStates) DeepCopyInto(out *BackendTargetStates) {
+	*out = *in
+	if in.Zones != nil {
+		in, out := &in.Zones, &out.Zones
+		*out = make([]ZoneTargetStates, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackendTargetStates.
+func (in *BackendTargetStates) DeepCopy() *BackendTargetStates {
+	if in == nil {
+		return nil
+	}
+	out := new(BackendTargetStates)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GrpcBackend) DeepCopyInto(out *GrpcBackend) {
 	*out = *in
@@ -87,7 +147,7 @@ func (in *GrpcBackendGroup) DeepCopyInto(out *GrpcBackendGroup) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrpcBackendGroup.
@@ -174,6 +234,20 @@ func (in *GrpcBackendGroupSpec) DeepCopy() *GrpcBackendGroupSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GrpcBackendGroupStatus) DeepCopyInto(out *GrpcBackendGroupStatus) {
 	*out = *in
+	if in.Backends != nil {
+		in, out := &in.Backends, &out.Backends
+		*out = make([]BackendTargetStates, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrpcBackendGroupStatus.
@@ -201,6 +275,21 @@ func (in *GrpcHealthCheck) DeepCopy() *GrpcHealthCheck {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTP2Options) DeepCopyInto(out *HTTP2Options) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTP2Options.
+func (in *HTTP2Options) DeepCopy() *HTTP2Options {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTP2Options)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HealthCheck) DeepCopyInto(out *HealthCheck) {
 	*out = *in
@@ -293,7 +382,7 @@ func (in *HttpBackendGroup) DeepCopyInto(out *HttpBackendGroup) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HttpBackendGroup.
@@ -380,6 +469,20 @@ func (in *HttpBackendGroupSpec) DeepCopy() *HttpBackendGroupSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HttpBackendGroupStatus) DeepCopyInto(out *HttpBackendGroupStatus) {
 	*out = *in
+	if in.Backends != nil {
+		in, out := &in.Backends, &out.Backends
+		*out = make([]BackendTargetStates, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HttpBackendGroupStatus.
@@ -417,6 +520,47 @@ func (in *IngressGroupSettings) DeepCopyInto(out *IngressGroupSettings) {
 		*out = new(LogOptions)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.RBAC != nil {
+		in, out := &in.RBAC, &out.RBAC
+		*out = new(RBAC)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Listeners != nil {
+		in, out := &in.Listeners, &out.Listeners
+		*out = make([]Listener, len(*in))
+		copy(*out, *in)
+	}
+	if in.ProtocolSettings != nil {
+		in, out := &in.ProtocolSettings, &out.ProtocolSettings
+		*out = new(ProtocolSettings)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Subnets != nil {
+		in, out := &in.Subnets, &out.Subnets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SecurityGroups != nil {
+		in, out := &in.SecurityGroups, &out.SecurityGroups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Addresses != nil {
+		in, out := &in.Addresses, &out.Addresses
+		*out = new(Addresses)
+		**out = **in
+	}
+	if in.AutoScale != nil {
+		in, out := &in.AutoScale, &out.AutoScale
+		*out = new(AutoScale)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RedirectHTTPToHTTPS != nil {
+		in, out := &in.RedirectHTTPToHTTPS, &out.RedirectHTTPToHTTPS
+		*out = new(bool)
+		**out = **in
+	}
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IngressGroupSettings.
@@ -469,6 +613,26 @@ func (in *IngressGroupSettingsList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IngressGroupSettingsStatus) DeepCopyInto(out *IngressGroupSettingsStatus) {
+	*out = *in
+	if in.IngressGroups != nil {
+		in, out := &in.IngressGroups, &out.IngressGroups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IngressGroupSettingsStatus.
+func (in *IngressGroupSettingsStatus) DeepCopy() *IngressGroupSettingsStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(IngressGroupSettingsStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *IngressGroupStatus) DeepCopyInto(out *IngressGroupStatus) {
 	*out = *in
@@ -484,6 +648,25 @@ func (in *IngressGroupStatus) DeepCopyInto(out *IngressGroupStatus) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.PlannedChanges != nil {
+		in, out := &in.PlannedChanges, &out.PlannedChanges
+		*out = make([]PlannedChange, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Ingresses != nil {
+		in, out := &in.Ingresses, &out.Ingresses
+		*out = make([]IngressRulesStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IngressGroupStatus.
@@ -536,6 +719,56 @@ func (in *IngressGroupStatusList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IngressRuleStatus) DeepCopyInto(out *IngressRuleStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IngressRuleStatus.
+func (in *IngressRuleStatus) DeepCopy() *IngressRuleStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(IngressRuleStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IngressRulesStatus) DeepCopyInto(out *IngressRulesStatus) {
+	*out = *in
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]IngressRuleStatus, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IngressRulesStatus.
+func (in *IngressRulesStatus) DeepCopy() *IngressRulesStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(IngressRulesStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Listener) DeepCopyInto(out *Listener) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Listener.
+func (in *Listener) DeepCopy() *Listener {
+	if in == nil {
+		return nil
+	}
+	out := new(Listener)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LoadBalancingConfig) DeepCopyInto(out *LoadBalancingConfig) {
 	*out = *in
@@ -612,6 +845,120 @@ func (in *LogOptions) DeepCopy() *LogOptions {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PlannedChange) DeepCopyInto(out *PlannedChange) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlannedChange.
+func (in *PlannedChange) DeepCopy() *PlannedChange {
+	if in == nil {
+		return nil
+	}
+	out := new(PlannedChange)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProtocolSettings) DeepCopyInto(out *ProtocolSettings) {
+	*out = *in
+	if in.HTTP2 != nil {
+		in, out := &in.HTTP2, &out.HTTP2
+		*out = new(HTTP2Options)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProtocolSettings.
+func (in *ProtocolSettings) DeepCopy() *ProtocolSettings {
+	if in == nil {
+		return nil
+	}
+	out := new(ProtocolSettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RBAC) DeepCopyInto(out *RBAC) {
+	*out = *in
+	if in.Principals != nil {
+		in, out := &in.Principals, &out.Principals
+		*out = make([]RBACPrincipals, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RBAC.
+func (in *RBAC) DeepCopy() *RBAC {
+	if in == nil {
+		return nil
+	}
+	out := new(RBAC)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RBACHeaderMatcher) DeepCopyInto(out *RBACHeaderMatcher) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RBACHeaderMatcher.
+func (in *RBACHeaderMatcher) DeepCopy() *RBACHeaderMatcher {
+	if in == nil {
+		return nil
+	}
+	out := new(RBACHeaderMatcher)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RBACPrincipal) DeepCopyInto(out *RBACPrincipal) {
+	*out = *in
+	if in.Header != nil {
+		in, out := &in.Header, &out.Header
+		*out = new(RBACHeaderMatcher)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RBACPrincipal.
+func (in *RBACPrincipal) DeepCopy() *RBACPrincipal {
+	if in == nil {
+		return nil
+	}
+	out := new(RBACPrincipal)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RBACPrincipals) DeepCopyInto(out *RBACPrincipals) {
+	*out = *in
+	if in.AndPrincipals != nil {
+		in, out := &in.AndPrincipals, &out.AndPrincipals
+		*out = make([]RBACPrincipal, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RBACPrincipals.
+func (in *RBACPrincipals) DeepCopy() *RBACPrincipals {
+	if in == nil {
+		return nil
+	}
+	out := new(RBACPrincipals)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ServiceBackend) DeepCopyInto(out *ServiceBackend) {
 	*out = *in
@@ -737,3 +1084,258 @@ func (in *StorageBucketBackend) DeepCopy() *StorageBucketBackend {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StreamBackend) DeepCopyInto(out *StreamBackend) {
+	*out = *in
+	if in.Service != nil {
+		in, out := &in.Service, &out.Service
+		*out = new(ServiceBackend)
+		**out = **in
+	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(BackendTLS)
+		**out = **in
+	}
+	if in.LoadBalancingConfig != nil {
+		in, out := &in.LoadBalancingConfig, &out.LoadBalancingConfig
+		*out = new(LoadBalancingConfig)
+		**out = **in
+	}
+	if in.HealthChecks != nil {
+		in, out := &in.HealthChecks, &out.HealthChecks
+		*out = make([]*StreamHealthCheck, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(StreamHealthCheck)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StreamBackend.
+func (in *StreamBackend) DeepCopy() *StreamBackend {
+	if in == nil {
+		return nil
+	}
+	out := new(StreamBackend)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StreamBackendGroup) DeepCopyInto(out *StreamBackendGroup) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StreamBackendGroup.
+func (in *StreamBackendGroup) DeepCopy() *StreamBackendGroup {
+	if in == nil {
+		return nil
+	}
+	out := new(StreamBackendGroup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *StreamBackendGroup) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StreamBackendGroupList) DeepCopyInto(out *StreamBackendGroupList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]StreamBackendGroup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StreamBackendGroupList.
+func (in *StreamBackendGroupList) DeepCopy() *StreamBackendGroupList {
+	if in == nil {
+		return nil
+	}
+	out := new(StreamBackendGroupList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *StreamBackendGroupList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StreamBackendGroupSpec) DeepCopyInto(out *StreamBackendGroupSpec) {
+	*out = *in
+	if in.SessionAffinity != nil {
+		in, out := &in.SessionAffinity, &out.SessionAffinity
+		*out = new(StreamSessionAffinity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Backends != nil {
+		in, out := &in.Backends, &out.Backends
+		*out = make([]*StreamBackend, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(StreamBackend)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StreamBackendGroupSpec.
+func (in *StreamBackendGroupSpec) DeepCopy() *StreamBackendGroupSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(StreamBackendGroupSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StreamBackendGroupStatus) DeepCopyInto(out *StreamBackendGroupStatus) {
+	*out = *in
+	if in.Backends != nil {
+		in, out := &in.Backends, &out.Backends
+		*out = make([]BackendTargetStates, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StreamBackendGroupStatus.
+func (in *StreamBackendGroupStatus) DeepCopy() *StreamBackendGroupStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(StreamBackendGroupStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StreamHealthCheck) DeepCopyInto(out *StreamHealthCheck) {
+	*out = *in
+	if in.Stream != nil {
+		in, out := &in.Stream, &out.Stream
+		*out = new(TCPHealthCheck)
+		**out = **in
+	}
+	if in.HTTP != nil {
+		in, out := &in.HTTP, &out.HTTP
+		*out = new(HttpHealthCheck)
+		**out = **in
+	}
+	if in.GRPC != nil {
+		in, out := &in.GRPC, &out.GRPC
+		*out = new(GrpcHealthCheck)
+		**out = **in
+	}
+	if in.Port != nil {
+		in, out := &in.Port, &out.Port
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StreamHealthCheck.
+func (in *StreamHealthCheck) DeepCopy() *StreamHealthCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(StreamHealthCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StreamSessionAffinity) DeepCopyInto(out *StreamSessionAffinity) {
+	*out = *in
+	if in.Connection != nil {
+		in, out := &in.Connection, &out.Connection
+		*out = new(SessionAffinityConnection)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StreamSessionAffinity.
+func (in *StreamSessionAffinity) DeepCopy() *StreamSessionAffinity {
+	if in == nil {
+		return nil
+	}
+	out := new(StreamSessionAffinity)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TCPHealthCheck) DeepCopyInto(out *TCPHealthCheck) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TCPHealthCheck.
+func (in *TCPHealthCheck) DeepCopy() *TCPHealthCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(TCPHealthCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ZoneTargetStates) DeepCopyInto(out *ZoneTargetStates) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ZoneTargetStates.
+func (in *ZoneTargetStates) DeepCopy() *ZoneTargetStates {
+	if in == nil {
+		return nil
+	}
+	out := new(ZoneTargetStates)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -16,6 +16,8 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	ycerrors "github.com/yandex-cloud/yc-alb-ingress-controller/pkg/errors"
+	"github.com/yandex-cloud/yc-alb-ingress-controller/pkg/k8s"
+	"github.com/yandex-cloud/yc-alb-ingress-controller/pkg/metrics"
 )
 
 const (
@@ -33,16 +35,23 @@ type Object interface {
 	runtime.Object
 }
 
-func HandleError(err error, log logr.Logger) (ctrl.Result, error) {
+// HandleError logs the outcome of reconciliation and counts it in metrics, group is empty for controllers
+// not dealing with ingress groups
+func HandleError(err error, log logr.Logger, controller, group string) (ctrl.Result, error) {
 	var outcome string
 	st := grpcStatus(err)
 	defer func() { logResult(log, outcome, err, st) }()
 
 	outcome = errorOutcome(err)
+	metrics.ReconcileTotal.WithLabelValues(controller, group, outcome).Inc()
 	switch outcome {
 	case DONE:
 		return ctrl.Result{}, nil
 	case REQUEUE:
+		var deregistration ycerrors.TargetsDeregistrationError
+		if errors.As(err, &deregistration) {
+			return ctrl.Result{RequeueAfter: deregistration.RequeueAfter}, nil
+		}
 		return ctrl.Result{RequeueAfter: FailureRequeueInterval * time.Second}, nil
 	}
 	return ctrl.Result{}, err
@@ -55,12 +64,23 @@ func HandleErrorWithObject(err error, obj Object, recorder record.EventRecorder)
 	outcome := errorOutcome(err)
 	switch outcome {
 	case DONE:
-		recorder.Eventf(obj, core.EventTypeNormal, "ReconciliationComplete", "Reconciliation complete for %s", obj.GetName())
+		recorder.Eventf(obj, core.EventTypeNormal, k8s.ReasonReconciliationComplete, "Reconciliation complete for %s", obj.GetName())
 	case REQUEUE:
-		recorder.Eventf(obj, core.EventTypeNormal, "ReconciliationRequeue", "Reconciliation requeue for %s, reason: %s", obj.GetName(), err.Error())
+		recorder.Eventf(obj, core.EventTypeNormal, k8s.ReasonReconciliationRequeue, "Reconciliation requeue for %s, reason: %s", obj.GetName(), err.Error())
 	case FAIL:
-		recorder.Eventf(obj, core.EventTypeWarning, "ReconciliationFailed", "Reconciliation failed for %s: %s", obj.GetName(), err.Error())
+		recorder.Eventf(obj, core.EventTypeWarning, k8s.ReasonReconciliationFailed, "Reconciliation failed for %s: %s", obj.GetName(), err.Error())
+	}
+}
+
+// ConditionReason returns the reason of status conditions by the outcome of reconciliation and the error message
+func ConditionReason(err error) (reason, message string) {
+	switch errorOutcome(err) {
+	case DONE:
+		return k8s.ReasonReconciliationComplete, ""
+	case REQUEUE:
+		return k8s.ReasonReconciliationRequeue, err.Error()
 	}
+	return k8s.ReasonReconciliationFailed, err.Error()
 }
 
 func isNil(obj interface{}) bool {
@@ -76,6 +96,7 @@ func errorOutcome(err error) string {
 	if errors.As(err, &ycerrors.ResourceNotReadyError{}) ||
 		errors.As(err, &ycerrors.OperationIncompleteError{}) ||
 		errors.As(err, &ycerrors.YCResourceNotReadyError{}) ||
+		errors.As(err, &ycerrors.TargetsDeregistrationError{}) ||
 		st != nil &&
 			st.Code() == codes.FailedPrecondition || st.Code() == codes.NotFound {
 		return REQUEUE
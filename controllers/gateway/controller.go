@@ -0,0 +1,313 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+
+	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"github.com/yandex-cloud/yc-alb-ingress-controller/controllers/errors"
+	"github.com/yandex-cloud/yc-alb-ingress-controller/controllers/ingress"
+	"github.com/yandex-cloud/yc-alb-ingress-controller/pkg/k8s"
+	"github.com/yandex-cloud/yc-alb-ingress-controller/pkg/yc"
+)
+
+//+kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=gatewayclasses;gateways;httproutes;grpcroutes,verbs=get;list;watch;update;patch
+//+kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=gatewayclasses/status;gateways/status;httproutes/status;grpcroutes/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=core,resources=namespaces,verbs=get;list;watch
+
+// Reconciler reconciles a Gateway object together with routes attached to it.
+// Gateway and routes are translated into an ingress group, so the balancer is built and deployed
+// by the same engine which serves ingresses.
+type Reconciler struct {
+	Loader   *k8s.GatewayLoader
+	Builder  ingress.EngineBuilder
+	Deployer ingress.Deployer
+
+	SecretsManager k8s.SecretManager
+
+	StatusUpdater      *k8s.GatewayStatusUpdater
+	FinalizerManager   *k8s.FinalizerManager
+	GroupStatusManager *k8s.GroupStatusManager
+
+	StatusResolver ingress.StatusResolver
+	SettingsLoader ingress.SettingsLoader
+
+	Scheme *runtime.Scheme
+
+	recorder record.EventRecorder
+}
+
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	rLog := log.FromContext(ctx).WithValues("name", req.NamespacedName, "kind", "Gateway")
+	rLog.Info("Gateway event detected")
+	g, err := r.doReconcile(ctx, req)
+	if g != nil {
+		errors.HandleErrorWithObject(err, g.Object, r.recorder)
+	}
+	return errors.HandleError(err, rLog, "gateway", req.NamespacedName.String())
+}
+
+func (r *Reconciler) doReconcile(ctx context.Context, req ctrl.Request) (*k8s.GatewayGroup, error) {
+	g, err := r.Loader.Load(ctx, req.NamespacedName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load gateway: %w", err)
+	}
+	if g == nil {
+		return nil, nil
+	}
+
+	if g.Deleting {
+		if !controllerutil.ContainsFinalizer(g.Object, k8s.Finalizer) {
+			return nil, nil
+		}
+		return g, r.undeploy(ctx, g)
+	}
+
+	err = r.FinalizerManager.UpdateFinalizer(ctx, g.Object, k8s.Finalizer)
+	if err != nil {
+		return g, fmt.Errorf("failed to update gateway finalizer: %w", err)
+	}
+
+	err = r.StatusUpdater.SetClassAccepted(ctx, g.Gateway.Spec.GatewayClassName)
+	if err != nil {
+		return g, fmt.Errorf("failed to set gateway class status: %w", err)
+	}
+
+	if g.Error != nil {
+		err = r.StatusUpdater.SetGatewayStatus(ctx, g, nil, nil)
+		if err != nil {
+			return g, fmt.Errorf("failed to set gateway status: %w", err)
+		}
+		return g, fmt.Errorf("gateway is not accepted: %w", g.Error)
+	}
+
+	resources, err := r.deploy(ctx, g)
+	if err != nil {
+		if statusErr := r.StatusUpdater.SetGatewayStatus(ctx, g, nil, err); statusErr != nil {
+			log.FromContext(ctx).Error(statusErr, "failed to set gateway status")
+		}
+		return g, err
+	}
+
+	albStatus := r.StatusResolver.Resolve(resources.Balancer)
+	err = r.StatusUpdater.SetGatewayStatus(ctx, g, &albStatus, nil)
+	if err != nil {
+		return g, fmt.Errorf("failed to set gateway status: %w", err)
+	}
+
+	return g, nil
+}
+
+func (r *Reconciler) deploy(ctx context.Context, g *k8s.GatewayGroup) (yc.BalancerResources, error) {
+	r.SecretsManager.ManageGroup(ctx, &g.IngressGroup)
+
+	settings, err := r.SettingsLoader.Load(ctx, &g.IngressGroup)
+	if err != nil {
+		return yc.BalancerResources{}, fmt.Errorf("failed to load group settings: %w", err)
+	}
+
+	reconcileEngine, err := r.Builder.Build(ctx, &g.IngressGroup, settings)
+	if err != nil {
+		return yc.BalancerResources{}, fmt.Errorf("failed to build group reconcile engine: %w", err)
+	}
+
+	resources, err := r.Deployer.Deploy(ctx, g.Tag, reconcileEngine)
+	if err != nil {
+		return resources, fmt.Errorf("failed to deploy group: %w", err)
+	}
+
+	err = r.setGroupStatus(ctx, g.Tag, resources)
+	if err != nil {
+		return resources, fmt.Errorf("failed to set group status: %w", err)
+	}
+
+	err = r.Deployer.UndeployOldBG(ctx, g.Tag)
+	if err != nil {
+		return resources, fmt.Errorf("failed to delete old backend groups: %w", err)
+	}
+
+	return resources, nil
+}
+
+func (r *Reconciler) undeploy(ctx context.Context, g *k8s.GatewayGroup) error {
+	empty := &k8s.IngressGroup{Tag: g.Tag}
+	r.SecretsManager.ManageGroup(ctx, empty)
+
+	reconcileEngine, err := r.Builder.Build(ctx, empty, nil)
+	if err != nil {
+		return fmt.Errorf("failed to build group reconcile engine: %w", err)
+	}
+
+	_, err = r.Deployer.Deploy(ctx, g.Tag, reconcileEngine)
+	if err != nil {
+		return fmt.Errorf("failed to undeploy group: %w", err)
+	}
+
+	err = r.GroupStatusManager.DeleteStatus(ctx, g.Tag)
+	if err != nil {
+		return fmt.Errorf("failed to delete group status: %w", err)
+	}
+
+	err = r.Deployer.UndeployOldBG(ctx, g.Tag)
+	if err != nil {
+		return fmt.Errorf("failed to delete old backend groups: %w", err)
+	}
+
+	err = r.FinalizerManager.RemoveFinalizer(ctx, g.Object, k8s.Finalizer)
+	if err != nil {
+		return fmt.Errorf("failed to remove gateway finalizer: %w", err)
+	}
+	return nil
+}
+
+func (r *Reconciler) setGroupStatus(ctx context.Context, tag string, resources yc.BalancerResources) error {
+	groupStatus, err := r.GroupStatusManager.LoadOrCreateStatus(ctx, tag)
+	if err != nil {
+		return fmt.Errorf("failed to load or create group status: %w", err)
+	}
+
+	var ids k8s.ResourcesIDs
+	if resources.Balancer != nil {
+		ids.BalancerID = resources.Balancer.Id
+	}
+	if resources.TLSRouter != nil {
+		ids.TLSRouterID = resources.TLSRouter.Id
+	}
+	if resources.Router != nil {
+		ids.RouterID = resources.Router.Id
+	}
+
+	return r.GroupStatusManager.SetBalancerResourcesIDs(ctx, groupStatus, ids)
+}
+
+// SetupWithManager sets up the controller with the manager.
+func (r *Reconciler) SetupWithManager(
+	mgr ctrl.Manager,
+	clientSet *kubernetes.Clientset,
+	secretEventChan chan event.GenericEvent,
+) error {
+	c, err := controller.New("gateway", mgr, controller.Options{
+		MaxConcurrentReconciles: 1,
+		Reconciler:              r,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create controller: %w", err)
+	}
+
+	r.recorder = mgr.GetEventRecorderFor("gateway")
+	r.SecretsManager = k8s.NewSecretManager(clientSet, secretEventChan)
+	cli := mgr.GetClient()
+
+	err = c.Watch(&source.Kind{Type: newUnstructured(k8s.GatewayGVK.Kind)}, &handler.EnqueueRequestForObject{})
+	if err != nil {
+		return fmt.Errorf("failed to watch gateways: %w", err)
+	}
+
+	for _, kind := range []string{k8s.HTTPRouteKind, k8s.GRPCRouteKind} {
+		err = c.Watch(&source.Kind{Type: newUnstructured(kind)}, handler.EnqueueRequestsFromMapFunc(routeParentsMapFn))
+		if err != nil {
+			return fmt.Errorf("failed to watch %s: %w", kind, err)
+		}
+	}
+
+	err = c.Watch(&source.Kind{Type: newUnstructured(k8s.GatewayClassGVK.Kind)}, handler.EnqueueRequestsFromMapFunc(classGatewaysMapFn(cli)))
+	if err != nil {
+		return fmt.Errorf("failed to watch gateway classes: %w", err)
+	}
+
+	err = k8s.IndexRoutes(context.Background(), mgr.GetFieldIndexer())
+	if err != nil {
+		return fmt.Errorf("failed to index routes: %w", err)
+	}
+	// unstructured objects aren't cached by the client, routes are looked up in the cache of the watches
+	err = c.Watch(&source.Kind{Type: &core.Service{}}, handler.EnqueueRequestsFromMapFunc(serviceGatewaysMapFn(mgr.GetCache(), r.recorder)))
+	if err != nil {
+		return fmt.Errorf("failed to watch services: %w", err)
+	}
+
+	return nil
+}
+
+func newUnstructured(kind string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(k8s.GatewayGVK.GroupVersion().WithKind(kind))
+	return obj
+}
+
+func routeParentsMapFn(o client.Object) []reconcile.Request {
+	refs, _, _ := unstructured.NestedSlice(o.(*unstructured.Unstructured).Object, "spec", "parentRefs")
+
+	var result []reconcile.Request
+	for _, ref := range refs {
+		m, ok := ref.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if kind, ok := m["kind"].(string); ok && kind != k8s.GatewayKind {
+			continue
+		}
+
+		nn := types.NamespacedName{Namespace: o.GetNamespace()}
+		nn.Name, _ = m["name"].(string)
+		if ns, ok := m["namespace"].(string); ok {
+			nn.Namespace = ns
+		}
+		result = append(result, reconcile.Request{NamespacedName: nn})
+	}
+	return result
+}
+
+func classGatewaysMapFn(cli client.Client) handler.MapFunc {
+	return func(o client.Object) []reconcile.Request {
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(k8s.GatewayGVK.GroupVersion().WithKind(k8s.GatewayKind + "List"))
+		if err := cli.List(context.Background(), list); err != nil {
+			return nil
+		}
+
+		var result []reconcile.Request
+		for _, gw := range list.Items {
+			className, _, _ := unstructured.NestedString(gw.Object, "spec", "gatewayClassName")
+			if className == o.GetName() {
+				result = append(result, reconcile.Request{NamespacedName: k8s.NamespacedNameOf(&gw)})
+			}
+		}
+		return result
+	}
+}
+
+// serviceGatewaysMapFn enqueues parent gateways of routes using the service
+func serviceGatewaysMapFn(reader client.Reader, recorder record.EventRecorder) handler.MapFunc {
+	return func(o client.Object) []reconcile.Request {
+		var result []reconcile.Request
+		for _, kind := range []string{k8s.HTTPRouteKind, k8s.GRPCRouteKind} {
+			list := &unstructured.UnstructuredList{}
+			list.SetGroupVersionKind(k8s.GatewayGVK.GroupVersion().WithKind(kind + "List"))
+			err := reader.List(context.Background(), list, client.InNamespace(o.GetNamespace()), client.MatchingFields{k8s.RouteServiceIndex: o.GetName()})
+			if err != nil {
+				recorder.Event(o, core.EventTypeWarning, "FailedToLoadGateways", fmt.Sprintf("failed to load routes due %v", err))
+				return nil
+			}
+
+			for i := range list.Items {
+				result = append(result, routeParentsMapFn(&list.Items[i])...)
+			}
+		}
+		return result
+	}
+}
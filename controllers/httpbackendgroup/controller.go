@@ -24,12 +24,15 @@ import (
 	"github.com/yandex-cloud/yc-alb-ingress-controller/pkg/k8s"
 	"k8s.io/client-go/tools/record"
 
+	"k8s.io/apimachinery/pkg/api/equality"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
 	albv1alpha1 "github.com/yandex-cloud/yc-alb-ingress-controller/api/v1alpha1"
 	errors2 "github.com/yandex-cloud/yc-alb-ingress-controller/controllers/errors"
@@ -79,19 +82,36 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		rLog.Info("object is being gracefully deleted")
 		err = r.HandleResourceDeleted(ctx, &bg)
 		errors2.HandleErrorWithObject(err, &bg, r.recorder)
-		return errors2.HandleError(err, rLog)
+		return errors2.HandleError(err, rLog, "httpbackendgroup", "")
 	}
 
 	rLog.Info("object has been created or updated")
 	err = r.HandleResourceUpdated(ctx, &bg)
 	errors2.HandleErrorWithObject(err, &bg, r.recorder)
-	return errors2.HandleError(err, rLog)
+	r.setConditions(ctx, &bg, err)
+	return errors2.HandleError(err, rLog, "httpbackendgroup", "")
+}
+
+// setConditions only logs errors, so they don't hide the result of reconciliation
+func (r *Reconciler) setConditions(ctx context.Context, bg *albv1alpha1.HttpBackendGroup, reconcileErr error) {
+	old := bg.DeepCopy()
+	reason, message := errors2.ConditionReason(reconcileErr)
+	k8s.SetReconcileConditions(&bg.Status.Conditions, bg.Generation, reason, message)
+	bg.Status.ObservedGeneration = bg.Generation
+	if equality.Semantic.DeepEqual(old.Status, bg.Status) {
+		return
+	}
+
+	if err := r.Status().Patch(ctx, bg, client.MergeFrom(old)); err != nil {
+		log.FromContext(ctx).Error(err, "failed to set conditions of http backend group")
+	}
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
 	r.recorder = mgr.GetEventRecorderFor(k8s.ControllerName)
+	// status updates of the controller and of the target states poller don't change generation and aren't reconciled
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&albv1alpha1.HttpBackendGroup{}).
+		For(&albv1alpha1.HttpBackendGroup{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
 		Complete(r)
 }
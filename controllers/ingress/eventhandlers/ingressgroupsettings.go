@@ -23,6 +23,10 @@ func (s SettingsEventHandler) Create(event event.CreateEvent, limitingInterface
 }
 
 func (s SettingsEventHandler) Update(event event.UpdateEvent, limitingInterface workqueue.RateLimitingInterface) {
+	// status lists groups using the settings, its updates don't change the groups
+	if event.ObjectOld.GetGeneration() == event.ObjectNew.GetGeneration() {
+		return
+	}
 	s.Common(event.ObjectNew.(*v1alpha1.IngressGroupSettings), limitingInterface)
 }
 
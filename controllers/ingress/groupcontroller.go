@@ -45,6 +45,7 @@ import (
 
 //+kubebuilder:rbac:groups=alb.yc.io,resources=ingressgroupstatuses,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=alb.yc.io,resources=ingressgroupsettings,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=alb.yc.io,resources=ingressgroupsettings/status,verbs=get;update;patch
 
 //+kubebuilder:rbac:groups=networking.k8s.io,resources=ingressclasses,verbs=get;list;watch;create;update;patch;delete
 
@@ -84,6 +85,17 @@ type GroupReconciler struct {
 	StatusResolver StatusResolver
 	SettingsLoader SettingsLoader
 
+	SettingsStatusUpdater *k8s.GroupSettingsStatusUpdater
+
+	// Adopter brings load balancers set in group settings under control of the group before they are reconciled
+	Adopter *deploy.BalancerAdopter
+
+	// Validator reports which rules of ingresses are invalid when the group fails to reconcile
+	Validator *reconcile2.Validator
+
+	// DryRun collects changes of cloud resources which are skipped by the dry-run repository into group status
+	DryRun bool
+
 	Scheme *runtime.Scheme
 
 	recorder record.EventRecorder
@@ -100,8 +112,9 @@ func (r *GroupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl
 		for _, in := range g.Deleted {
 			errors.HandleErrorWithObject(err, &in, r.recorder)
 		}
+		r.setReconcileResult(ctx, g, err)
 	}
-	return errors.HandleError(err, rLog)
+	return errors.HandleError(err, rLog, "ingressgroup", req.Name)
 }
 
 func (r *GroupReconciler) doReconcile(ctx context.Context, req ctrl.Request) (*k8s.IngressGroup, error) {
@@ -117,17 +130,40 @@ func (r *GroupReconciler) doReconcile(ctx context.Context, req ctrl.Request) (*k
 		return g, fmt.Errorf("failed to update group finalizer: %w", err)
 	}
 
+	if r.DryRun {
+		plan := &yc.Plan{}
+		ctx = yc.WithPlan(ctx, plan)
+		defer r.setPlannedChanges(ctx, g.Tag, plan)
+	}
+
 	r.SecretsManager.ManageGroup(ctx, g)
 
 	settings, err := r.SettingsLoader.Load(ctx, g)
 	if err != nil {
 		return g, fmt.Errorf("failed to load group settings: %w", err)
 	}
+	var settingsName string
+	if settings != nil {
+		settingsName = settings.Name
+	}
+	err = r.SettingsStatusUpdater.Update(ctx, g.Tag, settingsName)
+	if err != nil {
+		return g, fmt.Errorf("failed to update group settings status: %w", err)
+	}
 
 	reconcileEngine, err := r.Builder.Build(ctx, g, settings)
 	if err != nil {
 		return g, fmt.Errorf("failed to build group reconcile engine: %w", err)
 	}
+	r.recordWarnings(g, reconcileEngine)
+
+	if settings != nil && settings.LoadBalancerID != "" && len(g.Items) > 0 && reconcileEngine.Data != nil {
+		err = r.Adopter.Adopt(ctx, g.Tag, settings.LoadBalancerID, reconcileEngine.Data.Balancer)
+		if err != nil {
+			return g, fmt.Errorf("failed to adopt load balancer: %w", err)
+		}
+	}
+
 	balancerResources, err := r.Deployer.Deploy(ctx, g.Tag, reconcileEngine)
 	if err != nil {
 		return g, fmt.Errorf("failed to deploy group: %w", err)
@@ -188,6 +224,20 @@ func (r *GroupReconciler) SetupWithManager(
 	return r.setupIngressClassesWatch(c, cli, eventRecorder)
 }
 
+// recordWarnings reports ignored values of group-wide annotations as events of ingresses having them
+func (r *GroupReconciler) recordWarnings(g *k8s.IngressGroup, re *reconcile2.IngressGroupEngine) {
+	if re == nil || re.Data == nil {
+		return
+	}
+	for _, w := range re.Data.Warnings {
+		for _, item := range g.Items {
+			if item.Namespace == w.Ingress.Namespace && item.Name == w.Ingress.Name {
+				r.recorder.Event(&item, v1.EventTypeWarning, w.Reason, w.Message)
+			}
+		}
+	}
+}
+
 func (r *GroupReconciler) deleteOldBackendGroups(ctx context.Context, tag string) error {
 	return r.Deployer.UndeployOldBG(ctx, tag)
 }
@@ -210,6 +260,39 @@ func (r *GroupReconciler) removeGroupFinalizer(ctx context.Context, g *k8s.Ingre
 	return nil
 }
 
+// setPlannedChanges only logs errors, so they don't hide the result of planning
+func (r *GroupReconciler) setPlannedChanges(ctx context.Context, tag string, plan *yc.Plan) {
+	var changes []v1alpha1.PlannedChange
+	for _, c := range plan.Changes() {
+		changes = append(changes, v1alpha1.PlannedChange{Action: c.Action, ResourceType: c.ResourceType, Name: c.Name, Diff: c.Diff})
+	}
+
+	status, err := r.GroupStatusManager.LoadOrCreateStatus(ctx, tag)
+	if err == nil {
+		err = r.GroupStatusManager.SetPlannedChanges(ctx, status, changes)
+	}
+	if err != nil {
+		log.FromContext(ctx).Error(err, "failed to set planned changes of ingress group")
+	}
+}
+
+// setReconcileResult only logs errors, so they don't hide the result of reconciliation
+func (r *GroupReconciler) setReconcileResult(ctx context.Context, g *k8s.IngressGroup, reconcileErr error) {
+	// status of the group having no ingresses is deleted
+	if len(g.Items) == 0 {
+		return
+	}
+
+	reason, message := errors.ConditionReason(reconcileErr)
+	status, err := r.GroupStatusManager.LoadOrCreateStatus(ctx, g.Tag)
+	if err == nil {
+		err = r.GroupStatusManager.SetReconcileResult(ctx, status, reason, message, r.Validator.RulesStatus(g, reason))
+	}
+	if err != nil {
+		log.FromContext(ctx).Error(err, "failed to set reconcile result of ingress group")
+	}
+}
+
 func (r *GroupReconciler) setGroupStatus(ctx context.Context, g *k8s.IngressGroup, resources yc.BalancerResources) error {
 	albStatus := r.StatusResolver.Resolve(resources.Balancer)
 	for _, item := range g.Items {
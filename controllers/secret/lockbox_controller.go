@@ -0,0 +1,227 @@
+package secret
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/yandex-cloud/go-genproto/yandex/cloud/certificatemanager/v1"
+	"github.com/yandex-cloud/go-genproto/yandex/cloud/lockbox/v1"
+	v1 "k8s.io/api/core/v1"
+	networking "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	errors2 "github.com/yandex-cloud/yc-alb-ingress-controller/controllers/errors"
+	"github.com/yandex-cloud/yc-alb-ingress-controller/pkg/k8s"
+	"github.com/yandex-cloud/yc-alb-ingress-controller/pkg/metadata"
+	"github.com/yandex-cloud/yc-alb-ingress-controller/pkg/yc"
+)
+
+// LockboxController syncs certificates kept in Lockbox secrets referenced by ingresses into Certificate Manager.
+// Requests are keyed by names of the certificates. Lockbox can't be watched, so certificates following
+// the current versions of secrets are synced every poll interval.
+type LockboxController struct {
+	cli   client.Client
+	names *metadata.Names
+
+	certRepo     yc.CertRepo
+	lockboxRepo  yc.LockboxRepo
+	pollInterval time.Duration
+	expiryWindow time.Duration
+	recorder     record.EventRecorder
+}
+
+func NewLockboxController(cli client.Client, certRepo yc.CertRepo, lockboxRepo yc.LockboxRepo, names *metadata.Names,
+	pollInterval, expiryWindow time.Duration) *LockboxController {
+	return &LockboxController{
+		cli:   cli,
+		names: names,
+
+		certRepo:     certRepo,
+		lockboxRepo:  lockboxRepo,
+		pollInterval: pollInterval,
+		expiryWindow: expiryWindow,
+	}
+}
+
+func (lc *LockboxController) SetupWithManager(mgr ctrl.Manager) error {
+	// both old and new ingresses are mapped on update, so certificates no longer referenced get deleted
+	ingressMapFn := func(a client.Object) []reconcile.Request {
+		ing, ok := a.(*networking.Ingress)
+		if !ok {
+			return nil
+		}
+		refs, err := k8s.LockboxReferences(*ing)
+		if err != nil {
+			// the error is reported by ingress group controller
+			return nil
+		}
+
+		var ret []reconcile.Request
+		for _, ref := range refs {
+			ret = append(ret, reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: lc.names.LockboxCertificate(ref.SecretID, ref.VersionID)},
+			})
+		}
+		return ret
+	}
+
+	c, err := controller.New("lockbox", mgr, controller.Options{
+		MaxConcurrentReconciles: 1,
+		Reconciler:              lc,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create controller: %w", err)
+	}
+
+	lc.recorder = mgr.GetEventRecorderFor(k8s.ControllerName)
+
+	return c.Watch(&source.Kind{Type: &networking.Ingress{}}, handler.EnqueueRequestsFromMapFunc(ingressMapFn))
+}
+
+func (lc *LockboxController) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	rLog := log.FromContext(ctx).WithValues("name", req.Name, "kind", "LockboxCertificate")
+	rLog.Info("Lockbox certificate event detected")
+	follow, notAfter, err := lc.doReconcile(ctx, req.Name)
+	result, err := errors2.HandleError(err, rLog, "lockbox", "")
+	if err != nil || !result.IsZero() {
+		return result, err
+	}
+	if !notAfter.IsZero() {
+		result.RequeueAfter = k8s.NextExpiryCheck(notAfter, time.Now(), lc.expiryWindow)
+	}
+	if follow && lc.pollInterval > 0 && (result.RequeueAfter == 0 || lc.pollInterval < result.RequeueAfter) {
+		result.RequeueAfter = lc.pollInterval
+	}
+	return result, nil
+}
+
+// doReconcile reports whether the certificate follows the current version of the secret and returns
+// expiration time of the certificate served
+func (lc *LockboxController) doReconcile(ctx context.Context, certName string) (bool, time.Time, error) {
+	ref, ings, err := lc.referencingIngresses(ctx, certName)
+	if err != nil {
+		return false, time.Time{}, err
+	}
+
+	cert, err := lc.certRepo.LoadCertificate(ctx, certName)
+	if err != nil {
+		return false, time.Time{}, fmt.Errorf("failed to load certificate: %w", err)
+	}
+
+	if len(ings) == 0 {
+		if cert == nil {
+			return false, time.Time{}, nil
+		}
+		return false, time.Time{}, lc.certRepo.DeleteCertificate(ctx, cert.Id)
+	}
+
+	payload, err := lc.lockboxRepo.GetPayload(ctx, ref.SecretID, ref.VersionID)
+	if err != nil {
+		eventf(lc.recorder, ings, v1.EventTypeWarning, "LockboxSyncFailed", "Failed to sync certificate from lockbox secret %s: %s", ref.SecretID, err)
+		return false, time.Time{}, err
+	}
+	follow := ref.VersionID == ""
+	if cert != nil && cert.Labels[yc.LockboxVersionLabel] == payload.VersionId {
+		var notAfter time.Time
+		if cert.GetNotAfter() != nil {
+			notAfter = cert.GetNotAfter().AsTime()
+			warnIfExpiring(lc.recorder, ings, certName, notAfter, lc.expiryWindow)
+		}
+		return follow, notAfter, nil
+	}
+
+	notAfter, err := lc.syncCertificate(ctx, certName, cert, ref, payload)
+	if err != nil {
+		eventf(lc.recorder, ings, v1.EventTypeWarning, "LockboxSyncFailed", "Failed to sync certificate from version %s of lockbox secret %s: %s",
+			payload.VersionId, ref.SecretID, err)
+		return false, time.Time{}, err
+	}
+	eventf(lc.recorder, ings, v1.EventTypeNormal, "LockboxSynced", "Certificate %s synced from version %s of lockbox secret %s",
+		certName, payload.VersionId, ref.SecretID)
+	warnIfExpiring(lc.recorder, ings, certName, notAfter, lc.expiryWindow)
+	return follow, notAfter, nil
+}
+
+// referencingIngresses returns ingresses referencing lockbox secret version the certificate is synced from
+func (lc *LockboxController) referencingIngresses(ctx context.Context, certName string) (k8s.LockboxReference, []client.Object, error) {
+	var ingList networking.IngressList
+	if err := lc.cli.List(ctx, &ingList); err != nil {
+		return k8s.LockboxReference{}, nil, fmt.Errorf("failed to list ingresses: %w", err)
+	}
+
+	var (
+		ref  k8s.LockboxReference
+		ings []client.Object
+	)
+	for i := range ingList.Items {
+		ing := &ingList.Items[i]
+		refs, err := k8s.LockboxReferences(*ing)
+		if err != nil {
+			continue
+		}
+		for _, r := range refs {
+			if lc.names.LockboxCertificate(r.SecretID, r.VersionID) == certName {
+				ref = r
+				ings = append(ings, ing)
+				break
+			}
+		}
+	}
+	return ref, ings, nil
+}
+
+// syncCertificate returns expiration time of the certificate synced, an expired certificate doesn't replace
+// the current one which is still valid
+func (lc *LockboxController) syncCertificate(ctx context.Context, certName string, current *certificatemanager.Certificate,
+	ref k8s.LockboxReference, payload *lockbox.Payload) (time.Time, error) {
+	entries := make(map[string][]byte)
+	for _, e := range payload.Entries {
+		if e.GetBinaryValue() != nil {
+			entries[e.Key] = e.GetBinaryValue()
+		} else {
+			entries[e.Key] = []byte(e.GetTextValue())
+		}
+	}
+	if len(entries["tls.crt"]) == 0 || len(entries["tls.key"]) == 0 {
+		return time.Time{}, fmt.Errorf("secret must have tls.crt and tls.key entries")
+	}
+
+	key, err := convertKeyIfNeeded(entries["tls.key"])
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to convert key: %w", err)
+	}
+
+	notAfter, err := k8s.CertificateNotAfter(entries["tls.crt"])
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to read certificate: %w", err)
+	}
+	if current != nil {
+		if err := k8s.CheckCertificateReplacement(notAfter, current, time.Now()); err != nil {
+			return time.Time{}, err
+		}
+	}
+
+	cert := yc.Certificate{
+		ID:    current.GetId(),
+		Name:  certName,
+		Key:   key,
+		Chain: string(entries["tls.crt"]),
+		Labels: map[string]string{
+			yc.LockboxSecretLabel:  ref.SecretID,
+			yc.LockboxVersionLabel: payload.VersionId,
+		},
+	}
+	if current == nil {
+		return notAfter, lc.certRepo.CreateCertificate(ctx, cert)
+	}
+	return notAfter, lc.certRepo.UpdateCertificate(ctx, cert)
+}
@@ -6,12 +6,14 @@ import (
 	"encoding/pem"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/yandex-cloud/yc-alb-ingress-controller/pkg/k8s"
 	"k8s.io/client-go/tools/record"
 
 	"github.com/yandex-cloud/go-genproto/yandex/cloud/certificatemanager/v1"
 	v1 "k8s.io/api/core/v1"
+	networking "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -32,16 +34,20 @@ type Controller struct {
 	cli   client.Client
 	names *metadata.Names
 
-	repo     yc.CertRepo
-	recorder record.EventRecorder
+	repo         yc.CertRepo
+	expiryWindow time.Duration
+	recorder     record.EventRecorder
 }
 
-func NewController(cli client.Client, certRepo yc.CertRepo, names *metadata.Names) *Controller {
+// NewController returns controller syncing secrets into certificates, warnings are emitted for certificates
+// expiring within expiryWindow
+func NewController(cli client.Client, certRepo yc.CertRepo, names *metadata.Names, expiryWindow time.Duration) *Controller {
 	return &Controller{
 		cli:   cli,
 		names: names,
 
-		repo: certRepo,
+		repo:         certRepo,
+		expiryWindow: expiryWindow,
 	}
 }
 
@@ -73,15 +79,20 @@ func (sc *Controller) SetupWithManager(mgr ctrl.Manager, secretEventChan chan ev
 func (sc *Controller) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	rLog := log.FromContext(ctx).WithValues("name", req.NamespacedName, "kind", "Secret")
 	rLog.Info("Secret event detected")
-	secret, err := sc.doReconcile(ctx, req)
+	secret, notAfter, err := sc.doReconcile(ctx, req)
 	errors2.HandleErrorWithObject(err, secret, sc.recorder)
-	return errors2.HandleError(err, rLog)
+	result, err := errors2.HandleError(err, rLog, "secret", "")
+	if err == nil && result.IsZero() && !notAfter.IsZero() {
+		result.RequeueAfter = k8s.NextExpiryCheck(notAfter, time.Now(), sc.expiryWindow)
+	}
+	return result, err
 }
 
-func (sc *Controller) doReconcile(ctx context.Context, req reconcile.Request) (*v1.Secret, error) {
+// doReconcile returns expiration time of the certificate served for the secret
+func (sc *Controller) doReconcile(ctx context.Context, req reconcile.Request) (*v1.Secret, time.Time, error) {
 	certs, err := sc.repo.LoadCertificates(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to load certificates: %w", err)
+		return nil, time.Time{}, fmt.Errorf("failed to load certificates: %w", err)
 	}
 
 	certName := sc.names.Certificate(req.NamespacedName)
@@ -91,43 +102,107 @@ func (sc *Controller) doReconcile(ctx context.Context, req reconcile.Request) (*
 	err = sc.cli.Get(ctx, req.NamespacedName, &secret)
 	if errors.IsNotFound(err) || secret.DeletionTimestamp != nil {
 		if cert == nil {
-			return nil, nil
+			return nil, time.Time{}, nil
 		}
 
-		return nil, sc.repo.DeleteCertificate(ctx, cert.Id)
+		return nil, time.Time{}, sc.repo.DeleteCertificate(ctx, cert.Id)
 	}
 	if err != nil {
-		return &secret, fmt.Errorf("failed to get secret: %w", err)
+		return &secret, time.Time{}, fmt.Errorf("failed to get secret: %w", err)
 	}
 
 	secretKey, err := convertKeyIfNeeded(secret.Data["tls.key"])
 	if err != nil {
-		return &secret, fmt.Errorf("failed to convert key: %w", err)
+		return &secret, time.Time{}, fmt.Errorf("failed to convert key: %w", err)
+	}
+
+	notAfter, err := k8s.CertificateNotAfter(secret.Data["tls.crt"])
+	if err != nil {
+		return &secret, time.Time{}, fmt.Errorf("failed to read certificate: %w", err)
+	}
+
+	objs, err := sc.referencingObjects(ctx, &secret)
+	if err != nil {
+		return &secret, time.Time{}, err
 	}
 
 	if cert == nil {
-		return &secret, sc.repo.CreateCertificate(ctx, yc.Certificate{
+		err = sc.repo.CreateCertificate(ctx, yc.Certificate{
 			Name:  certName,
 			Key:   secretKey,
 			Chain: string(secret.Data["tls.crt"]),
 		})
+		if err != nil {
+			return &secret, time.Time{}, err
+		}
+		warnIfExpiring(sc.recorder, objs, certName, notAfter, sc.expiryWindow)
+		return &secret, notAfter, nil
 	}
 
 	certData, err := sc.repo.LoadCertificateData(ctx, cert.Id)
 	if err != nil {
-		return &secret, fmt.Errorf("failed to load certificate data: %w", err)
+		return &secret, time.Time{}, fmt.Errorf("failed to load certificate data: %w", err)
 	}
 
 	if certNeedsUpdate(secret, certData) {
-		return &secret, sc.repo.UpdateCertificate(ctx, yc.Certificate{
-			ID:    cert.Id,
-			Name:  cert.Name,
-			Key:   secretKey,
-			Chain: string(secret.Data["tls.crt"]),
-		})
+		if err := k8s.CheckCertificateReplacement(notAfter, cert, time.Now()); err != nil {
+			eventf(sc.recorder, objs, v1.EventTypeWarning, "CertificateReplacementRefused",
+				"Certificate %s is not replaced with the one from secret %s: %s", certName, secret.Name, err)
+			notAfter = cert.GetNotAfter().AsTime()
+		} else {
+			err = sc.repo.UpdateCertificate(ctx, yc.Certificate{
+				ID:    cert.Id,
+				Name:  cert.Name,
+				Key:   secretKey,
+				Chain: string(secret.Data["tls.crt"]),
+			})
+			if err != nil {
+				return &secret, time.Time{}, err
+			}
+		}
+	}
+
+	warnIfExpiring(sc.recorder, objs, certName, notAfter, sc.expiryWindow)
+	return &secret, notAfter, nil
+}
+
+// referencingObjects returns the secret and ingresses referencing it in their TLS items
+func (sc *Controller) referencingObjects(ctx context.Context, secret *v1.Secret) ([]client.Object, error) {
+	var ingList networking.IngressList
+	if err := sc.cli.List(ctx, &ingList, client.InNamespace(secret.Namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list ingresses: %w", err)
 	}
 
-	return &secret, nil
+	ret := []client.Object{secret}
+	for i := range ingList.Items {
+		for _, tls := range ingList.Items[i].Spec.TLS {
+			if tls.SecretName == secret.Name {
+				ret = append(ret, &ingList.Items[i])
+				break
+			}
+		}
+	}
+	return ret, nil
+}
+
+func warnIfExpiring(recorder record.EventRecorder, objs []client.Object, certName string, notAfter time.Time, window time.Duration) {
+	now := time.Now()
+	if !k8s.ExpiresWithin(notAfter, now, window) {
+		return
+	}
+	if !notAfter.After(now) {
+		eventf(recorder, objs, v1.EventTypeWarning, "CertificateExpired", "Certificate %s expired at %s",
+			certName, notAfter.Format(time.RFC3339))
+		return
+	}
+	eventf(recorder, objs, v1.EventTypeWarning, "CertificateExpiring", "Certificate %s expires at %s",
+		certName, notAfter.Format(time.RFC3339))
+}
+
+func eventf(recorder record.EventRecorder, objs []client.Object, eventType, reason, messageFmt string, args ...interface{}) {
+	for _, obj := range objs {
+		recorder.Eventf(obj, eventType, reason, messageFmt, args...)
+	}
 }
 
 func certNeedsUpdate(secret v1.Secret, data *certificatemanager.GetCertificateContentResponse) bool {
@@ -136,6 +211,9 @@ func certNeedsUpdate(secret v1.Secret, data *certificatemanager.GetCertificateCo
 
 func convertKeyIfNeeded(bs []byte) (string, error) {
 	block, _ := pem.Decode(bs)
+	if block == nil {
+		return "", fmt.Errorf("failed to decode private key")
+	}
 	if block.Type == "PRIVATE KEY" {
 		return string(bs), nil
 	}
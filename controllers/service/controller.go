@@ -2,18 +2,23 @@ package service
 
 import (
 	"context"
+	goerrors "errors"
 	"fmt"
 
-	"github.com/yandex-cloud/yc-alb-ingress-controller/pkg/algo"
 	ycerrors "github.com/yandex-cloud/yc-alb-ingress-controller/pkg/errors"
 	"k8s.io/client-go/tools/record"
 
 	"github.com/yandex-cloud/go-genproto/yandex/cloud/apploadbalancer/v1"
+	"google.golang.org/protobuf/proto"
 	core "k8s.io/api/core/v1"
 	discovery "k8s.io/api/discovery/v1"
 	networking "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/source"
@@ -27,20 +32,26 @@ import (
 	"github.com/yandex-cloud/yc-alb-ingress-controller/pkg/k8s"
 	"github.com/yandex-cloud/yc-alb-ingress-controller/pkg/metadata"
 	ingressreconcile "github.com/yandex-cloud/yc-alb-ingress-controller/pkg/reconcile"
+	"github.com/yandex-cloud/yc-alb-ingress-controller/pkg/yc"
 )
 
 type BackendGroupFinder interface {
 	FindTargetGroup(ctx context.Context, name string) (*apploadbalancer.TargetGroup, error)
 	FindBackendGroup(ctx context.Context, name string) (*apploadbalancer.BackendGroup, error)
 	ListSubnetsByNetworkID(ctx context.Context, id string) ([]*vpc.Subnet, error)
+	GetTargetStates(ctx context.Context, balancerID, bgID, tgID string) ([]*apploadbalancer.TargetState, error)
+	yc.BalancerRepository
 }
 
 //+kubebuilder:rbac:groups=discovery.k8s.io,resources=endpointslices,verbs=get;list;watch
 //+kubebuilder:rbac:groups=core,resources=endpoints,verbs=get;list;watch;
+//+kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch
+//+kubebuilder:rbac:groups=core,resources=pods/status,verbs=patch
 
 // Reconciler reconciles a Node object
 type Reconciler struct {
-	Repo BackendGroupFinder
+	Client client.Client
+	Repo   BackendGroupFinder
 
 	TargetGroupBuilder  *ingressreconcile.TargetGroupBuilder
 	TargetGroupDeployer *deploy.TargetGroupDeployer
@@ -57,6 +68,9 @@ type Reconciler struct {
 
 	Resolvers *builders.Resolvers
 
+	// GatewayAPI enables reconciliation of services referenced by Gateway API routes
+	GatewayAPI bool
+
 	recorder record.EventRecorder
 }
 
@@ -65,7 +79,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	rLog.Info("event detected")
 	svc, err := r.doReconcile(ctx, req)
 	errors2.HandleErrorWithObject(err, svc, r.recorder)
-	return errors2.HandleError(err, rLog)
+	return errors2.HandleError(err, rLog, "service", "")
 }
 
 func (r *Reconciler) doReconcile(ctx context.Context, req ctrl.Request) (*core.Service, error) {
@@ -102,10 +116,11 @@ func (r *Reconciler) doReconcile(ctx context.Context, req ctrl.Request) (*core.S
 			return obj, fmt.Errorf("failed to list ingresses by service: %w", err)
 		}
 
+		var deployedBGs []*apploadbalancer.BackendGroup
 		if len(ings) != 0 {
 			// Service is referenced directly by ingress, not by HttpBackendGroup or GrpcBackendGroup
 
-			bgs, err := r.BackendGroupBuilder.BuildForSvc(svc.ToReconcile, ings, tg.Id)
+			bgs, err := r.BackendGroupBuilder.BuildForSvc(ctx, svc.ToReconcile, ings, tg.Id)
 			if err != nil {
 				return obj, fmt.Errorf("failed to build backend group: %w", err)
 			}
@@ -129,7 +144,6 @@ func (r *Reconciler) doReconcile(ctx context.Context, req ctrl.Request) (*core.S
 				}
 			}
 
-			var deployedBGs []*apploadbalancer.BackendGroup
 			for _, bg := range bgs {
 				bg, err = r.BackendGroupDeployer.Deploy(ctx, bg)
 				deployedBGs = append(deployedBGs, bg)
@@ -144,6 +158,11 @@ func (r *Reconciler) doReconcile(ctx context.Context, req ctrl.Request) (*core.S
 				}
 			}
 
+			err = r.reconcileCanaryBackendGroups(ctx, svc.ToReconcile, ings, tg)
+			if err != nil {
+				return obj, fmt.Errorf("failed to reconcile canary backend groups: %w", err)
+			}
+
 			if legacyBG != nil {
 				// if legacy group doesn't match with new, delete it
 				// at this step there are no chance to rename
@@ -163,6 +182,15 @@ func (r *Reconciler) doReconcile(ctx context.Context, req ctrl.Request) (*core.S
 		if err != nil {
 			return obj, fmt.Errorf("failed to add tg id to group statuses: %w", err)
 		}
+
+		err = r.reconcileReadinessGates(ctx, svc.ToReconcile, svc.References, tg, deployedBGs)
+		if err != nil {
+			return obj, fmt.Errorf("failed to reconcile readiness gates: %w", err)
+		}
+
+		if after := r.TargetGroupDeployer.DeregistrationPending(tg.Name); after > 0 {
+			return obj, ycerrors.TargetsDeregistrationError{TargetGroup: tg.Name, RequeueAfter: after}
+		}
 		return obj, nil
 	}
 
@@ -171,7 +199,19 @@ func (r *Reconciler) doReconcile(ctx context.Context, req ctrl.Request) (*core.S
 
 		bgs := []string{r.Names.LegacyBackendGroupForSvc(req.NamespacedName)}
 		for _, port := range svc.ToDelete.Spec.Ports {
-			bgs = append(bgs, r.Names.BackendGroupForSvcPort(req.NamespacedName, int64(port.NodePort)))
+			backendPort, err := k8s.BackendPort(ctx, r.Client, svc.ToDelete, port)
+			if goerrors.As(err, &ycerrors.ResourceNotReadyError{}) {
+				// named target port isn't resolved without pods, so backend groups of the port can't be found by name
+				log.FromContext(ctx).Info("skipping backend groups of unresolved target port", "port", port.Name, "reason", err.Error())
+				continue
+			}
+			if err != nil {
+				return obj, fmt.Errorf("failed to get backend port: %w", err)
+			}
+			bgs = append(bgs,
+				r.Names.BackendGroupForSvcPort(req.NamespacedName, int64(backendPort)),
+				r.Names.CanaryBackendGroupForSvcPort(req.NamespacedName, int64(backendPort)),
+			)
 		}
 
 		for _, bgName := range bgs {
@@ -219,6 +259,159 @@ func (r *Reconciler) doReconcile(ctx context.Context, req ctrl.Request) (*core.S
 	return nil, err
 }
 
+// reconcileCanaryBackendGroups deploys backend groups splitting traffic between primary services
+// and the service used by canary ingresses. Canary backend groups of service ports which are not used
+// by canary ingresses anymore are deleted.
+func (r *Reconciler) reconcileCanaryBackendGroups(ctx context.Context, svc *core.Service, ings []networking.Ingress, tg *apploadbalancer.TargetGroup) error {
+	canaryBGs := make(map[string]*apploadbalancer.BackendGroup)
+	for _, ing := range ings {
+		annotations := ing.GetAnnotations()
+		opts, err := r.Resolvers.Canary().Resolve(annotations[k8s.Canary], annotations[k8s.CanaryWeight])
+		if err != nil {
+			return fmt.Errorf("failed to resolve canary for ingress %s/%s: %w", ing.Namespace, ing.Name, err)
+		}
+		if !opts.Enabled || opts.Weight == 0 {
+			continue
+		}
+
+		backends, err := builders.ServiceBackendsByHostAndPath(ing)
+		if err != nil {
+			return fmt.Errorf("failed to get paths of ingress %s/%s: %w", ing.Namespace, ing.Name, err)
+		}
+		for hp, backend := range backends {
+			if backend.Name != svc.Name {
+				continue
+			}
+
+			bg, err := r.buildCanaryBackendGroup(ctx, svc, ings, ing, hp, backend, tg, opts.Weight)
+			if err != nil {
+				return err
+			}
+			if existing, ok := canaryBGs[bg.Name]; ok && !proto.Equal(existing, bg) {
+				return fmt.Errorf("port %v of service %s/%s is used as canary of different services", backend.Port, svc.Namespace, svc.Name)
+			}
+			canaryBGs[bg.Name] = bg
+		}
+	}
+
+	for _, port := range svc.Spec.Ports {
+		backendPort, err := k8s.BackendPort(ctx, r.Client, svc, port)
+		if err != nil {
+			return fmt.Errorf("failed to get backend port: %w", err)
+		}
+		bgName := r.Names.CanaryBackendGroupForSvcPort(k8s.NamespacedNameOf(svc), int64(backendPort))
+		if bg, ok := canaryBGs[bgName]; ok {
+			bg, err := r.BackendGroupDeployer.Deploy(ctx, bg)
+			if err != nil {
+				return fmt.Errorf("failed to deploy canary backend group: %w", err)
+			}
+			err = r.AddBGIDToGroupStatuses(ctx, *svc, bg)
+			if err != nil {
+				return fmt.Errorf("failed to add bg id to group statuses: %w", err)
+			}
+			continue
+		}
+
+		bg, err := r.Repo.FindBackendGroup(ctx, bgName)
+		if err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("failed to find canary backend group: %w", err)
+		}
+		if err == nil && bg != nil {
+			err = r.RemoveBGIDFromGroupStatuses(ctx, *svc, bg)
+			if err != nil {
+				return fmt.Errorf("failed to remove ids from group statuses: %w", err)
+			}
+
+			_, err = r.BackendGroupDeployer.Undeploy(ctx, bgName)
+			if err != nil {
+				return fmt.Errorf("failed to undeploy canary backend group: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+func (r *Reconciler) buildCanaryBackendGroup(
+	ctx context.Context, svc *core.Service, ings []networking.Ingress, canaryIng networking.Ingress, hp builders.HostAndPath,
+	backend networking.IngressServiceBackend, tg *apploadbalancer.TargetGroup, weight int64,
+) (*apploadbalancer.BackendGroup, error) {
+	primaryBackend, err := r.findCanaryPrimary(ctx, canaryIng, hp)
+	if err != nil {
+		return nil, err
+	}
+
+	primaryName := types.NamespacedName{Namespace: canaryIng.Namespace, Name: primaryBackend.Name}
+	var primarySvc core.Service
+	err = r.Client.Get(ctx, primaryName, &primarySvc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get primary service %s: %w", primaryName, err)
+	}
+
+	primaryTG, err := r.Repo.FindTargetGroup(ctx, r.Names.TargetGroup(primaryName))
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to find target group of primary service: %w", err)
+	}
+	if primaryTG == nil {
+		return nil, ycerrors.ResourceNotReadyError{ResourceType: "TargetGroup", Name: r.Names.TargetGroup(primaryName)}
+	}
+
+	primaryIngs, err := r.IngressLoader.ListBySvc(ctx, primarySvc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ingresses by primary service: %w", err)
+	}
+
+	primaryPort, err := servicePort(&primarySvc, primaryBackend.Port)
+	if err != nil {
+		return nil, err
+	}
+	canaryPort, err := servicePort(svc, backend.Port)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.BackendGroupBuilder.BuildCanary(
+		ctx,
+		builders.CanaryBackendData{Svc: &primarySvc, Port: primaryPort, Ings: primaryIngs, TargetGroupID: primaryTG.Id},
+		builders.CanaryBackendData{Svc: svc, Port: canaryPort, Ings: ings, TargetGroupID: tg.Id},
+		weight,
+	)
+}
+
+// findCanaryPrimary finds backend of non-canary ingress of the same group and namespace serving host and path of the canary
+func (r *Reconciler) findCanaryPrimary(ctx context.Context, canaryIng networking.Ingress, hp builders.HostAndPath) (networking.IngressServiceBackend, error) {
+	tag := k8s.GetBalancerTag(&canaryIng)
+	ings, err := r.IngressLoader.List(ctx, client.InNamespace(canaryIng.Namespace), client.MatchingFields{k8s.PrimaryIngressGroupIndex: tag})
+	if err != nil {
+		return networking.IngressServiceBackend{}, fmt.Errorf("failed to list ingresses: %w", err)
+	}
+
+	for _, ing := range ings {
+		// ingresses of gateways are listed regardless of the options
+		if ing.Namespace != canaryIng.Namespace || k8s.GetBalancerTag(&ing) != tag || k8s.IsCanary(&ing) {
+			continue
+		}
+
+		backends, err := builders.ServiceBackendsByHostAndPath(ing)
+		if err != nil {
+			return networking.IngressServiceBackend{}, fmt.Errorf("failed to get paths of ingress %s/%s: %w", ing.Namespace, ing.Name, err)
+		}
+		if backend, ok := backends[hp]; ok {
+			return backend, nil
+		}
+	}
+	return networking.IngressServiceBackend{},
+		fmt.Errorf("primary ingress for canary ingress %s/%s with host %s and path %s not found", canaryIng.Namespace, canaryIng.Name, hp.Host, hp.Path)
+}
+
+func servicePort(svc *core.Service, port networking.ServiceBackendPort) (core.ServicePort, error) {
+	for _, p := range svc.Spec.Ports {
+		if p.Name == port.Name || p.Port == port.Number {
+			return p, nil
+		}
+	}
+	return core.ServicePort{}, fmt.Errorf("service %s/%s doesn't expose its port %v", svc.Namespace, svc.Name, port)
+}
+
 func getGroupNamesFromIngresses(ings []networking.Ingress) map[string]struct{} {
 	res := make(map[string]struct{})
 	for _, ing := range ings {
@@ -227,7 +420,7 @@ func getGroupNamesFromIngresses(ings []networking.Ingress) map[string]struct{} {
 	return res
 }
 
-func (r *Reconciler) getSuitableSubnets(ctx context.Context, groups map[string]k8s.IngressGroup) ([]string, error) {
+func (r *Reconciler) getSuitableSubnets(ctx context.Context, groups map[string]k8s.IngressGroup) ([]*vpc.Subnet, error) {
 	var commonNetwork string
 
 	for _, group := range groups {
@@ -255,7 +448,7 @@ func (r *Reconciler) getSuitableSubnets(ctx context.Context, groups map[string]k
 		return nil, fmt.Errorf("failed to find network: %w", err)
 	}
 
-	return algo.Map(subnets, (*vpc.Subnet).GetId), nil
+	return subnets, nil
 }
 
 func (r *Reconciler) updateGroupStatuses(
@@ -358,6 +551,11 @@ func (r *Reconciler) RemoveBGIDFromGroupStatuses(ctx context.Context, svc core.S
 
 // SetupWithManager sets up the controller with the manager.
 func (r *Reconciler) SetupWithManager(mgr ctrl.Manager, useEndpointSlices bool) error {
+	err := k8s.IndexIngresses(context.Background(), mgr.GetFieldIndexer())
+	if err != nil {
+		return fmt.Errorf("failed to index ingresses: %w", err)
+	}
+
 	c, err := controller.New("service", mgr, controller.Options{
 		MaxConcurrentReconciles: 1,
 		Reconciler:              r,
@@ -381,6 +579,16 @@ func (r *Reconciler) SetupWithManager(mgr ctrl.Manager, useEndpointSlices bool)
 		if err != nil {
 			return fmt.Errorf("failed to watch endpoints: %w", err)
 		}
+
+		err = c.Watch(&source.Kind{Type: &discovery.EndpointSlice{}}, eventhandlers.NewPodTargetsEndpointSliceEventHandler(mgr.GetLogger(), mgr.GetClient()))
+		if err != nil {
+			return fmt.Errorf("failed to watch endpoint slices: %w", err)
+		}
+	}
+
+	err = c.Watch(&source.Kind{Type: &core.Pod{}}, eventhandlers.NewPodEventHandler(mgr.GetLogger()))
+	if err != nil {
+		return fmt.Errorf("failed to watch pods: %w", err)
 	}
 
 	err = c.Watch(&source.Kind{Type: &networking.Ingress{}}, eventhandlers.NewIngressEventHandler(mgr.GetLogger(), mgr.GetClient()))
@@ -398,6 +606,22 @@ func (r *Reconciler) SetupWithManager(mgr ctrl.Manager, useEndpointSlices bool)
 		return fmt.Errorf("failed to watch grpc backend groups: %w", err)
 	}
 
+	err = c.Watch(&source.Kind{Type: &v1alpha1.StreamBackendGroup{}}, eventhandlers.NewStreamBackendGroupEventHandler(mgr.GetLogger(), mgr.GetClient()))
+	if err != nil {
+		return fmt.Errorf("failed to watch stream backend groups: %w", err)
+	}
+
+	if r.GatewayAPI {
+		for _, gvk := range []schema.GroupVersionKind{k8s.HTTPRouteGVK, k8s.GRPCRouteGVK} {
+			route := &unstructured.Unstructured{}
+			route.SetGroupVersionKind(gvk)
+			err = c.Watch(&source.Kind{Type: route}, eventhandlers.NewRouteEventHandler())
+			if err != nil {
+				return fmt.Errorf("failed to watch %s: %w", gvk.Kind, err)
+			}
+		}
+	}
+
 	r.recorder = mgr.GetEventRecorderFor(k8s.ControllerName)
 
 	return nil
@@ -0,0 +1,33 @@
+package eventhandlers
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	networking "k8s.io/api/networking/v1"
+	"k8s.io/client-go/util/workqueue"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/yandex-cloud/yc-alb-ingress-controller/pkg/k8s"
+)
+
+// enqueueCanaryServices adds services of canary ingresses of the groups to the queue. Backend groups splitting traffic
+// between primary and canary services are deployed on reconciliation of canary services, so they have to be rebuilt
+// when primary ingresses or services change
+func enqueueCanaryServices(log logr.Logger, cli client.Client, namespace string, tags map[string]struct{}, q workqueue.RateLimitingInterface) {
+	for tag := range tags {
+		var ings networking.IngressList
+		err := cli.List(context.Background(), &ings, client.InNamespace(namespace), client.MatchingFields{k8s.CanaryIngressGroupIndex: tag})
+		if err != nil {
+			log.Error(err, "failed to list canary ingresses", "namespace", namespace, "group", tag)
+			continue
+		}
+
+		for i := range ings.Items {
+			for svc := range parseServicesFromIngress(&ings.Items[i]) {
+				q.Add(ctrl.Request{NamespacedName: svc})
+			}
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package eventhandlers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	core "k8s.io/api/core/v1"
+	networking "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/util/workqueue"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	"github.com/yandex-cloud/yc-alb-ingress-controller/pkg/k8s"
+)
+
+type builderIndexer struct {
+	b *fake.ClientBuilder
+}
+
+func (i builderIndexer) IndexField(_ context.Context, obj client.Object, field string, extract client.IndexerFunc) error {
+	i.b.WithIndex(obj, field, extract)
+	return nil
+}
+
+func ingressWithBackend(name, svc string, annotations map[string]string) *networking.Ingress {
+	return &networking.Ingress{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name, Annotations: annotations},
+		Spec: networking.IngressSpec{Rules: []networking.IngressRule{{
+			Host: "example.com",
+			IngressRuleValue: networking.IngressRuleValue{HTTP: &networking.HTTPIngressRuleValue{
+				Paths: []networking.HTTPIngressPath{{
+					Path:    "/",
+					Backend: networking.IngressBackend{Service: &networking.IngressServiceBackend{Name: svc}},
+				}},
+			}},
+		}}},
+	}
+}
+
+func queued(q workqueue.RateLimitingInterface) map[types.NamespacedName]struct{} {
+	ret := make(map[types.NamespacedName]struct{})
+	for q.Len() > 0 {
+		item, _ := q.Get()
+		ret[item.(ctrl.Request).NamespacedName] = struct{}{}
+		q.Done(item)
+	}
+	return ret
+}
+
+func TestCanaryServicesEnqueued(t *testing.T) {
+	primary := ingressWithBackend("primary", "primary-svc", map[string]string{k8s.AlbTag: "tag"})
+	canary := ingressWithBackend("canary", "canary-svc", map[string]string{k8s.AlbTag: "tag", k8s.Canary: "true", k8s.CanaryWeight: "10"})
+	otherGroup := ingressWithBackend("other", "other-svc", map[string]string{k8s.AlbTag: "other-tag", k8s.Canary: "true"})
+	primarySvc := &core.Service{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "primary-svc"}}
+
+	b := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(primary, canary, otherGroup, primarySvc)
+	require.NoError(t, k8s.IndexIngresses(context.Background(), builderIndexer{b: b}))
+	cli := b.Build()
+
+	name := func(svc string) types.NamespacedName { return types.NamespacedName{Namespace: "default", Name: svc} }
+	set := func(names ...types.NamespacedName) map[types.NamespacedName]struct{} {
+		ret := make(map[types.NamespacedName]struct{})
+		for _, n := range names {
+			ret[n] = struct{}{}
+		}
+		return ret
+	}
+
+	t.Run("primary service changed", func(t *testing.T) {
+		q := workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+		changed := primarySvc.DeepCopy()
+		changed.Spec.Ports = []core.ServicePort{{Port: 80}}
+		NewServiceEventHandler(logr.Discard(), cli).Update(event.UpdateEvent{ObjectOld: primarySvc, ObjectNew: changed}, q)
+		assert.Equal(t, set(name("primary-svc"), name("canary-svc")), queued(q))
+	})
+
+	t.Run("primary service status changed", func(t *testing.T) {
+		q := workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+		changed := primarySvc.DeepCopy()
+		changed.Finalizers = []string{k8s.Finalizer}
+		NewServiceEventHandler(logr.Discard(), cli).Update(event.UpdateEvent{ObjectOld: primarySvc, ObjectNew: changed}, q)
+		assert.Equal(t, set(name("primary-svc")), queued(q))
+	})
+
+	t.Run("primary ingress changed", func(t *testing.T) {
+		q := workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+		changed := primary.DeepCopy()
+		changed.Spec.Rules[0].HTTP.Paths[0].Path = "/api"
+		NewIngressEventHandler(logr.Discard(), cli).Update(event.UpdateEvent{ObjectOld: primary, ObjectNew: changed}, q)
+		assert.Equal(t, set(name("canary-svc")), queued(q))
+	})
+
+	t.Run("canary weight changed", func(t *testing.T) {
+		q := workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+		changed := canary.DeepCopy()
+		changed.Annotations[k8s.CanaryWeight] = "50"
+		NewIngressEventHandler(logr.Discard(), cli).Update(event.UpdateEvent{ObjectOld: canary, ObjectNew: changed}, q)
+		assert.Equal(t, set(name("canary-svc")), queued(q))
+	})
+
+	t.Run("primary ingress deleted", func(t *testing.T) {
+		q := workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+		NewIngressEventHandler(logr.Discard(), cli).Delete(event.DeleteEvent{Object: primary}, q)
+		assert.Equal(t, set(name("primary-svc"), name("canary-svc")), queued(q))
+	})
+}
@@ -1,18 +1,27 @@
 package eventhandlers
 
 import (
+	"context"
+
 	"github.com/go-logr/logr"
+	core "k8s.io/api/core/v1"
 	discovery "k8s.io/api/discovery/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/util/workqueue"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	"github.com/yandex-cloud/yc-alb-ingress-controller/pkg/k8s"
 )
 
 type EndpointSliceEventHandler struct {
 	Log logr.Logger
 	cli client.Client
+
+	// podTargetsOnly makes handler skip slices of services which don't use pod IP targets
+	podTargetsOnly bool
 }
 
 func (s EndpointSliceEventHandler) Create(event event.CreateEvent, q workqueue.RateLimitingInterface) {
@@ -57,9 +66,29 @@ func (s EndpointSliceEventHandler) Common(sl *discovery.EndpointSlice, q workque
 		Namespace: sl.Namespace,
 	}
 
+	if s.podTargetsOnly {
+		var svc core.Service
+		err := s.cli.Get(context.Background(), name, &svc)
+		if err != nil {
+			if !errors.IsNotFound(err) {
+				s.Log.Error(err, "failed to get service of endpoint slice", "namespace", sl.Namespace, "name", sl.Name)
+			}
+			return
+		}
+		if podTargets, _ := k8s.PodIPTargets(&svc); !podTargets {
+			return
+		}
+	}
+
 	q.Add(ctrl.Request{NamespacedName: name})
 }
 
 func NewEndpointSliceEventHandler(logger logr.Logger, cli client.Client) *EndpointSliceEventHandler {
 	return &EndpointSliceEventHandler{Log: logger, cli: cli}
 }
+
+// NewPodTargetsEndpointSliceEventHandler returns handler of endpoint slices of services using pod IP targets,
+// which are built from endpoint slices even if endpoints are watched for other services
+func NewPodTargetsEndpointSliceEventHandler(logger logr.Logger, cli client.Client) *EndpointSliceEventHandler {
+	return &EndpointSliceEventHandler{Log: logger, cli: cli, podTargetsOnly: true}
+}
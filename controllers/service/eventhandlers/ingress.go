@@ -3,6 +3,7 @@ package eventhandlers
 import (
 	"github.com/go-logr/logr"
 	networking "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/util/workqueue"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -10,6 +11,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/event"
 
 	"github.com/yandex-cloud/yc-alb-ingress-controller/pkg/algo"
+	"github.com/yandex-cloud/yc-alb-ingress-controller/pkg/k8s"
 )
 
 type IngressEventHandler struct {
@@ -32,14 +34,30 @@ func (s IngressEventHandler) Update(event event.UpdateEvent, q workqueue.RateLim
 		"name", event.ObjectNew.GetName()).
 		Info("Service update event detected")
 
-	oldServices := parseServicesFromIngress(event.ObjectOld.(*networking.Ingress))
-	newServices := parseServicesFromIngress(event.ObjectNew.(*networking.Ingress))
+	oldIng, newIng := event.ObjectOld.(*networking.Ingress), event.ObjectNew.(*networking.Ingress)
+	oldServices := parseServicesFromIngress(oldIng)
+	newServices := parseServicesFromIngress(newIng)
 
 	// trigger only inserted or removed services
 	toUpdate := algo.SetsExceptUnion(oldServices, newServices)
 	for svc := range toUpdate {
 		q.Add(ctrl.Request{NamespacedName: svc})
 	}
+
+	if equality.Semantic.DeepEqual(oldIng.Spec, newIng.Spec) && equality.Semantic.DeepEqual(oldIng.Annotations, newIng.Annotations) {
+		return
+	}
+	// canary backend groups depend on paths of primary ingresses and on weights of canary ones
+	if k8s.IsCanary(oldIng) || k8s.IsCanary(newIng) {
+		for _, svcs := range []map[types.NamespacedName]struct{}{oldServices, newServices} {
+			for svc := range svcs {
+				q.Add(ctrl.Request{NamespacedName: svc})
+			}
+		}
+		return
+	}
+	tags := map[string]struct{}{k8s.GetBalancerTag(oldIng): {}, k8s.GetBalancerTag(newIng): {}}
+	enqueueCanaryServices(s.Log, s.cli, newIng.Namespace, tags, q)
 }
 
 func (s IngressEventHandler) Delete(event event.DeleteEvent, q workqueue.RateLimitingInterface) {
@@ -65,6 +83,10 @@ func (s IngressEventHandler) Common(ing *networking.Ingress, q workqueue.RateLim
 	for svc := range svcs {
 		q.Add(ctrl.Request{NamespacedName: svc})
 	}
+
+	if !k8s.IsCanary(ing) {
+		enqueueCanaryServices(s.Log, s.cli, ing.Namespace, map[string]struct{}{k8s.GetBalancerTag(ing): {}}, q)
+	}
 }
 
 func NewIngressEventHandler(logger logr.Logger, cli client.Client) *IngressEventHandler {
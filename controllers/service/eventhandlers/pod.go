@@ -0,0 +1,49 @@
+package eventhandlers
+
+import (
+	"github.com/go-logr/logr"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	"github.com/yandex-cloud/yc-alb-ingress-controller/pkg/k8s"
+)
+
+// PodEventHandler enqueues services awaited by readiness gates of pods, pods without such gates are ignored
+type PodEventHandler struct {
+	log logr.Logger
+}
+
+func (s PodEventHandler) Create(event event.CreateEvent, q workqueue.RateLimitingInterface) {
+	s.Common(event.Object.(*v1.Pod), q)
+}
+
+func (s PodEventHandler) Update(event event.UpdateEvent, q workqueue.RateLimitingInterface) {
+	s.Common(event.ObjectNew.(*v1.Pod), q)
+}
+
+func (s PodEventHandler) Delete(event event.DeleteEvent, q workqueue.RateLimitingInterface) {
+	s.Common(event.Object.(*v1.Pod), q)
+}
+
+func (s PodEventHandler) Generic(event event.GenericEvent, q workqueue.RateLimitingInterface) {
+	s.Common(event.Object.(*v1.Pod), q)
+}
+
+func (s PodEventHandler) Common(pod *v1.Pod, q workqueue.RateLimitingInterface) {
+	for _, svcName := range k8s.ReadinessGateServices(pod) {
+		s.log.WithValues(
+			"namespace", pod.Namespace,
+			"name", pod.Name,
+			"service", svcName).
+			Info("Pod with readiness gate event detected")
+
+		q.Add(ctrl.Request{NamespacedName: types.NamespacedName{Namespace: pod.Namespace, Name: svcName}})
+	}
+}
+
+func NewPodEventHandler(logger logr.Logger) *PodEventHandler {
+	return &PodEventHandler{log: logger}
+}
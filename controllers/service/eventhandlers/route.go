@@ -0,0 +1,57 @@
+package eventhandlers
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// NewRouteEventHandler returns handler of Gateway API routes events, which enqueues services referenced by the route.
+// On update both old and new routes are mapped, so services removed from the route are reconciled too.
+func NewRouteEventHandler() handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(func(o client.Object) []reconcile.Request {
+		var result []reconcile.Request
+		for svc := range parseServicesFromRoute(o.(*unstructured.Unstructured)) {
+			result = append(result, reconcile.Request{NamespacedName: svc})
+		}
+		return result
+	})
+}
+
+func parseServicesFromRoute(route *unstructured.Unstructured) map[types.NamespacedName]struct{} {
+	result := make(map[types.NamespacedName]struct{})
+
+	rules, _, _ := unstructured.NestedSlice(route.Object, "spec", "rules")
+	for _, rule := range rules {
+		r, ok := rule.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		refs, _, _ := unstructured.NestedSlice(r, "backendRefs")
+		for _, ref := range refs {
+			m, ok := ref.(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			if kind, ok := m["kind"].(string); ok && kind != "Service" {
+				continue
+			}
+			if group, ok := m["group"].(string); ok && group != "" {
+				continue
+			}
+
+			svc := types.NamespacedName{Namespace: route.GetNamespace()}
+			svc.Name, _ = m["name"].(string)
+			if ns, ok := m["namespace"].(string); ok {
+				svc.Namespace = ns
+			}
+			result[svc] = struct{}{}
+		}
+	}
+
+	return result
+}
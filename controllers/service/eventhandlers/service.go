@@ -1,8 +1,12 @@
 package eventhandlers
 
 import (
+	"context"
+
 	"github.com/go-logr/logr"
 	v1 "k8s.io/api/core/v1"
+	networking "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/client-go/util/workqueue"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -31,7 +35,11 @@ func (s ServiceEventHandler) Update(event event.UpdateEvent, q workqueue.RateLim
 		"name", event.ObjectNew.GetName()).
 		Info("Service update event detected")
 
-	s.Common(event.ObjectNew.(*v1.Service), q)
+	oldSvc, newSvc := event.ObjectOld.(*v1.Service), event.ObjectNew.(*v1.Service)
+	q.Add(ctrl.Request{NamespacedName: k8s.NamespacedNameOf(newSvc)})
+	if !equality.Semantic.DeepEqual(oldSvc.Spec, newSvc.Spec) {
+		s.enqueueCanaries(newSvc, q)
+	}
 }
 
 func (s ServiceEventHandler) Delete(event event.DeleteEvent, q workqueue.RateLimitingInterface) {
@@ -54,6 +62,25 @@ func (s ServiceEventHandler) Generic(event event.GenericEvent, q workqueue.RateL
 
 func (s ServiceEventHandler) Common(svc *v1.Service, q workqueue.RateLimitingInterface) {
 	q.Add(ctrl.Request{NamespacedName: k8s.NamespacedNameOf(svc)})
+	s.enqueueCanaries(svc, q)
+}
+
+// enqueueCanaries adds services of canary ingresses for which the service is primary
+func (s ServiceEventHandler) enqueueCanaries(svc *v1.Service, q workqueue.RateLimitingInterface) {
+	var ings networking.IngressList
+	err := s.cli.List(context.Background(), &ings, client.InNamespace(svc.Namespace), client.MatchingFields{k8s.IngressServiceIndex: svc.Name})
+	if err != nil {
+		s.log.Error(err, "failed to list ingresses of service", "namespace", svc.Namespace, "name", svc.Name)
+		return
+	}
+
+	tags := make(map[string]struct{})
+	for i := range ings.Items {
+		if !k8s.IsCanary(&ings.Items[i]) {
+			tags[k8s.GetBalancerTag(&ings.Items[i])] = struct{}{}
+		}
+	}
+	enqueueCanaryServices(s.log, s.cli, svc.Namespace, tags, q)
 }
 
 func NewServiceEventHandler(logger logr.Logger, cli client.Client) *ServiceEventHandler {
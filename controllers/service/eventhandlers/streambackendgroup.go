@@ -0,0 +1,89 @@
+package eventhandlers
+
+import (
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	"github.com/yandex-cloud/yc-alb-ingress-controller/api/v1alpha1"
+	"github.com/yandex-cloud/yc-alb-ingress-controller/pkg/algo"
+)
+
+type StreamBackendGroupEventHandler struct {
+	Log logr.Logger
+	cli client.Client
+}
+
+func (s StreamBackendGroupEventHandler) Create(event event.CreateEvent, q workqueue.RateLimitingInterface) {
+	s.Log.WithValues(
+		"namespace", event.Object.GetNamespace(),
+		"name", event.Object.GetName()).
+		Info("Service create event detected")
+
+	s.Common(event.Object.(*v1alpha1.StreamBackendGroup), q)
+}
+
+func (s StreamBackendGroupEventHandler) Update(event event.UpdateEvent, q workqueue.RateLimitingInterface) {
+	s.Log.WithValues(
+		"namespace", event.ObjectNew.GetNamespace(),
+		"name", event.ObjectNew.GetName()).
+		Info("Service update event detected")
+
+	oldServices := parseServicesFromStreamBG(event.ObjectOld.(*v1alpha1.StreamBackendGroup))
+	newServices := parseServicesFromStreamBG(event.ObjectNew.(*v1alpha1.StreamBackendGroup))
+
+	// trigger only inserted or removed services
+	toUpdate := algo.SetsExceptUnion(oldServices, newServices)
+	for svc := range toUpdate {
+		q.Add(ctrl.Request{NamespacedName: svc})
+	}
+}
+
+func (s StreamBackendGroupEventHandler) Delete(event event.DeleteEvent, q workqueue.RateLimitingInterface) {
+	s.Log.WithValues(
+		"namespace", event.Object.GetNamespace(),
+		"name", event.Object.GetName()).
+		Info("Service delete event detected")
+
+	s.Common(event.Object.(*v1alpha1.StreamBackendGroup), q)
+}
+
+func (s StreamBackendGroupEventHandler) Generic(event event.GenericEvent, q workqueue.RateLimitingInterface) {
+	s.Log.WithValues(
+		"namespace", event.Object.GetNamespace(),
+		"name", event.Object.GetName()).
+		Info("Generic node event detected")
+
+	s.Common(event.Object.(*v1alpha1.StreamBackendGroup), q)
+}
+
+func (s StreamBackendGroupEventHandler) Common(ing *v1alpha1.StreamBackendGroup, q workqueue.RateLimitingInterface) {
+	svcs := parseServicesFromStreamBG(ing)
+	for svc := range svcs {
+		q.Add(ctrl.Request{NamespacedName: svc})
+	}
+}
+
+func NewStreamBackendGroupEventHandler(logger logr.Logger, cli client.Client) *StreamBackendGroupEventHandler {
+	return &StreamBackendGroupEventHandler{Log: logger, cli: cli}
+}
+
+func parseServicesFromStreamBG(ing *v1alpha1.StreamBackendGroup) map[types.NamespacedName]struct{} {
+	result := make(map[types.NamespacedName]struct{})
+
+	for _, be := range ing.Spec.Backends {
+		if be.Service == nil {
+			continue
+		}
+
+		result[types.NamespacedName{
+			Name:      be.Service.Name,
+			Namespace: ing.Namespace,
+		}] = struct{}{}
+	}
+
+	return result
+}
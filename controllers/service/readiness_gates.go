@@ -0,0 +1,225 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/yandex-cloud/go-genproto/yandex/cloud/apploadbalancer/v1"
+	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ycerrors "github.com/yandex-cloud/yc-alb-ingress-controller/pkg/errors"
+	"github.com/yandex-cloud/yc-alb-ingress-controller/pkg/k8s"
+	"github.com/yandex-cloud/yc-alb-ingress-controller/pkg/yc"
+)
+
+// reconcileReadinessGates sets conditions of readiness gates of the service pods whose targets are healthy in all
+// backend groups of the service on the balancers using them. Reconciliation is requeued while some pods are still waiting.
+// Backend groups of the service are the ones built for ingresses and the ones of backend group resources used by the
+// groups referencing the service. Gates of services having no backend groups on balancers aren't managed.
+func (r *Reconciler) reconcileReadinessGates(
+	ctx context.Context, svc *core.Service, refs map[string]k8s.IngressGroup, tg *apploadbalancer.TargetGroup, bgs []*apploadbalancer.BackendGroup,
+) error {
+	if len(svc.Spec.Selector) == 0 {
+		return nil
+	}
+
+	var pods core.PodList
+	err := r.Client.List(ctx, &pods, client.InNamespace(svc.Namespace), client.MatchingLabels(svc.Spec.Selector))
+	if err != nil {
+		return fmt.Errorf("failed to list pods: %w", err)
+	}
+
+	var waiting []*core.Pod
+	for i := range pods.Items {
+		if k8s.WaitsForTargetHealth(&pods.Items[i], svc.Name) {
+			waiting = append(waiting, &pods.Items[i])
+		}
+	}
+	if len(waiting) == 0 {
+		return nil
+	}
+
+	podTargets, err := k8s.PodIPTargets(svc)
+	if err != nil {
+		return err
+	}
+	if !podTargets {
+		// node targets don't tell anything about health of particular pods
+		r.recorder.Eventf(svc, core.EventTypeWarning, "ReadinessGateNotSupported",
+			"Readiness gate %s of pods is supported only with %s target mode", k8s.ReadinessGateConditionType(svc.Name), k8s.TargetModePodIP)
+		return nil
+	}
+
+	crBGs, err := r.backendGroupsOfResources(ctx, svc, refs)
+	if err != nil {
+		return err
+	}
+	bgs = append(bgs, crBGs...)
+	if len(bgs) == 0 {
+		// health of targets is known only for backend groups of balancers, pods mustn't wait for it forever
+		r.recorder.Eventf(svc, core.EventTypeWarning, "ReadinessGateNotManaged",
+			"Readiness gate %s of pods is set without target health, no backend group of application load balancers uses the service",
+			k8s.ReadinessGateConditionType(svc.Name))
+		for _, pod := range waiting {
+			err = r.setReadinessGateCondition(ctx, pod, svc.Name, "BackendGroupsNotFound",
+				"Service isn't used by backend groups of application load balancers")
+			if err != nil {
+				return fmt.Errorf("failed to set readiness gate condition of pod %s/%s: %w", pod.Namespace, pod.Name, err)
+			}
+		}
+		return nil
+	}
+
+	healthy, err := r.healthyTargets(ctx, refs, tg, bgs)
+	if err != nil {
+		return fmt.Errorf("failed to get healthy targets: %w", err)
+	}
+
+	pending := 0
+	for _, pod := range waiting {
+		if _, ok := healthy[pod.Status.PodIP]; !ok {
+			pending++
+			continue
+		}
+
+		err = r.setReadinessGateCondition(ctx, pod, svc.Name, "TargetHealthy",
+			"Target is healthy in all zones of application load balancers")
+		if err != nil {
+			return fmt.Errorf("failed to set readiness gate condition of pod %s/%s: %w", pod.Namespace, pod.Name, err)
+		}
+	}
+
+	if pending > 0 {
+		return ycerrors.ResourceNotReadyError{
+			ResourceType: "TargetHealth",
+			Name:         fmt.Sprintf("%d pods of service %s/%s", pending, svc.Namespace, svc.Name),
+		}
+	}
+	return nil
+}
+
+// backendGroupsOfResources returns backend groups deployed for HttpBackendGroup, GrpcBackendGroup and StreamBackendGroup
+// resources having backends of the service, which are used by ingress groups referencing the service
+func (r *Reconciler) backendGroupsOfResources(ctx context.Context, svc *core.Service, refs map[string]k8s.IngressGroup) ([]*apploadbalancer.BackendGroup, error) {
+	names, err := k8s.BackendGroupsOfService(ctx, r.Client, *svc, refs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get backend group resources of service: %w", err)
+	}
+
+	var ret []*apploadbalancer.BackendGroup
+	for _, name := range names {
+		bgName := r.Names.BackendGroupForCR(name.Namespace, name.Name)
+		bg, err := r.Repo.FindBackendGroup(ctx, bgName)
+		if err != nil && !errors.IsNotFound(err) {
+			return nil, fmt.Errorf("failed to find backend group %s: %w", bgName, err)
+		}
+		if bg == nil {
+			return nil, ycerrors.ResourceNotReadyError{ResourceType: "BackendGroup", Name: bgName}
+		}
+		ret = append(ret, bg)
+	}
+	return ret, nil
+}
+
+// healthyTargets returns IPs of targets of the target group which are healthy in every zone for the backend groups
+// on balancers of ingress groups using the service. Each balancer is checked only for the backend groups its routes
+// and listeners reference, since groups may use different ports or backend group resources of the service.
+func (r *Reconciler) healthyTargets(
+	ctx context.Context, refs map[string]k8s.IngressGroup, tg *apploadbalancer.TargetGroup, bgs []*apploadbalancer.BackendGroup,
+) (map[string]struct{}, error) {
+	var balancerIDs []string
+	for group := range refs {
+		status, err := r.GroupStatusManager.LoadStatus(ctx, group)
+		if errors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to load group status: %w", err)
+		}
+		if status.LoadBalancerID != "" {
+			balancerIDs = append(balancerIDs, status.LoadBalancerID)
+		}
+	}
+
+	healthyCount := make(map[string]int)
+	attached := 0
+	for _, balancerID := range balancerIDs {
+		bgIDs, err := yc.BalancerBackendGroupIDs(ctx, r.Repo, balancerID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get backend groups of balancer: %w", err)
+		}
+		used := make(map[string]struct{}, len(bgIDs))
+		for _, id := range bgIDs {
+			used[id] = struct{}{}
+		}
+
+		for _, bg := range bgs {
+			if _, ok := used[bg.Id]; !ok {
+				continue
+			}
+			attached++
+
+			states, err := r.Repo.GetTargetStates(ctx, balancerID, bg.Id, tg.Id)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get target states of backend group %s: %w", bg.Name, err)
+			}
+			for _, state := range states {
+				if targetHealthy(state) {
+					healthyCount[state.GetTarget().GetIpAddress()]++
+				}
+			}
+		}
+	}
+	if attached == 0 {
+		// backend groups aren't attached to balancers yet
+		return nil, nil
+	}
+
+	ret := make(map[string]struct{})
+	for ip, count := range healthyCount {
+		if count == attached {
+			ret[ip] = struct{}{}
+		}
+	}
+	return ret, nil
+}
+
+func targetHealthy(state *apploadbalancer.TargetState) bool {
+	zones := state.GetStatus().GetZoneStatuses()
+	if len(zones) == 0 {
+		return false
+	}
+	for _, zone := range zones {
+		if zone.GetStatus() != apploadbalancer.TargetState_HEALTHY {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *Reconciler) setReadinessGateCondition(ctx context.Context, pod *core.Pod, svcName, reason, message string) error {
+	patch := client.StrategicMergeFrom(pod.DeepCopy())
+
+	cond := core.PodCondition{
+		Type:               k8s.ReadinessGateConditionType(svcName),
+		Status:             core.ConditionTrue,
+		Reason:             reason,
+		Message:            message,
+		LastTransitionTime: metav1.Now(),
+	}
+	found := false
+	for i := range pod.Status.Conditions {
+		if pod.Status.Conditions[i].Type == cond.Type {
+			pod.Status.Conditions[i] = cond
+			found = true
+		}
+	}
+	if !found {
+		pod.Status.Conditions = append(pod.Status.Conditions, cond)
+	}
+
+	return r.Client.Status().Patch(ctx, pod, patch)
+}
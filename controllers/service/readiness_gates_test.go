@@ -0,0 +1,217 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yandex-cloud/go-genproto/yandex/cloud/apploadbalancer/v1"
+	"github.com/yandex-cloud/go-genproto/yandex/cloud/vpc/v1"
+	core "k8s.io/api/core/v1"
+	networking "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/yandex-cloud/yc-alb-ingress-controller/api/v1alpha1"
+	ycerrors "github.com/yandex-cloud/yc-alb-ingress-controller/pkg/errors"
+	"github.com/yandex-cloud/yc-alb-ingress-controller/pkg/k8s"
+	"github.com/yandex-cloud/yc-alb-ingress-controller/pkg/metadata"
+)
+
+type fakeBackendGroupFinder struct {
+	backendGroups map[string]*apploadbalancer.BackendGroup
+	targetStates  map[string][]*apploadbalancer.TargetState
+	// balancerBGs are IDs of backend groups attached to balancers by their stream listeners
+	balancerBGs map[string][]string
+}
+
+func (f *fakeBackendGroupFinder) GetLoadBalancer(_ context.Context, id string) (*apploadbalancer.LoadBalancer, error) {
+	ret := &apploadbalancer.LoadBalancer{Id: id}
+	for _, bgID := range f.balancerBGs[id] {
+		ret.Listeners = append(ret.Listeners, &apploadbalancer.Listener{
+			Listener: &apploadbalancer.Listener_Stream{Stream: &apploadbalancer.StreamListener{
+				Handler: &apploadbalancer.StreamHandler{BackendGroupId: bgID},
+			}},
+		})
+	}
+	return ret, nil
+}
+
+func (f *fakeBackendGroupFinder) GetHTTPRouter(_ context.Context, id string) (*apploadbalancer.HttpRouter, error) {
+	return &apploadbalancer.HttpRouter{Id: id}, nil
+}
+
+func (f *fakeBackendGroupFinder) FindTargetGroup(context.Context, string) (*apploadbalancer.TargetGroup, error) {
+	return nil, nil
+}
+
+func (f *fakeBackendGroupFinder) FindBackendGroup(_ context.Context, name string) (*apploadbalancer.BackendGroup, error) {
+	return f.backendGroups[name], nil
+}
+
+func (f *fakeBackendGroupFinder) ListSubnetsByNetworkID(context.Context, string) ([]*vpc.Subnet, error) {
+	return nil, nil
+}
+
+func (f *fakeBackendGroupFinder) GetTargetStates(_ context.Context, balancerID, bgID, _ string) ([]*apploadbalancer.TargetState, error) {
+	return f.targetStates[balancerID+"/"+bgID], nil
+}
+
+func TestReconciler_ReconcileReadinessGates(t *testing.T) {
+	require.NoError(t, v1alpha1.AddToScheme(scheme.Scheme))
+	names := &metadata.Names{ClusterID: "cluster"}
+
+	svc := &core.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "default",
+			Name:        "svc",
+			Annotations: map[string]string{k8s.TargetMode: k8s.TargetModePodIP},
+		},
+		Spec: core.ServiceSpec{Selector: map[string]string{"app": "web"}},
+	}
+	pod := &core.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pod", Labels: map[string]string{"app": "web"}},
+		Spec:       core.PodSpec{ReadinessGates: []core.PodReadinessGate{{ConditionType: k8s.ReadinessGateConditionType("svc")}}},
+		Status: core.PodStatus{
+			PodIP:      "10.0.0.1",
+			Conditions: []core.PodCondition{{Type: core.ContainersReady, Status: core.ConditionTrue}},
+		},
+	}
+	// the service is used by the ingress only through the backend group resource
+	bgCR := &v1alpha1.HttpBackendGroup{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "bg"},
+		Spec: v1alpha1.HttpBackendGroupSpec{Backends: []*v1alpha1.HttpBackend{
+			{Name: "svc", Service: &v1alpha1.ServiceBackend{Name: "svc", Port: v1alpha1.ServiceBackendPort{Number: 80}}},
+		}},
+	}
+	ing := networking.Ingress{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "ing", Annotations: map[string]string{k8s.AlbTag: "tag"}},
+		Spec: networking.IngressSpec{DefaultBackend: &networking.IngressBackend{
+			Resource: &core.TypedLocalObjectReference{Kind: "HttpBackendGroup", Name: "bg"},
+		}},
+	}
+	status := &v1alpha1.IngressGroupStatus{ObjectMeta: metav1.ObjectMeta{Name: "tag"}, LoadBalancerID: "alb"}
+	refs := map[string]k8s.IngressGroup{"tag": {Tag: "tag", Items: []networking.Ingress{ing}}}
+	// groups use different ports of the service, each balancer has its own backend group of the service
+	svcIngress := func(group string, port int32) networking.Ingress {
+		return networking.Ingress{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: group, Annotations: map[string]string{k8s.AlbTag: group}},
+			Spec: networking.IngressSpec{DefaultBackend: &networking.IngressBackend{
+				Service: &networking.IngressServiceBackend{Name: "svc", Port: networking.ServiceBackendPort{Number: port}},
+			}},
+		}
+	}
+	otherStatus := &v1alpha1.IngressGroupStatus{ObjectMeta: metav1.ObjectMeta{Name: "other"}, LoadBalancerID: "alb-other"}
+	twoGroupRefs := map[string]k8s.IngressGroup{
+		"tag":   {Tag: "tag", Items: []networking.Ingress{svcIngress("tag", 80)}},
+		"other": {Tag: "other", Items: []networking.Ingress{svcIngress("other", 8080)}},
+	}
+	portBGs := []*apploadbalancer.BackendGroup{{Id: "bg-80"}, {Id: "bg-8080"}}
+	tg := &apploadbalancer.TargetGroup{Id: "tg"}
+	healthy := []*apploadbalancer.TargetState{{
+		Target: &apploadbalancer.Target{AddressType: &apploadbalancer.Target_IpAddress{IpAddress: "10.0.0.1"}},
+		Status: &apploadbalancer.TargetState_HealthcheckStatus{ZoneStatuses: []*apploadbalancer.TargetState_ZoneHealthcheckStatus{
+			{ZoneId: "zone", Status: apploadbalancer.TargetState_HEALTHY},
+		}},
+	}}
+
+	for _, tc := range []struct {
+		desc       string
+		objects    []client.Object
+		refs       map[string]k8s.IngressGroup
+		bgs        []*apploadbalancer.BackendGroup
+		repo       *fakeBackendGroupFinder
+		wantErr    error
+		wantReason string
+	}{
+		{
+			desc:    "healthy in backend group of resource",
+			objects: []client.Object{bgCR, status},
+			refs:    refs,
+			repo: &fakeBackendGroupFinder{
+				backendGroups: map[string]*apploadbalancer.BackendGroup{names.BackendGroupForCR("default", "bg"): {Id: "bg-id"}},
+				targetStates:  map[string][]*apploadbalancer.TargetState{"alb/bg-id": healthy},
+				balancerBGs:   map[string][]string{"alb": {"bg-id"}},
+			},
+			wantReason: "TargetHealthy",
+		},
+		{
+			desc:    "healthy in backend groups of ports used by different groups",
+			objects: []client.Object{status, otherStatus},
+			refs:    twoGroupRefs,
+			bgs:     portBGs,
+			repo: &fakeBackendGroupFinder{
+				targetStates: map[string][]*apploadbalancer.TargetState{"alb/bg-80": healthy, "alb-other/bg-8080": healthy},
+				balancerBGs:  map[string][]string{"alb": {"bg-80"}, "alb-other": {"bg-8080"}},
+			},
+			wantReason: "TargetHealthy",
+		},
+		{
+			desc:    "unhealthy in backend group of one of the groups",
+			objects: []client.Object{status, otherStatus},
+			refs:    twoGroupRefs,
+			bgs:     portBGs,
+			repo: &fakeBackendGroupFinder{
+				targetStates: map[string][]*apploadbalancer.TargetState{"alb/bg-80": healthy},
+				balancerBGs:  map[string][]string{"alb": {"bg-80"}, "alb-other": {"bg-8080"}},
+			},
+			wantErr: ycerrors.ResourceNotReadyError{ResourceType: "TargetHealth", Name: "1 pods of service default/svc"},
+		},
+		{
+			desc:    "unhealthy in backend group of resource",
+			objects: []client.Object{bgCR, status},
+			refs:    refs,
+			repo: &fakeBackendGroupFinder{
+				backendGroups: map[string]*apploadbalancer.BackendGroup{names.BackendGroupForCR("default", "bg"): {Id: "bg-id"}},
+				balancerBGs:   map[string][]string{"alb": {"bg-id"}},
+			},
+			wantErr: ycerrors.ResourceNotReadyError{ResourceType: "TargetHealth", Name: "1 pods of service default/svc"},
+		},
+		{
+			desc:    "backend group of resource not deployed yet",
+			objects: []client.Object{bgCR, status},
+			refs:    refs,
+			repo:    &fakeBackendGroupFinder{},
+			wantErr: ycerrors.ResourceNotReadyError{ResourceType: "BackendGroup", Name: names.BackendGroupForCR("default", "bg")},
+		},
+		{
+			desc:       "no backend groups",
+			objects:    []client.Object{status},
+			repo:       &fakeBackendGroupFinder{},
+			wantReason: "BackendGroupsNotFound",
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			ctx := context.Background()
+			cli := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(append(tc.objects, pod.DeepCopy())...).Build()
+			r := &Reconciler{
+				Client:             cli,
+				Repo:               tc.repo,
+				GroupStatusManager: k8s.NewGroupStatusManager(cli),
+				Names:              names,
+				recorder:           record.NewFakeRecorder(10),
+			}
+
+			err := r.reconcileReadinessGates(ctx, svc, tc.refs, tg, tc.bgs)
+			if tc.wantErr != nil {
+				assert.Equal(t, tc.wantErr, err)
+				return
+			}
+			require.NoError(t, err)
+
+			var actual core.Pod
+			require.NoError(t, cli.Get(ctx, types.NamespacedName{Namespace: "default", Name: "pod"}, &actual))
+			assert.False(t, k8s.WaitsForTargetHealth(&actual, "svc"))
+			for _, cond := range actual.Status.Conditions {
+				if cond.Type == k8s.ReadinessGateConditionType("svc") {
+					assert.Equal(t, tc.wantReason, cond.Reason)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package streambackendgroup
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/yandex-cloud/yc-alb-ingress-controller/pkg/k8s"
+	"k8s.io/client-go/tools/record"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	albv1alpha1 "github.com/yandex-cloud/yc-alb-ingress-controller/api/v1alpha1"
+	errors2 "github.com/yandex-cloud/yc-alb-ingress-controller/controllers/errors"
+)
+
+// Reconciler reconciles a StreamBackendGroup object
+type Reconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+	ReconcileHandler
+
+	recorder record.EventRecorder
+}
+
+//+kubebuilder:rbac:groups=alb.yc.io,resources=streambackendgroups,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=alb.yc.io,resources=streambackendgroups/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=alb.yc.io,resources=streambackendgroups/finalizers,verbs=update
+
+// Reconcile is part of the main kubernetes reconciliation loop which aims to
+// move the current state of the cluster closer to the desired state.
+// For more details, check Reconcile and its Result here:
+// - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.8.3/pkg/reconcile
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	rLog := log.FromContext(ctx).WithValues("name", req.NamespacedName, "kind", "StreamBackendGroup")
+	rLog.Info("event detected")
+
+	var bg albv1alpha1.StreamBackendGroup
+	err := r.Get(ctx, req.NamespacedName, &bg)
+
+	// StreamBackendGroup removed from etcd, and we failed to retrieve it (e.g. forceful deletion)
+	var statusError *k8serrors.StatusError
+	if errors.As(err, &statusError) && statusError.Status().Reason == metav1.StatusReasonNotFound {
+		rLog.Info("object not found, probably deleted")
+		// TODO: check handler's error after handler is implemented
+		_ = r.HandleResourceNotFound(ctx, req.NamespacedName)
+		return ctrl.Result{}, nil
+	}
+
+	// request failure
+	if err != nil {
+		rLog.Info("failed to retrieve object")
+		return ctrl.Result{RequeueAfter: time.Second * errors2.FailureRequeueInterval}, err
+	}
+
+	// StreamBackendGroup is being gracefully deleted
+	if !bg.DeletionTimestamp.IsZero() {
+		rLog.Info("object is being gracefully deleted")
+		err = r.HandleResourceDeleted(ctx, &bg)
+		errors2.HandleErrorWithObject(err, &bg, r.recorder)
+		return errors2.HandleError(err, rLog, "streambackendgroup", "")
+	}
+
+	rLog.Info("object has been created or updated")
+	err = r.HandleResourceUpdated(ctx, &bg)
+	errors2.HandleErrorWithObject(err, &bg, r.recorder)
+	r.setConditions(ctx, &bg, err)
+	return errors2.HandleError(err, rLog, "streambackendgroup", "")
+}
+
+// setConditions only logs errors, so they don't hide the result of reconciliation
+func (r *Reconciler) setConditions(ctx context.Context, bg *albv1alpha1.StreamBackendGroup, reconcileErr error) {
+	old := bg.DeepCopy()
+	reason, message := errors2.ConditionReason(reconcileErr)
+	k8s.SetReconcileConditions(&bg.Status.Conditions, bg.Generation, reason, message)
+	bg.Status.ObservedGeneration = bg.Generation
+	if equality.Semantic.DeepEqual(old.Status, bg.Status) {
+		return
+	}
+
+	if err := r.Status().Patch(ctx, bg, client.MergeFrom(old)); err != nil {
+		log.FromContext(ctx).Error(err, "failed to set conditions of stream backend group")
+	}
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
+	r.recorder = mgr.GetEventRecorderFor(k8s.ControllerName)
+	// status updates of the controller and of the target states poller don't change generation and aren't reconciled
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&albv1alpha1.StreamBackendGroup{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Complete(r)
+}
@@ -0,0 +1,273 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+
+	networking "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	albv1alpha1 "github.com/yandex-cloud/yc-alb-ingress-controller/api/v1alpha1"
+	"github.com/yandex-cloud/yc-alb-ingress-controller/pkg/builders"
+	"github.com/yandex-cloud/yc-alb-ingress-controller/pkg/k8s"
+	"github.com/yandex-cloud/yc-alb-ingress-controller/pkg/reconcile"
+)
+
+//+kubebuilder:webhook:path=/validate-networking-k8s-io-v1-ingress,mutating=false,failurePolicy=ignore,sideEffects=None,groups=networking.k8s.io,resources=ingresses,verbs=create;update,versions=v1,name=vingress.alb.yc.io,admissionReviewVersions=v1
+//+kubebuilder:webhook:path=/validate-alb-yc-io-v1alpha1-httpbackendgroup,mutating=false,failurePolicy=ignore,sideEffects=None,groups=alb.yc.io,resources=httpbackendgroups,verbs=create;update,versions=v1alpha1,name=vhttpbackendgroup.alb.yc.io,admissionReviewVersions=v1
+//+kubebuilder:webhook:path=/validate-alb-yc-io-v1alpha1-grpcbackendgroup,mutating=false,failurePolicy=ignore,sideEffects=None,groups=alb.yc.io,resources=grpcbackendgroups,verbs=create;update,versions=v1alpha1,name=vgrpcbackendgroup.alb.yc.io,admissionReviewVersions=v1
+//+kubebuilder:webhook:path=/validate-alb-yc-io-v1alpha1-ingressgroupsettings,mutating=false,failurePolicy=ignore,sideEffects=None,groups=alb.yc.io,resources=ingressgroupsettings,verbs=create;update,versions=v1alpha1,name=vingressgroupsettings.alb.yc.io,admissionReviewVersions=v1
+
+// SetupWithManager registers validating webhooks of ingresses, backend groups and ingress group settings
+func SetupWithManager(mgr ctrl.Manager) error {
+	cli := mgr.GetClient()
+	validator := reconcile.NewValidator()
+
+	if err := ctrl.NewWebhookManagedBy(mgr).
+		For(&networking.Ingress{}).
+		WithValidator(&IngressValidator{cli: cli, validator: validator}).
+		Complete(); err != nil {
+		return fmt.Errorf("failed to set up ingress webhook: %w", err)
+	}
+	if err := ctrl.NewWebhookManagedBy(mgr).
+		For(&albv1alpha1.HttpBackendGroup{}).
+		WithValidator(&HttpBackendGroupValidator{}).
+		Complete(); err != nil {
+		return fmt.Errorf("failed to set up http backend group webhook: %w", err)
+	}
+	if err := ctrl.NewWebhookManagedBy(mgr).
+		For(&albv1alpha1.GrpcBackendGroup{}).
+		WithValidator(&GrpcBackendGroupValidator{}).
+		Complete(); err != nil {
+		return fmt.Errorf("failed to set up grpc backend group webhook: %w", err)
+	}
+	if err := ctrl.NewWebhookManagedBy(mgr).
+		For(&albv1alpha1.IngressGroupSettings{}).
+		WithValidator(&SettingsValidator{cli: cli, validator: validator}).
+		Complete(); err != nil {
+		return fmt.Errorf("failed to set up ingress group settings webhook: %w", err)
+	}
+	return nil
+}
+
+// IngressValidator rejects ingresses which are invalid on their own or conflict with other ingresses of their group
+type IngressValidator struct {
+	cli       client.Client
+	validator *reconcile.Validator
+}
+
+func (v *IngressValidator) ValidateCreate(ctx context.Context, obj runtime.Object) error {
+	ing, ok := obj.(*networking.Ingress)
+	if !ok {
+		return fmt.Errorf("expected ingress, got %T", obj)
+	}
+	return v.validate(ctx, ing)
+}
+
+func (v *IngressValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) error {
+	oldIng, ok := oldObj.(*networking.Ingress)
+	if !ok {
+		return fmt.Errorf("expected ingress, got %T", oldObj)
+	}
+	newIng, ok := newObj.(*networking.Ingress)
+	if !ok {
+		return fmt.Errorf("expected ingress, got %T", newObj)
+	}
+	// updates of finalizers and status must not be blocked by ingresses which were admitted before
+	if !newIng.DeletionTimestamp.IsZero() ||
+		equality.Semantic.DeepEqual(oldIng.Spec, newIng.Spec) && equality.Semantic.DeepEqual(oldIng.Annotations, newIng.Annotations) {
+		return nil
+	}
+	return v.validate(ctx, newIng)
+}
+
+func (v *IngressValidator) ValidateDelete(context.Context, runtime.Object) error {
+	return nil
+}
+
+func (v *IngressValidator) validate(ctx context.Context, ing *networking.Ingress) error {
+	var classes networking.IngressClassList
+	if err := v.cli.List(ctx, &classes); err != nil {
+		return fmt.Errorf("failed to list ingress classes: %w", err)
+	}
+	if !k8s.IsIngressManagedByThisController(*ing, classes) {
+		return nil
+	}
+
+	tag := k8s.GetBalancerTag(ing)
+	g, err := k8s.NewGroupLoader(v.cli).Load(ctx, types.NamespacedName{Name: tag})
+	if err != nil {
+		return err
+	}
+	if g == nil {
+		g = &k8s.IngressGroup{Tag: tag}
+	}
+
+	// the ingress is validated after others of the group, so the settings named by them win
+	others := &k8s.IngressGroup{Tag: tag}
+	for _, item := range g.Items {
+		if item.Namespace != ing.Namespace || item.Name != ing.Name {
+			others.Items = append(others.Items, item)
+		}
+	}
+	settingsName := k8s.GroupSettingsName(others)
+	if settingsName == "" {
+		settingsName = ing.GetAnnotations()[k8s.GroupSettings]
+	}
+	settings, err := loadSettings(ctx, v.cli, settingsName)
+	if err != nil {
+		return err
+	}
+	return v.validator.ValidateIngressInGroup(g, *ing, settings)
+}
+
+// loadSettings returns nil if the settings aren't created yet, they are validated on their own creation then
+func loadSettings(ctx context.Context, cli client.Client, name string) (*albv1alpha1.IngressGroupSettings, error) {
+	if name == "" {
+		return nil, nil
+	}
+
+	var settings albv1alpha1.IngressGroupSettings
+	err := cli.Get(ctx, types.NamespacedName{Name: name}, &settings)
+	if k8serrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ingress group settings: %w", err)
+	}
+	return &settings, nil
+}
+
+// SettingsValidator rejects invalid ingress group settings and the ones conflicting with groups referencing them
+type SettingsValidator struct {
+	cli       client.Client
+	validator *reconcile.Validator
+}
+
+func (v *SettingsValidator) ValidateCreate(ctx context.Context, obj runtime.Object) error {
+	settings, ok := obj.(*albv1alpha1.IngressGroupSettings)
+	if !ok {
+		return fmt.Errorf("expected ingress group settings, got %T", obj)
+	}
+	if err := v.validator.ValidateSettings(settings); err != nil {
+		return err
+	}
+
+	tags, err := v.referencingGroups(ctx, settings.Name)
+	if err != nil {
+		return err
+	}
+	loader := k8s.NewGroupLoader(v.cli)
+	for _, tag := range tags {
+		g, err := loader.Load(ctx, types.NamespacedName{Name: tag})
+		if err != nil {
+			return err
+		}
+		if g == nil {
+			continue
+		}
+		if err = v.validator.ValidateIngressGroup(g, settings); err != nil {
+			return fmt.Errorf("settings conflict with ingress group %s: %w", tag, err)
+		}
+	}
+	return nil
+}
+
+func (v *SettingsValidator) ValidateUpdate(ctx context.Context, _, newObj runtime.Object) error {
+	return v.ValidateCreate(ctx, newObj)
+}
+
+func (v *SettingsValidator) ValidateDelete(context.Context, runtime.Object) error {
+	return nil
+}
+
+func (v *SettingsValidator) referencingGroups(ctx context.Context, name string) ([]string, error) {
+	var list networking.IngressList
+	if err := v.cli.List(ctx, &list); err != nil {
+		return nil, fmt.Errorf("failed to list ingresses: %w", err)
+	}
+	var classes networking.IngressClassList
+	if err := v.cli.List(ctx, &classes); err != nil {
+		return nil, fmt.Errorf("failed to list ingress classes: %w", err)
+	}
+
+	var tags []string
+	seen := make(map[string]struct{})
+	for _, item := range list.Items {
+		if item.GetAnnotations()[k8s.GroupSettings] != name || !k8s.IsIngressManagedByThisController(item, classes) {
+			continue
+		}
+		tag := k8s.GetBalancerTag(&item)
+		if _, ok := seen[tag]; ok {
+			continue
+		}
+		seen[tag] = struct{}{}
+		tags = append(tags, tag)
+	}
+	return tags, nil
+}
+
+// HttpBackendGroupValidator rejects invalid http backend groups
+type HttpBackendGroupValidator struct{} //nolint:revive
+
+func (v *HttpBackendGroupValidator) ValidateCreate(_ context.Context, obj runtime.Object) error {
+	bg, ok := obj.(*albv1alpha1.HttpBackendGroup)
+	if !ok {
+		return fmt.Errorf("expected http backend group, got %T", obj)
+	}
+	return builders.ValidateHttpBackendGroup(bg)
+}
+
+func (v *HttpBackendGroupValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) error {
+	oldBG, ok := oldObj.(*albv1alpha1.HttpBackendGroup)
+	if !ok {
+		return fmt.Errorf("expected http backend group, got %T", oldObj)
+	}
+	newBG, ok := newObj.(*albv1alpha1.HttpBackendGroup)
+	if !ok {
+		return fmt.Errorf("expected http backend group, got %T", newObj)
+	}
+	if !newBG.DeletionTimestamp.IsZero() || equality.Semantic.DeepEqual(oldBG.Spec, newBG.Spec) {
+		return nil
+	}
+	return v.ValidateCreate(ctx, newObj)
+}
+
+func (v *HttpBackendGroupValidator) ValidateDelete(context.Context, runtime.Object) error {
+	return nil
+}
+
+// GrpcBackendGroupValidator rejects invalid grpc backend groups
+type GrpcBackendGroupValidator struct{}
+
+func (v *GrpcBackendGroupValidator) ValidateCreate(_ context.Context, obj runtime.Object) error {
+	bg, ok := obj.(*albv1alpha1.GrpcBackendGroup)
+	if !ok {
+		return fmt.Errorf("expected grpc backend group, got %T", obj)
+	}
+	return builders.ValidateGrpcBackendGroup(bg)
+}
+
+func (v *GrpcBackendGroupValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) error {
+	oldBG, ok := oldObj.(*albv1alpha1.GrpcBackendGroup)
+	if !ok {
+		return fmt.Errorf("expected grpc backend group, got %T", oldObj)
+	}
+	newBG, ok := newObj.(*albv1alpha1.GrpcBackendGroup)
+	if !ok {
+		return fmt.Errorf("expected grpc backend group, got %T", newObj)
+	}
+	if !newBG.DeletionTimestamp.IsZero() || equality.Semantic.DeepEqual(oldBG.Spec, newBG.Spec) {
+		return nil
+	}
+	return v.ValidateCreate(ctx, newObj)
+}
+
+func (v *GrpcBackendGroupValidator) ValidateDelete(context.Context, runtime.Object) error {
+	return nil
+}
@@ -0,0 +1,137 @@
+package webhook
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	networking "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	albv1alpha1 "github.com/yandex-cloud/yc-alb-ingress-controller/api/v1alpha1"
+	"github.com/yandex-cloud/yc-alb-ingress-controller/pkg/k8s"
+	"github.com/yandex-cloud/yc-alb-ingress-controller/pkg/reconcile"
+)
+
+func ingress(name string, annotations map[string]string) *networking.Ingress {
+	return &networking.Ingress{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name, Annotations: annotations}}
+}
+
+func TestIngressValidator(t *testing.T) {
+	require.NoError(t, albv1alpha1.AddToScheme(scheme.Scheme))
+	ctx := context.Background()
+
+	for _, tc := range []struct {
+		desc    string
+		objects []client.Object
+		ing     *networking.Ingress
+		wantErr bool
+	}{
+		{
+			desc: "valid",
+			ing:  ingress("ing", map[string]string{k8s.AlbTag: "tag", k8s.RequestTimeout: "10s"}),
+		},
+		{
+			desc:    "invalid",
+			ing:     ingress("ing", map[string]string{k8s.AlbTag: "tag", k8s.RequestTimeout: "ten seconds"}),
+			wantErr: true,
+		},
+		{
+			desc: "not managed by the controller",
+			ing:  ingress("ing", map[string]string{k8s.RequestTimeout: "ten seconds"}),
+		},
+		{
+			desc:    "conflicting with group",
+			objects: []client.Object{ingress("other", map[string]string{k8s.AlbTag: "tag", k8s.RedirectHTTPToHTTPS: "true"})},
+			ing:     ingress("ing", map[string]string{k8s.AlbTag: "tag", k8s.RedirectHTTPToHTTPS: "false"}),
+			wantErr: true,
+		},
+		{
+			desc:    "group with invalid ingress",
+			objects: []client.Object{ingress("broken", map[string]string{k8s.AlbTag: "tag", k8s.RequestTimeout: "ten seconds"})},
+			ing:     ingress("ing", map[string]string{k8s.AlbTag: "tag"}),
+		},
+		{
+			desc: "conflict overridden by settings",
+			objects: []client.Object{
+				ingress("other", map[string]string{k8s.AlbTag: "tag", k8s.GroupSettings: "settings", k8s.ExternalIPv4Address: "1.2.3.4"}),
+				&albv1alpha1.IngressGroupSettings{
+					ObjectMeta: metav1.ObjectMeta{Name: "settings"},
+					Addresses:  &albv1alpha1.Addresses{ExternalIPv4: "auto"},
+				},
+			},
+			ing: ingress("ing", map[string]string{k8s.AlbTag: "tag", k8s.GroupSettings: "settings", k8s.ExternalIPv4Address: "5.6.7.8"}),
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			cli := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(tc.objects...).Build()
+			v := &IngressValidator{cli: cli, validator: reconcile.NewValidator()}
+			err := v.ValidateCreate(ctx, tc.ing)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestIngressValidator_ValidateUpdate(t *testing.T) {
+	ctx := context.Background()
+	cli := fake.NewClientBuilder().WithScheme(scheme.Scheme).Build()
+	v := &IngressValidator{cli: cli, validator: reconcile.NewValidator()}
+
+	invalid := ingress("ing", map[string]string{k8s.AlbTag: "tag", k8s.RequestTimeout: "ten seconds"})
+	withFinalizer := invalid.DeepCopy()
+	withFinalizer.Finalizers = []string{k8s.Finalizer}
+	assert.NoError(t, v.ValidateUpdate(ctx, invalid, withFinalizer), "update of metadata admitted before isn't validated")
+
+	changed := invalid.DeepCopy()
+	changed.Annotations[k8s.RequestTimeout] = "ten minutes"
+	assert.Error(t, v.ValidateUpdate(ctx, invalid, changed))
+}
+
+func TestSettingsValidator(t *testing.T) {
+	require.NoError(t, albv1alpha1.AddToScheme(scheme.Scheme))
+	ctx := context.Background()
+	cli := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(
+		ingress("ing", map[string]string{k8s.AlbTag: "tag", k8s.GroupSettings: "settings", k8s.StreamListeners: "5432=postgres"}),
+	).Build()
+	v := &SettingsValidator{cli: cli, validator: reconcile.NewValidator()}
+
+	settings := func(port int64) *albv1alpha1.IngressGroupSettings {
+		return &albv1alpha1.IngressGroupSettings{
+			ObjectMeta: metav1.ObjectMeta{Name: "settings"},
+			Listeners:  []albv1alpha1.Listener{{Port: port, Protocol: "HTTP"}},
+		}
+	}
+	assert.NoError(t, v.ValidateCreate(ctx, settings(8080)))
+	assert.Error(t, v.ValidateCreate(ctx, settings(5432)), "listener conflicts with stream listener of the group")
+}
+
+func TestBackendGroupValidators(t *testing.T) {
+	ctx := context.Background()
+	svc := &albv1alpha1.ServiceBackend{Name: "svc", Port: albv1alpha1.ServiceBackendPort{Number: 80}}
+
+	valid := &albv1alpha1.HttpBackendGroup{Spec: albv1alpha1.HttpBackendGroupSpec{
+		Backends: []*albv1alpha1.HttpBackend{{Name: "a", Service: svc}},
+	}}
+	invalid := &albv1alpha1.HttpBackendGroup{Spec: albv1alpha1.HttpBackendGroupSpec{
+		Backends: []*albv1alpha1.HttpBackend{{Name: "a", Service: svc}, {Name: "a", Service: svc}},
+	}}
+	assert.NoError(t, (&HttpBackendGroupValidator{}).ValidateCreate(ctx, valid))
+	assert.Error(t, (&HttpBackendGroupValidator{}).ValidateCreate(ctx, invalid))
+	assert.Error(t, (&HttpBackendGroupValidator{}).ValidateUpdate(ctx, valid, invalid))
+	assert.Error(t, (&HttpBackendGroupValidator{}).ValidateCreate(ctx, ingress("ing", nil)))
+
+	assert.NoError(t, (&GrpcBackendGroupValidator{}).ValidateCreate(ctx, &albv1alpha1.GrpcBackendGroup{Spec: albv1alpha1.GrpcBackendGroupSpec{
+		Backends: []*albv1alpha1.GrpcBackend{{Name: "a", Service: svc}},
+	}}))
+	assert.Error(t, (&GrpcBackendGroupValidator{}).ValidateCreate(ctx, &albv1alpha1.GrpcBackendGroup{Spec: albv1alpha1.GrpcBackendGroupSpec{
+		Backends: []*albv1alpha1.GrpcBackend{{Name: "a"}},
+	}}))
+}
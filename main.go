@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"time"
 
 	ycsdk "github.com/yandex-cloud/go-sdk"
 	"github.com/yandex-cloud/go-sdk/iamkey"
@@ -40,15 +41,19 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
 	albv1alpha1 "github.com/yandex-cloud/yc-alb-ingress-controller/api/v1alpha1"
+	"github.com/yandex-cloud/yc-alb-ingress-controller/controllers/gateway"
 	"github.com/yandex-cloud/yc-alb-ingress-controller/controllers/grpcbackendgroup"
 	"github.com/yandex-cloud/yc-alb-ingress-controller/controllers/httpbackendgroup"
 	"github.com/yandex-cloud/yc-alb-ingress-controller/controllers/ingress"
 	"github.com/yandex-cloud/yc-alb-ingress-controller/controllers/secret"
 	"github.com/yandex-cloud/yc-alb-ingress-controller/controllers/service"
+	"github.com/yandex-cloud/yc-alb-ingress-controller/controllers/streambackendgroup"
+	"github.com/yandex-cloud/yc-alb-ingress-controller/controllers/webhook"
 	"github.com/yandex-cloud/yc-alb-ingress-controller/pkg/builders"
 	"github.com/yandex-cloud/yc-alb-ingress-controller/pkg/deploy"
 	"github.com/yandex-cloud/yc-alb-ingress-controller/pkg/k8s"
 	"github.com/yandex-cloud/yc-alb-ingress-controller/pkg/metadata"
+	"github.com/yandex-cloud/yc-alb-ingress-controller/pkg/metrics"
 	"github.com/yandex-cloud/yc-alb-ingress-controller/pkg/reconcile"
 	"github.com/yandex-cloud/yc-alb-ingress-controller/pkg/yc"
 	//+kubebuilder:scaffold:imports
@@ -89,6 +94,14 @@ func main() {
 		keyFile                   string
 		endpoint                  string
 		enableDefaultHealthChecks bool
+		enableGatewayAPI          bool
+		targetStatesPollInterval  time.Duration
+		dryRun                    bool
+		defaultTargetMode         string
+		deregistrationDelay       time.Duration
+		lockboxPollInterval       time.Duration
+		certExpiryWindow          time.Duration
+		enableWebhook             bool
 	)
 	flag.StringVar(&folderID, "folder-id", "", "alb folder ID")
 	flag.StringVar(&certsFolderID, "certs-folder-id", "", "certificates folder ID, by default equals to value of folder-id")
@@ -98,6 +111,15 @@ func main() {
 	flag.StringVar(&keyFile, "keyfile", "", "service account key json file")
 	flag.StringVar(&endpoint, "endpoint", "", "cloud environment endpoint (defaults to prod endpoint)")
 	flag.BoolVar(&enableDefaultHealthChecks, "enable-default-health-checks", true, "enables default healthchecks in ALB configuration")
+	flag.BoolVar(&enableGatewayAPI, "enable-gateway-api", false, "enables Gateway API support, Gateway API CRDs must be installed in the cluster")
+	flag.DurationVar(&targetStatesPollInterval, "target-states-poll-interval", time.Minute, "interval of polling health of backend targets, 0 disables polling")
+	flag.BoolVar(&dryRun, "dry-run", false, "only read cloud resources, planned changes are logged and written into ingress group statuses")
+	flag.StringVar(&defaultTargetMode, "default-target-mode", k8s.TargetModeNode,
+		"targets of services without target-mode annotation: node for NodePort on cluster nodes, pod-ip for pods directly")
+	flag.DurationVar(&deregistrationDelay, "deregistration-delay", 0, "time targets which left a service are kept in its target group to complete requests in flight. Kept targets aren't drained: the balancer still sends them new requests until the delay passes")
+	flag.DurationVar(&lockboxPollInterval, "lockbox-poll-interval", 5*time.Minute, "interval of syncing certificates from the current versions of lockbox secrets, 0 disables polling")
+	flag.DurationVar(&certExpiryWindow, "cert-expiry-window", 30*24*time.Hour, "warning events are emitted for certificates expiring within this time")
+	flag.BoolVar(&enableWebhook, "enable-webhook", false, "enables validating admission webhook, serving certificates must be mounted into the webhook server cert dir")
 
 	opts := zap.Options{
 		Development:     true,
@@ -144,6 +166,77 @@ func main() {
 		}
 	}
 
+	if envEnable := os.Getenv("YC_ALB_ENABLE_GATEWAY_API"); envEnable != "" {
+		var err error
+		enableGatewayAPI, err = strconv.ParseBool(envEnable)
+		if err != nil {
+			setupLog.Error(err, "unable to parse YC_ALB_ENABLE_GATEWAY_API")
+			os.Exit(1)
+		}
+	}
+
+	if envEnable := os.Getenv("YC_ALB_DRY_RUN"); envEnable != "" {
+		var err error
+		dryRun, err = strconv.ParseBool(envEnable)
+		if err != nil {
+			setupLog.Error(err, "unable to parse YC_ALB_DRY_RUN")
+			os.Exit(1)
+		}
+	}
+
+	if envEnable := os.Getenv("YC_ALB_ENABLE_WEBHOOK"); envEnable != "" {
+		var err error
+		enableWebhook, err = strconv.ParseBool(envEnable)
+		if err != nil {
+			setupLog.Error(err, "unable to parse YC_ALB_ENABLE_WEBHOOK")
+			os.Exit(1)
+		}
+	}
+
+	if envInterval := os.Getenv("YC_ALB_TARGET_STATES_POLL_INTERVAL"); envInterval != "" {
+		var err error
+		targetStatesPollInterval, err = time.ParseDuration(envInterval)
+		if err != nil {
+			setupLog.Error(err, "unable to parse YC_ALB_TARGET_STATES_POLL_INTERVAL")
+			os.Exit(1)
+		}
+	}
+
+	if envDelay := os.Getenv("YC_ALB_DEREGISTRATION_DELAY"); envDelay != "" {
+		var err error
+		deregistrationDelay, err = time.ParseDuration(envDelay)
+		if err != nil {
+			setupLog.Error(err, "unable to parse YC_ALB_DEREGISTRATION_DELAY")
+			os.Exit(1)
+		}
+	}
+
+	if envInterval := os.Getenv("YC_ALB_LOCKBOX_POLL_INTERVAL"); envInterval != "" {
+		var err error
+		lockboxPollInterval, err = time.ParseDuration(envInterval)
+		if err != nil {
+			setupLog.Error(err, "unable to parse YC_ALB_LOCKBOX_POLL_INTERVAL")
+			os.Exit(1)
+		}
+	}
+
+	if envWindow := os.Getenv("YC_ALB_CERT_EXPIRY_WINDOW"); envWindow != "" {
+		var err error
+		certExpiryWindow, err = time.ParseDuration(envWindow)
+		if err != nil {
+			setupLog.Error(err, "unable to parse YC_ALB_CERT_EXPIRY_WINDOW")
+			os.Exit(1)
+		}
+	}
+
+	if envMode := os.Getenv("YC_ALB_DEFAULT_TARGET_MODE"); envMode != "" {
+		defaultTargetMode = envMode
+	}
+	if err := k8s.SetupDefaultTargetMode(defaultTargetMode); err != nil {
+		setupLog.Error(err, "unable to set up default target mode")
+		os.Exit(1)
+	}
+
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
 		Scheme:                 scheme,
 		HealthProbeBindAddress: probeAddr,
@@ -176,24 +269,35 @@ func main() {
 
 	cli := mgr.GetClient()
 	repo := yc.NewRepository(sdk, names, folderID)
+	if dryRun {
+		setupLog.Info("running in dry-run mode, cloud resources are not changed")
+		repo = yc.NewDryRunRepository(sdk, names, folderID)
+	}
 	builders.SetupDefaultHealthChecks(enableDefaultHealthChecks)
 	resolvers := builders.NewResolvers(repo)
 
+	ingressLoader := k8s.NewIngressLoader(cli)
+	if enableGatewayAPI {
+		ingressLoader = k8s.NewIngressLoaderWithGateways(cli)
+	}
+
 	if err = (&service.Reconciler{
-		Repo: repo,
+		Client: cli,
+		Repo:   repo,
 
 		TargetGroupBuilder:  reconcile.NewTargetGroupBuilder(folderID, cli, names, labels, repo.FindInstanceByID, useEndpointSlices),
-		TargetGroupDeployer: deploy.NewServiceDeployer(repo),
+		TargetGroupDeployer: deploy.NewServiceDeployer(repo, deregistrationDelay),
 
-		BackendGroupBuilder:  &builders.BackendGroupForSvcBuilder{FolderID: folderID, Names: names},
+		BackendGroupBuilder:  &builders.BackendGroupForSvcBuilder{FolderID: folderID, Names: names, Cli: cli},
 		BackendGroupDeployer: deploy.NewBackendGroupDeployer(repo),
 
-		FinalizerManager:   &k8s.FinalizerManager{Client: cli},
+		FinalizerManager:   &k8s.FinalizerManager{Client: cli, DryRun: dryRun},
 		GroupStatusManager: k8s.NewGroupStatusManager(cli),
-		ServiceLoader:      &k8s.DefaultServiceLoader{Client: cli},
-		IngressLoader:      k8s.NewIngressLoader(cli),
+		ServiceLoader:      &k8s.DefaultServiceLoader{Client: cli, GatewayAPI: enableGatewayAPI},
+		IngressLoader:      ingressLoader,
 		Names:              names,
 		Resolvers:          resolvers,
+		GatewayAPI:         enableGatewayAPI,
 	}).SetupWithManager(mgr, useEndpointSlices); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Service")
 		os.Exit(1)
@@ -211,31 +315,62 @@ func main() {
 
 	secretEventChan := make(chan event.GenericEvent)
 	certRepo := yc.NewCertRepo(sdk, certsFolderID)
+	if dryRun {
+		certRepo = yc.NewDryRunCertRepo(certRepo)
+	}
 
 	if err = (&ingress.GroupReconciler{
-		Loader:             k8s.NewGroupLoader(cli),
-		Builder:            reconcile.NewDefaultDataBuilder(factory, resolvers, newEngineFn, folderID, names, certRepo, repo, cli),
-		Deployer:           deploy.NewIngressGroupDeployManager(repo),
-		StatusUpdater:      &k8s.StatusUpdater{Client: cli},
-		FinalizerManager:   &k8s.FinalizerManager{Client: cli},
-		GroupStatusManager: k8s.NewGroupStatusManager(cli),
-		StatusResolver:     &reconcile.IngressStatusResolver{},
-		SettingsLoader:     &k8s.GroupSettingsLoader{Client: cli},
-		Scheme:             mgr.GetScheme(),
+		Loader:                k8s.NewGroupLoader(cli),
+		Builder:               reconcile.NewDefaultDataBuilder(factory, resolvers, newEngineFn, folderID, names, certRepo, repo, cli),
+		Deployer:              deploy.NewIngressGroupDeployManager(repo),
+		StatusUpdater:         &k8s.StatusUpdater{Client: cli},
+		FinalizerManager:      &k8s.FinalizerManager{Client: cli, DryRun: dryRun},
+		GroupStatusManager:    k8s.NewGroupStatusManager(cli),
+		StatusResolver:        &reconcile.IngressStatusResolver{},
+		SettingsLoader:        &k8s.GroupSettingsLoader{Client: cli},
+		SettingsStatusUpdater: &k8s.GroupSettingsStatusUpdater{Client: cli},
+		Adopter:               deploy.NewBalancerAdopter(repo, names, labels, folderID),
+		Validator:             reconcile.NewValidator(),
+		DryRun:                dryRun,
+		Scheme:                mgr.GetScheme(),
 	}).SetupWithManager(mgr, clientSet, secretEventChan); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Ingress-Groups")
 		os.Exit(1)
 	}
 
-	if err = (secret.NewController(cli, certRepo, names)).SetupWithManager(mgr, secretEventChan); err != nil {
+	if enableGatewayAPI {
+		// factory keeps state of the build in progress, so it can't be shared with the ingress controller
+		gatewayFactory := builders.NewFactory(folderID, region, names, labels, cli, repo)
+		if err = (&gateway.Reconciler{
+			Loader:             k8s.NewGatewayLoader(cli),
+			Builder:            reconcile.NewDefaultDataBuilder(gatewayFactory, resolvers, newEngineFn, folderID, names, certRepo, repo, cli),
+			Deployer:           deploy.NewIngressGroupDeployManager(repo),
+			StatusUpdater:      &k8s.GatewayStatusUpdater{Client: cli},
+			FinalizerManager:   &k8s.FinalizerManager{Client: cli, DryRun: dryRun},
+			GroupStatusManager: k8s.NewGroupStatusManager(cli),
+			StatusResolver:     &reconcile.IngressStatusResolver{},
+			SettingsLoader:     &k8s.GroupSettingsLoader{Client: cli},
+			Scheme:             mgr.GetScheme(),
+		}).SetupWithManager(mgr, clientSet, secretEventChan); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "Gateway")
+			os.Exit(1)
+		}
+	}
+
+	if err = (secret.NewController(cli, certRepo, names, certExpiryWindow)).SetupWithManager(mgr, secretEventChan); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Secrets")
 		os.Exit(1)
 	}
 
+	if err = (secret.NewLockboxController(cli, certRepo, yc.NewLockboxRepo(sdk), names, lockboxPollInterval, certExpiryWindow)).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "Lockbox")
+		os.Exit(1)
+	}
+
 	httpBGRecHandler := &reconcile.HttpBackendGroupReconcileHandler{
 		Repo:             repo,
 		Predicates:       &yc.UpdatePredicates{},
-		FinalizerManager: &k8s.FinalizerManager{Client: cli},
+		FinalizerManager: &k8s.FinalizerManager{Client: cli, DryRun: dryRun},
 
 		Builder: &builders.HttpBackendGroupForCrdBuilder{
 			FolderID: folderID,
@@ -259,7 +394,7 @@ func main() {
 	grpcBGRecHandler := &reconcile.GrpcBackendGroupReconcileHandler{
 		Repo:             repo,
 		Predicates:       &yc.UpdatePredicates{},
-		FinalizerManager: &k8s.FinalizerManager{Client: cli},
+		FinalizerManager: &k8s.FinalizerManager{Client: cli, DryRun: dryRun},
 
 		Builder: &builders.GrpcBackendGroupForCrdBuilder{
 			FolderID: folderID,
@@ -279,8 +414,67 @@ func main() {
 		setupLog.Error(err, "unable to create controller", "controller", "HttpBackendGroup")
 		os.Exit(1)
 	}
+
+	streamBGRecHandler := &reconcile.StreamBackendGroupReconcileHandler{
+		Repo:             repo,
+		Predicates:       &yc.UpdatePredicates{},
+		FinalizerManager: &k8s.FinalizerManager{Client: cli, DryRun: dryRun},
+
+		Builder: &builders.StreamBackendGroupForCrdBuilder{
+			FolderID: folderID,
+			Names:    names,
+			Cli:      cli,
+			Repo:     repo,
+		},
+		Deployer: deploy.NewBackendGroupDeployer(repo),
+
+		Names: names,
+	}
+	if err = (&streambackendgroup.Reconciler{
+		Client:           mgr.GetClient(),
+		Scheme:           mgr.GetScheme(),
+		ReconcileHandler: streamBGRecHandler,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "StreamBackendGroup")
+		os.Exit(1)
+	}
 	// +kubebuilder:scaffold:builder
 
+	if enableWebhook {
+		if err = webhook.SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook")
+			os.Exit(1)
+		}
+	}
+
+	if err = mgr.Add(&k8s.ManagedResourcesCounter{
+		Client:   cli,
+		CertRepo: certRepo,
+		Interval: time.Minute,
+		Log:      ctrl.Log.WithName("managed-resources"),
+	}); err != nil {
+		setupLog.Error(err, "unable to set up managed resources counter")
+		os.Exit(1)
+	}
+
+	if targetStatesPollInterval > 0 {
+		publisher := &k8s.TargetStatesPublisher{
+			Client:   cli,
+			Names:    names,
+			Recorder: mgr.GetEventRecorderFor("target-states"),
+		}
+		if err = mgr.Add(&yc.TargetStatesPoller{
+			Repo:      repo,
+			Interval:  targetStatesPollInterval,
+			Log:       ctrl.Log.WithName("target-states"),
+			Balancers: publisher.Balancers,
+			Publish:   publisher.Publish,
+		}); err != nil {
+			setupLog.Error(err, "unable to set up target states poller")
+			os.Exit(1)
+		}
+	}
+
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
 		setupLog.Error(err, "unable to set up health check")
 		os.Exit(1)
@@ -319,7 +513,7 @@ func buildSDK(keyFile, endpoint string) (*ycsdk.SDK, error) {
 	return ycsdk.Build(context.Background(), ycsdk.Config{
 		Credentials: creds,
 		Endpoint:    endpoint,
-	}, grpc.WithUserAgent(userAgent))
+	}, grpc.WithUserAgent(userAgent), grpc.WithChainUnaryInterceptor(metrics.UnaryClientInterceptor()))
 }
 
 type Key struct {
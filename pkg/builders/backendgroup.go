@@ -11,6 +11,8 @@ import (
 	"google.golang.org/protobuf/types/known/durationpb"
 	core "k8s.io/api/core/v1"
 	networking "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 const (
@@ -74,8 +76,10 @@ func HTTPIngressPathToHostAndPath(host string, path networking.HTTPIngressPath,
 	return HostAndPath{Host: host, Path: path.Path, PathType: string(*path.PathType)}, nil
 }
 
-// exposedNodePort - exposed NodePort and route which will be served by a service which opened it
-type exposedNodePort struct {
+// exposedBackendPort - port of targets of the service: NodePort, unique across the cluster, or container port of pods,
+// which may be shared by different services
+type exposedBackendPort struct {
+	svc  types.NamespacedName
 	port int64
 }
 
@@ -110,11 +114,13 @@ type BackendGroups struct {
 type BackendGroupForSvcBuilder struct {
 	FolderID string
 	Names    *metadata.Names
+	// Cli resolves named target ports of services using pod IP targets
+	Cli client.Reader
 }
 
-func (b *BackendGroupForSvcBuilder) BuildForSvc(svc *core.Service, ings []networking.Ingress, tgID string) ([]*apploadbalancer.BackendGroup, error) {
-	if svc.Spec.Type != core.ServiceTypeNodePort {
-		return nil, fmt.Errorf("type of service %s/%s used by path is not NodePort", svc.Name, svc.Namespace)
+func (b *BackendGroupForSvcBuilder) BuildForSvc(ctx context.Context, svc *core.Service, ings []networking.Ingress, tgID string) ([]*apploadbalancer.BackendGroup, error) {
+	if err := checkServiceType(svc); err != nil {
+		return nil, err
 	}
 
 	nodePorts, err := collectPortsForService(svc, ings)
@@ -127,10 +133,10 @@ func (b *BackendGroupForSvcBuilder) BuildForSvc(svc *core.Service, ings []networ
 		return nil, fmt.Errorf("failed to build backend opts: %w", err)
 	}
 
-	return b.buildForSvc(svc, nodePorts, tgID, opts)
+	return b.buildForSvc(ctx, svc, nodePorts, tgID, opts)
 }
 
-func (b *BackendGroupForSvcBuilder) buildForSvc(svc *core.Service, nodePorts []core.ServicePort, tgID string, opts BackendResolveOpts) ([]*apploadbalancer.BackendGroup, error) {
+func (b *BackendGroupForSvcBuilder) buildForSvc(ctx context.Context, svc *core.Service, nodePorts []core.ServicePort, tgID string, opts BackendResolveOpts) ([]*apploadbalancer.BackendGroup, error) {
 	balancingConfig, err := parseBalancingConfigFromStruct(opts.LoadBalancingConfig)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse balancing config: %w", err)
@@ -145,7 +151,7 @@ func (b *BackendGroupForSvcBuilder) buildForSvc(svc *core.Service, nodePorts []c
 
 	if opts.BackendType == GRPC {
 		backends, err := b.buildGrpcBackends(
-			svc, tgID, nodePorts, balancingConfig,
+			ctx, svc, tgID, nodePorts, balancingConfig,
 			tls, opts.healthChecks,
 		)
 		if err != nil {
@@ -168,7 +174,7 @@ func (b *BackendGroupForSvcBuilder) buildForSvc(svc *core.Service, nodePorts []c
 		}
 	} else {
 		backends, err := b.buildHTTPBackends(
-			svc, tgID, nodePorts, balancingConfig,
+			ctx, svc, tgID, nodePorts, balancingConfig,
 			tls, opts.BackendType == HTTP2, opts.healthChecks,
 		)
 		if err != nil {
@@ -285,6 +291,18 @@ func (b *BackendGroupForSvcBuilder) backendOpts(svc *core.Service, ings []networ
 	return opts, nil
 }
 
+// checkServiceType checks that service exposes NodePort unless its pods are used as targets directly
+func checkServiceType(svc *core.Service) error {
+	podTargets, err := k8s.PodIPTargets(svc)
+	if err != nil {
+		return err
+	}
+	if !podTargets && svc.Spec.Type != core.ServiceTypeNodePort {
+		return fmt.Errorf("type of service %s/%s used by path is not NodePort", svc.Name, svc.Namespace)
+	}
+	return nil
+}
+
 func parseSvcAnnotationFromIngs(ings []networking.Ingress, annotation string) (string, error) {
 	var result string
 	for _, ing := range ings {
@@ -350,7 +368,7 @@ func collectPortsForService(svc *core.Service, ings []networking.Ingress) ([]cor
 }
 
 func (b *BackendGroupForSvcBuilder) buildHTTPBackends(
-	svc *core.Service, tgID string,
+	ctx context.Context, svc *core.Service, tgID string,
 	ports []core.ServicePort,
 	balancingConfig *apploadbalancer.LoadBalancingConfig,
 	tls *apploadbalancer.BackendTls, useHTTP2 bool,
@@ -358,9 +376,13 @@ func (b *BackendGroupForSvcBuilder) buildHTTPBackends(
 ) ([]*apploadbalancer.HttpBackend, error) {
 	backends := make([]*apploadbalancer.HttpBackend, 0, len(ports))
 	for _, p := range ports {
+		port, err := k8s.BackendPort(ctx, b.Cli, svc, p)
+		if err != nil {
+			return nil, err
+		}
 		backends = append(backends, &apploadbalancer.HttpBackend{
-			Name: b.Names.Backend("", svc.Namespace, svc.Name, p.Port, p.NodePort), // TODO(khodasevich): make better name
-			Port: int64(p.NodePort),
+			Name: b.Names.Backend("", svc.Namespace, svc.Name, p.Port, port), // TODO(khodasevich): make better name
+			Port: int64(port),
 			BackendType: &apploadbalancer.HttpBackend_TargetGroups{
 				TargetGroups: &apploadbalancer.TargetGroupsBackend{TargetGroupIds: []string{tgID}},
 			},
@@ -376,7 +398,7 @@ func (b *BackendGroupForSvcBuilder) buildHTTPBackends(
 }
 
 func (b *BackendGroupForSvcBuilder) buildGrpcBackends(
-	svc *core.Service, id string,
+	ctx context.Context, svc *core.Service, id string,
 	ports []core.ServicePort,
 	balancingConfig *apploadbalancer.LoadBalancingConfig,
 	tls *apploadbalancer.BackendTls,
@@ -385,9 +407,13 @@ func (b *BackendGroupForSvcBuilder) buildGrpcBackends(
 	backends := make([]*apploadbalancer.GrpcBackend, 0, len(ports))
 
 	for _, p := range ports {
+		port, err := k8s.BackendPort(ctx, b.Cli, svc, p)
+		if err != nil {
+			return nil, err
+		}
 		backends = append(backends, &apploadbalancer.GrpcBackend{
-			Name: b.Names.Backend("", svc.Namespace, svc.Name, p.Port, p.NodePort), // TODO(khodasevich): make better name
-			Port: int64(p.NodePort),
+			Name: b.Names.Backend("", svc.Namespace, svc.Name, p.Port, port), // TODO(khodasevich): make better name
+			Port: int64(port),
 			BackendType: &apploadbalancer.GrpcBackend_TargetGroups{
 				TargetGroups: &apploadbalancer.TargetGroupsBackend{TargetGroupIds: []string{id}},
 			},
@@ -439,6 +465,19 @@ func parseHTTPSessionAffinityFromOpts(o BackendResolveOpts) (apploadbalancer.Htt
 	}
 }
 
+// backendPorts returns ports of targets serving the service ports
+func backendPorts(ctx context.Context, cli client.Reader, svc *core.Service, svcPorts []core.ServicePort) ([]int64, error) {
+	ret := make([]int64, 0, len(svcPorts))
+	for _, p := range svcPorts {
+		port, err := k8s.BackendPort(ctx, cli, svc, p)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, int64(port))
+	}
+	return ret, nil
+}
+
 func nodePortsForServicePort(portName string, portNumber int32, servicePorts []core.ServicePort) []core.ServicePort {
 	var nodePorts []core.ServicePort
 	if len(portName) > 0 {
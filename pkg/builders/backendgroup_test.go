@@ -1,18 +1,23 @@
 package builders
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/yandex-cloud/go-genproto/yandex/cloud/apploadbalancer/v1"
+	"github.com/yandex-cloud/yc-alb-ingress-controller/pkg/k8s"
 	"github.com/yandex-cloud/yc-alb-ingress-controller/pkg/metadata"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/durationpb"
 	"google.golang.org/protobuf/types/known/wrapperspb"
 	v12 "k8s.io/api/core/v1"
+	networking "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 func TestBackendGroupForSvcBuilder_BuildForSvc(t *testing.T) {
@@ -292,7 +297,7 @@ func TestBackendGroupForSvcBuilder_BuildForSvc(t *testing.T) {
 				Names:    &metadata.Names{ClusterID: "my-cluster"},
 			}
 
-			bgs, err := b.buildForSvc(tc.svc, tc.nodePorts, "target-group-id", tc.opts)
+			bgs, err := b.buildForSvc(context.Background(), tc.svc, tc.nodePorts, "target-group-id", tc.opts)
 			require.True(t, (err != nil) == tc.wantError)
 			if tc.wantError {
 				return
@@ -326,3 +331,67 @@ func TestBackendGroupForSvcBuilder_BuildForSvc(t *testing.T) {
 		})
 	}
 }
+
+func TestBackendGroupForSvcBuilder_BuildForSvc_PodIPTargets(t *testing.T) {
+	svc := &v12.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "default",
+			Name:        "service1",
+			Annotations: map[string]string{k8s.TargetMode: k8s.TargetModePodIP},
+		},
+		Spec: v12.ServiceSpec{
+			Type:     v12.ServiceTypeClusterIP,
+			Selector: map[string]string{"app": "web"},
+			Ports: []v12.ServicePort{
+				{Name: "http", Port: 80, TargetPort: intstr.FromInt(8080)},
+				{Name: "named", Port: 81, TargetPort: intstr.FromString("web")},
+			},
+		},
+	}
+	ingWithPort := func(port networking.ServiceBackendPort) []networking.Ingress {
+		return []networking.Ingress{{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "ing"},
+			Spec: networking.IngressSpec{
+				DefaultBackend: &networking.IngressBackend{
+					Service: &networking.IngressServiceBackend{Name: "service1", Port: port},
+				},
+			},
+		}}
+	}
+
+	pod := &v12.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pod", Labels: map[string]string{"app": "web"}},
+		Spec: v12.PodSpec{Containers: []v12.Container{{
+			Name:  "app",
+			Ports: []v12.ContainerPort{{Name: "web", ContainerPort: 9090}},
+		}}},
+	}
+
+	ctx := context.Background()
+	b := BackendGroupForSvcBuilder{
+		FolderID: "my-folder",
+		Names:    &metadata.Names{ClusterID: "my-cluster"},
+		Cli:      fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(pod).Build(),
+	}
+
+	bgs, err := b.BuildForSvc(ctx, svc, ingWithPort(networking.ServiceBackendPort{Name: "http"}), "target-group-id")
+	require.NoError(t, err)
+	require.Len(t, bgs, 1)
+	assert.Equal(t, b.Names.BackendGroupForSvcPort(k8s.NamespacedNameOf(svc), 8080), bgs[0].Name)
+	backends := bgs[0].GetHttp().GetBackends()
+	require.Len(t, backends, 1)
+	assert.Equal(t, int64(8080), backends[0].Port)
+
+	bgs, err = b.BuildForSvc(ctx, svc, ingWithPort(networking.ServiceBackendPort{Name: "named"}), "target-group-id")
+	require.NoError(t, err)
+	require.Len(t, bgs, 1)
+	assert.Equal(t, b.Names.BackendGroupForSvcPort(k8s.NamespacedNameOf(svc), 9090), bgs[0].Name)
+
+	b.Cli = fake.NewClientBuilder().WithScheme(scheme.Scheme).Build()
+	_, err = b.BuildForSvc(ctx, svc, ingWithPort(networking.ServiceBackendPort{Name: "named"}), "target-group-id")
+	assert.Error(t, err, "named target port can't be resolved without pods")
+
+	delete(svc.Annotations, k8s.TargetMode)
+	_, err = b.BuildForSvc(ctx, svc, ingWithPort(networking.ServiceBackendPort{Name: "http"}), "target-group-id")
+	assert.Error(t, err, "ClusterIP service can't be used with node targets")
+}
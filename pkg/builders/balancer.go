@@ -34,7 +34,7 @@ func (b *BalancerBuilder) Build(handler *apploadbalancer.HttpHandler, matches []
 
 func (b *BalancerBuilder) listenerSpecs(handler *apploadbalancer.HttpHandler, matches []*apploadbalancer.SniMatch, tag string, opts Options) []*apploadbalancer.Listener {
 	var ret []*apploadbalancer.Listener
-	if handler != nil {
+	if http := httpListener(handler, matches, opts); http != nil {
 		ret = append(ret, &apploadbalancer.Listener{
 			Name: b.names.Listener(tag),
 			Endpoints: []*apploadbalancer.Endpoint{{
@@ -42,9 +42,7 @@ func (b *BalancerBuilder) listenerSpecs(handler *apploadbalancer.HttpHandler, ma
 				Ports:     []int64{80},
 			}},
 			Listener: &apploadbalancer.Listener_Http{
-				Http: &apploadbalancer.HttpListener{
-					Handler: handler,
-				},
+				Http: http,
 			},
 		})
 	}
@@ -63,5 +61,90 @@ func (b *BalancerBuilder) listenerSpecs(handler *apploadbalancer.HttpHandler, ma
 			},
 		})
 	}
+	for _, cl := range opts.CustomListeners {
+		if l := b.customListenerSpec(handler, matches, tag, cl, opts); l != nil {
+			ret = append(ret, l)
+		}
+	}
+	for _, sl := range opts.StreamListeners {
+		ret = append(ret, &apploadbalancer.Listener{
+			Name: b.names.ListenerStream(tag, sl.Port),
+			Endpoints: []*apploadbalancer.Endpoint{{
+				Addresses: opts.Addresses,
+				Ports:     []int64{sl.Port},
+			}},
+			Listener: &apploadbalancer.Listener_Stream{
+				Stream: &apploadbalancer.StreamListener{
+					Handler: &apploadbalancer.StreamHandler{
+						BackendGroupId: sl.BackendGroupID,
+					},
+				},
+			},
+		})
+	}
 	return ret
 }
+
+// customListenerSpec returns nil if the group has no hosts served by the listener
+func (b *BalancerBuilder) customListenerSpec(handler *apploadbalancer.HttpHandler, matches []*apploadbalancer.SniMatch, tag string,
+	cl CustomListener, opts Options,
+) *apploadbalancer.Listener {
+	ret := &apploadbalancer.Listener{
+		Name: b.names.ListenerCustom(tag, cl.Port),
+		Endpoints: []*apploadbalancer.Endpoint{{
+			Addresses: opts.Addresses,
+			Ports:     []int64{cl.Port},
+		}},
+	}
+
+	switch {
+	case cl.TLS:
+		if len(matches) == 0 {
+			return nil
+		}
+		ret.Listener = &apploadbalancer.Listener_Tls{
+			Tls: &apploadbalancer.TlsListener{
+				DefaultHandler: matches[0].GetHandler(),
+				SniHandlers:    matches,
+			},
+		}
+	case cl.TLSRouter:
+		// handler of TLS router is shared with SNI matches, so it gets router ID when the latter are injected with it
+		if len(matches) == 0 {
+			return nil
+		}
+		ret.Listener = &apploadbalancer.Listener_Http{
+			Http: &apploadbalancer.HttpListener{
+				Handler: matches[0].GetHandler().GetHttpHandler(),
+			},
+		}
+	default:
+		http := httpListener(handler, matches, opts)
+		if http == nil {
+			return nil
+		}
+		ret.Listener = &apploadbalancer.Listener_Http{
+			Http: http,
+		}
+	}
+	return ret
+}
+
+// httpListener returns listener serving requests with HTTP router or redirecting them to HTTPS listener,
+// nil if the group has no HTTP hosts or no TLS hosts to redirect to
+func httpListener(handler *apploadbalancer.HttpHandler, matches []*apploadbalancer.SniMatch, opts Options) *apploadbalancer.HttpListener {
+	if opts.RedirectHTTPToHTTPS {
+		if len(matches) == 0 {
+			return nil
+		}
+		return &apploadbalancer.HttpListener{
+			Redirects: &apploadbalancer.Redirects{HttpToHttps: true},
+		}
+	}
+	if handler == nil {
+		return nil
+	}
+	return &apploadbalancer.HttpListener{
+		Handler: handler,
+	}
+}
@@ -0,0 +1,77 @@
+package builders
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yandex-cloud/go-genproto/yandex/cloud/apploadbalancer/v1"
+
+	"github.com/yandex-cloud/yc-alb-ingress-controller/pkg/metadata"
+)
+
+func TestBalancerBuilder_CustomListeners(t *testing.T) {
+	names := &metadata.Names{ClusterID: "my-cluster"}
+	f := NewFactory("my-folder", "", names, &metadata.Labels{ClusterID: "my-cluster"}, nil, nil)
+
+	handler := &apploadbalancer.HttpHandler{HttpRouterId: "http-router"}
+	tlsHandler := &apploadbalancer.HttpHandler{HttpRouterId: "tls-router"}
+	matches := []*apploadbalancer.SniMatch{{
+		Name:        "sni",
+		ServerNames: []string{"example.com"},
+		Handler: &apploadbalancer.TlsHandler{
+			Handler:        &apploadbalancer.TlsHandler_HttpHandler{HttpHandler: tlsHandler},
+			CertificateIds: []string{"cert"},
+		},
+	}}
+	opts := Options{ListenerOptions: ListenerOptions{
+		CustomListeners: []CustomListener{
+			{Port: 8080},
+			{Port: 8081, TLSRouter: true},
+			{Port: 8443, TLS: true},
+		},
+	}}
+
+	t.Run("all hosts", func(t *testing.T) {
+		balancer := f.BalancerBuilder("tag").Build(handler, matches, nil, opts)
+		require.Len(t, balancer.Listeners, 5)
+
+		http, tlsRouter, tls := balancer.Listeners[2], balancer.Listeners[3], balancer.Listeners[4]
+		assert.Equal(t, names.ListenerCustom("tag", 8080), http.Name)
+		assert.Equal(t, []int64{8080}, http.Endpoints[0].Ports)
+		assert.Same(t, handler, http.GetHttp().GetHandler())
+
+		assert.Equal(t, names.ListenerCustom("tag", 8081), tlsRouter.Name)
+		assert.Same(t, tlsHandler, tlsRouter.GetHttp().GetHandler())
+
+		assert.Equal(t, names.ListenerCustom("tag", 8443), tls.Name)
+		assert.Equal(t, []int64{8443}, tls.Endpoints[0].Ports)
+		assert.Equal(t, matches, tls.GetTls().GetSniHandlers())
+		assert.Same(t, matches[0].Handler, tls.GetTls().GetDefaultHandler())
+	})
+
+	t.Run("no tls hosts", func(t *testing.T) {
+		balancer := f.BalancerBuilder("tag").Build(handler, nil, nil, opts)
+		require.Len(t, balancer.Listeners, 2)
+		assert.Equal(t, names.Listener("tag"), balancer.Listeners[0].Name)
+		assert.Equal(t, names.ListenerCustom("tag", 8080), balancer.Listeners[1].Name)
+	})
+	t.Run("redirect to https", func(t *testing.T) {
+		opts := opts
+		opts.RedirectHTTPToHTTPS = true
+		balancer := f.BalancerBuilder("tag").Build(nil, matches, nil, opts)
+		require.Len(t, balancer.Listeners, 5)
+
+		redirects := &apploadbalancer.Redirects{HttpToHttps: true}
+		assert.Equal(t, names.Listener("tag"), balancer.Listeners[0].Name)
+		assert.Equal(t, redirects, balancer.Listeners[0].GetHttp().GetRedirects())
+		assert.Nil(t, balancer.Listeners[0].GetHttp().GetHandler())
+		assert.Equal(t, redirects, balancer.Listeners[2].GetHttp().GetRedirects())
+		assert.Same(t, tlsHandler, balancer.Listeners[3].GetHttp().GetHandler())
+	})
+	t.Run("redirect to https without tls hosts", func(t *testing.T) {
+		opts := Options{ListenerOptions: ListenerOptions{RedirectHTTPToHTTPS: true}}
+		balancer := f.BalancerBuilder("tag").Build(nil, nil, nil, opts)
+		assert.Empty(t, balancer.Listeners)
+	})
+}
@@ -2,6 +2,8 @@ package builders
 
 import (
 	"github.com/yandex-cloud/go-genproto/yandex/cloud/apploadbalancer/v1"
+
+	"github.com/yandex-cloud/yc-alb-ingress-controller/pkg/k8s"
 )
 
 type Data struct {
@@ -13,6 +15,9 @@ type Data struct {
 	SNIMatches    []*apploadbalancer.SniMatch
 	Balancer      *apploadbalancer.LoadBalancer
 	LogOptions    *apploadbalancer.LogOptions
+
+	// Warnings about ingresses of the group which don't fail the build, e.g. ignored group-wide annotations
+	Warnings []k8s.GroupWarning
 }
 
 /* TODO: the injection of IDs after deployment is ugly
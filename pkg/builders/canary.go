@@ -0,0 +1,146 @@
+package builders
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/golang/protobuf/ptypes/wrappers"
+	"github.com/yandex-cloud/go-genproto/yandex/cloud/apploadbalancer/v1"
+	core "k8s.io/api/core/v1"
+	networking "k8s.io/api/networking/v1"
+
+	"github.com/yandex-cloud/yc-alb-ingress-controller/pkg/k8s"
+)
+
+const maxCanaryWeight = 100
+
+// CanaryOpts describes the share of traffic which canary ingress takes from the primary one.
+// Weight is a percentage of requests routed to the canary service.
+type CanaryOpts struct {
+	Enabled bool
+	Weight  int64
+}
+
+type CanaryResolver struct{}
+
+func (r CanaryResolver) Resolve(canary, weight string) (CanaryOpts, error) {
+	var ret CanaryOpts
+	switch canary {
+	case "true":
+		ret.Enabled = true
+	case "false", "":
+		ret.Enabled = false
+	default:
+		return CanaryOpts{}, fmt.Errorf("unsupported canary flag format %s", canary)
+	}
+
+	if weight == "" {
+		return ret, nil
+	}
+	if !ret.Enabled {
+		return CanaryOpts{}, fmt.Errorf("canary weight is specified for non-canary ingress")
+	}
+
+	w, err := strconv.ParseInt(weight, 10, 64)
+	if err != nil {
+		return CanaryOpts{}, fmt.Errorf("failed to parse canary weight: %w", err)
+	}
+	if w < 0 || w > maxCanaryWeight {
+		return CanaryOpts{}, fmt.Errorf("canary weight must be in range [0, %d], found: %d", maxCanaryWeight, w)
+	}
+	ret.Weight = w
+	return ret, nil
+}
+
+// ServiceBackendsByHostAndPath returns service backends of ingress rules keyed by their host and path
+func ServiceBackendsByHostAndPath(ing networking.Ingress) (map[HostAndPath]networking.IngressServiceBackend, error) {
+	useRegex := ing.GetAnnotations()[k8s.UseRegex] == "true"
+
+	ret := make(map[HostAndPath]networking.IngressServiceBackend)
+	for _, rule := range ing.Spec.Rules {
+		if rule.HTTP == nil {
+			continue
+		}
+		for _, path := range rule.HTTP.Paths {
+			if path.Backend.Service == nil {
+				continue
+			}
+			hp, err := HTTPIngressPathToHostAndPath(rule.Host, path, useRegex)
+			if err != nil {
+				return nil, err
+			}
+			ret[hp] = *path.Backend.Service
+		}
+	}
+	return ret, nil
+}
+
+// CanaryBackendData describes service backend which is merged into canary backend group
+type CanaryBackendData struct {
+	Svc           *core.Service
+	Port          core.ServicePort
+	Ings          []networking.Ingress
+	TargetGroupID string
+}
+
+// BuildCanary builds backend group which splits traffic of the primary service port between it and the canary one.
+// Canary receives weight percents of requests, the primary service receives the rest.
+func (b *BackendGroupForSvcBuilder) BuildCanary(ctx context.Context, primary, canary CanaryBackendData, weight int64) (*apploadbalancer.BackendGroup, error) {
+	primaryBG, err := b.buildSingleForSvc(ctx, primary)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build primary backend: %w", err)
+	}
+	canaryBG, err := b.buildSingleForSvc(ctx, canary)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build canary backend: %w", err)
+	}
+
+	primaryWeight := &wrappers.Int64Value{Value: maxCanaryWeight - weight}
+	canaryWeight := &wrappers.Int64Value{Value: weight}
+
+	switch {
+	case primaryBG.GetHttp() != nil && canaryBG.GetHttp() != nil:
+		primaryBackend, canaryBackend := primaryBG.GetHttp().Backends[0], canaryBG.GetHttp().Backends[0]
+		primaryBackend.BackendWeight, canaryBackend.BackendWeight = primaryWeight, canaryWeight
+		primaryBG.GetHttp().Backends = append(primaryBG.GetHttp().Backends, canaryBackend)
+	case primaryBG.GetGrpc() != nil && canaryBG.GetGrpc() != nil:
+		primaryBackend, canaryBackend := primaryBG.GetGrpc().Backends[0], canaryBG.GetGrpc().Backends[0]
+		primaryBackend.BackendWeight, canaryBackend.BackendWeight = primaryWeight, canaryWeight
+		primaryBG.GetGrpc().Backends = append(primaryBG.GetGrpc().Backends, canaryBackend)
+	default:
+		return nil, fmt.Errorf("protocols of primary service %s/%s and canary service %s/%s differ",
+			primary.Svc.Namespace, primary.Svc.Name, canary.Svc.Namespace, canary.Svc.Name)
+	}
+
+	primaryPort, err := k8s.BackendPort(ctx, b.Cli, primary.Svc, primary.Port)
+	if err != nil {
+		return nil, err
+	}
+	canaryPort, err := k8s.BackendPort(ctx, b.Cli, canary.Svc, canary.Port)
+	if err != nil {
+		return nil, err
+	}
+
+	primaryBG.Name = b.Names.CanaryBackendGroupForSvcPort(k8s.NamespacedNameOf(canary.Svc), int64(canaryPort))
+	primaryBG.Description = fmt.Sprintf("canary backend group for k8s service %s/%s with port %d and service %s/%s with port %d",
+		primary.Svc.Namespace, primary.Svc.Name, primaryPort, canary.Svc.Namespace, canary.Svc.Name, canaryPort)
+	return primaryBG, nil
+}
+
+func (b *BackendGroupForSvcBuilder) buildSingleForSvc(ctx context.Context, d CanaryBackendData) (*apploadbalancer.BackendGroup, error) {
+	if err := checkServiceType(d.Svc); err != nil {
+		return nil, err
+	}
+
+	opts, err := b.backendOpts(d.Svc, d.Ings)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build backend opts: %w", err)
+	}
+
+	bgs, err := b.buildForSvc(ctx, d.Svc, []core.ServicePort{d.Port}, d.TargetGroupID, opts)
+	if err != nil {
+		return nil, err
+	}
+	return bgs[0], nil
+}
@@ -0,0 +1,199 @@
+package builders
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yandex-cloud/go-genproto/yandex/cloud/apploadbalancer/v1"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+	v12 "k8s.io/api/core/v1"
+	networking "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/yandex-cloud/yc-alb-ingress-controller/pkg/k8s"
+	"github.com/yandex-cloud/yc-alb-ingress-controller/pkg/metadata"
+)
+
+func TestCanaryResolver(t *testing.T) {
+	testData := []struct {
+		desc           string
+		canary, weight string
+		exp            CanaryOpts
+		wantErr        bool
+	}{
+		{
+			desc: "no annotations",
+		},
+		{
+			desc:   "canary without weight",
+			canary: "true",
+			exp:    CanaryOpts{Enabled: true},
+		},
+		{
+			desc:   "canary with weight",
+			canary: "true",
+			weight: "20",
+			exp:    CanaryOpts{Enabled: true, Weight: 20},
+		},
+		{
+			desc:    "weight without canary",
+			weight:  "20",
+			wantErr: true,
+		},
+		{
+			desc:    "bad canary flag",
+			canary:  "yes",
+			wantErr: true,
+		},
+		{
+			desc:    "weight out of range",
+			canary:  "true",
+			weight:  "101",
+			wantErr: true,
+		},
+		{
+			desc:    "bad weight",
+			canary:  "true",
+			weight:  "20%",
+			wantErr: true,
+		},
+	}
+	for _, tc := range testData {
+		t.Run(tc.desc, func(t *testing.T) {
+			ret, err := CanaryResolver{}.Resolve(tc.canary, tc.weight)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.exp, ret)
+		})
+	}
+}
+
+func TestServiceBackendsByHostAndPath(t *testing.T) {
+	prefix := networking.PathTypePrefix
+	ing := networking.Ingress{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "canary"},
+		Spec: networking.IngressSpec{
+			Rules: []networking.IngressRule{
+				{
+					Host: "example.com",
+					IngressRuleValue: networking.IngressRuleValue{HTTP: &networking.HTTPIngressRuleValue{
+						Paths: []networking.HTTPIngressPath{
+							{
+								Path:     "/api",
+								PathType: &prefix,
+								Backend: networking.IngressBackend{Service: &networking.IngressServiceBackend{
+									Name: "api-canary",
+									Port: networking.ServiceBackendPort{Number: 80},
+								}},
+							},
+							{
+								Path:     "/static",
+								PathType: &prefix,
+								Backend: networking.IngressBackend{Resource: &v12.TypedLocalObjectReference{
+									Kind: "HttpBackendGroup",
+									Name: "static",
+								}},
+							},
+						},
+					}},
+				},
+				{Host: "empty.example.com"},
+			},
+		},
+	}
+
+	ret, err := ServiceBackendsByHostAndPath(ing)
+	require.NoError(t, err)
+	assert.Equal(t, map[HostAndPath]networking.IngressServiceBackend{
+		{Host: "example.com", Path: "/api", PathType: "Prefix"}: {
+			Name: "api-canary",
+			Port: networking.ServiceBackendPort{Number: 80},
+		},
+	}, ret)
+}
+
+func TestBackendGroupForSvcBuilder_BuildCanary(t *testing.T) {
+	port := v12.ServicePort{Name: "http", Port: 80, NodePort: 30080}
+	canaryPort := v12.ServicePort{Name: "http", Port: 80, NodePort: 30081}
+	primarySvc := &v12.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "api"},
+		Spec:       v12.ServiceSpec{Type: v12.ServiceTypeNodePort, Ports: []v12.ServicePort{port}},
+	}
+	canarySvc := &v12.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "api-canary"},
+		Spec:       v12.ServiceSpec{Type: v12.ServiceTypeNodePort, Ports: []v12.ServicePort{canaryPort}},
+	}
+	grpcSvc := &v12.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "default",
+			Name:        "api-grpc",
+			Annotations: map[string]string{k8s.Protocol: "grpc"},
+		},
+		Spec: v12.ServiceSpec{Type: v12.ServiceTypeNodePort, Ports: []v12.ServicePort{canaryPort}},
+	}
+
+	names := &metadata.Names{ClusterID: "my-cluster"}
+	b := BackendGroupForSvcBuilder{FolderID: "my-folder", Names: names}
+	backend := func(svc *v12.Service, p v12.ServicePort, tgID string, weight int64) *apploadbalancer.HttpBackend {
+		return &apploadbalancer.HttpBackend{
+			Name:          names.Backend("", svc.Namespace, svc.Name, p.Port, p.NodePort),
+			BackendWeight: &wrapperspb.Int64Value{Value: weight},
+			Port:          int64(p.NodePort),
+			BackendType: &apploadbalancer.HttpBackend_TargetGroups{
+				TargetGroups: &apploadbalancer.TargetGroupsBackend{TargetGroupIds: []string{tgID}},
+			},
+			Healthchecks: defaultHealthChecks,
+		}
+	}
+
+	testData := []struct {
+		desc            string
+		primary, canary CanaryBackendData
+		weight          int64
+		exp             *apploadbalancer.BackendGroup
+		wantErr         bool
+	}{
+		{
+			desc:    "OK",
+			primary: CanaryBackendData{Svc: primarySvc, Port: port, TargetGroupID: "tg-primary"},
+			canary:  CanaryBackendData{Svc: canarySvc, Port: canaryPort, TargetGroupID: "tg-canary"},
+			weight:  20,
+			exp: &apploadbalancer.BackendGroup{
+				Name:        names.CanaryBackendGroupForSvcPort(types.NamespacedName{Namespace: "default", Name: "api-canary"}, 30081),
+				FolderId:    "my-folder",
+				Description: "canary backend group for k8s service default/api with port 30080 and service default/api-canary with port 30081",
+				Backend: &apploadbalancer.BackendGroup_Http{Http: &apploadbalancer.HttpBackendGroup{
+					Backends: []*apploadbalancer.HttpBackend{
+						backend(primarySvc, port, "tg-primary", 80),
+						backend(canarySvc, canaryPort, "tg-canary", 20),
+					},
+				}},
+			},
+		},
+		{
+			desc:    "different protocols",
+			primary: CanaryBackendData{Svc: primarySvc, Port: port, TargetGroupID: "tg-primary"},
+			canary:  CanaryBackendData{Svc: grpcSvc, Port: canaryPort, TargetGroupID: "tg-canary"},
+			weight:  20,
+			wantErr: true,
+		},
+	}
+	for _, tc := range testData {
+		t.Run(tc.desc, func(t *testing.T) {
+			ret, err := b.BuildCanary(context.Background(), tc.primary, tc.canary, tc.weight)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, proto.Equal(tc.exp, ret), "backend groups mismatch\nexp %v\ngot %v", tc.exp, ret)
+		})
+	}
+}
@@ -33,7 +33,7 @@ func (b *GrpcBackendGroupForCrdBuilder) BuildForCrd(
 ) (*apploadbalancer.BackendGroup, error) {
 	var backends []*apploadbalancer.GrpcBackend
 
-	seenSvc := make(map[exposedNodePort]struct{})
+	seenSvc := make(map[exposedBackendPort]struct{})
 
 	for _, bcrd := range bgCR.Spec.Backends {
 		if bcrd.Service != nil {
@@ -64,7 +64,7 @@ func (b *GrpcBackendGroupForCrdBuilder) BuildForCrd(
 }
 
 func (b *GrpcBackendGroupForCrdBuilder) buildGrpcBackendsForService( //nolint:revive
-	ctx context.Context, ns string, seenSvc map[exposedNodePort]struct{}, bgCrd *v1alpha1.GrpcBackend,
+	ctx context.Context, ns string, seenSvc map[exposedBackendPort]struct{}, bgCrd *v1alpha1.GrpcBackend,
 ) ([]*apploadbalancer.GrpcBackend, error) {
 	var svc core.Service
 	err := b.Cli.Get(ctx, types.NamespacedName{
@@ -74,7 +74,11 @@ func (b *GrpcBackendGroupForCrdBuilder) buildGrpcBackendsForService( //nolint:re
 	if err != nil {
 		return nil, fmt.Errorf("failed to get service %s/%s: %w", ns, bgCrd.Service.Name, err)
 	}
-	if svc.Spec.Type != core.ServiceTypeNodePort {
+	podTargets, err := k8s.PodIPTargets(&svc)
+	if err != nil {
+		return nil, err
+	}
+	if !podTargets && svc.Spec.Type != core.ServiceTypeNodePort {
 		return nil, fmt.Errorf("type of service %s/%s used by CR GrpcBackend %s is not NodePort",
 			svc.Namespace, svc.Name, bgCrd.Service.Name)
 	}
@@ -94,24 +98,32 @@ func (b *GrpcBackendGroupForCrdBuilder) buildGrpcBackendsForService( //nolint:re
 		return nil, fmt.Errorf("service %s/%s doesn't expose its port %v",
 			svc.Namespace, svc.Name, ingressBackendPort)
 	}
+	ports, err := backendPorts(ctx, b.Cli, &svc, svcBackendPorts)
+	if err != nil {
+		return nil, err
+	}
 
 	balancingConfig, err := parseBalancingConfigFromCRDConfig(bgCrd.LoadBalancingConfig)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse load balancing config: %w", err)
 	}
+	healthChecks, err := b.buildGrpcHealthChecks(bgCrd, ports)
+	if err != nil {
+		return nil, err
+	}
 
 	var ret []*apploadbalancer.GrpcBackend
-	for _, port := range svcBackendPorts {
-		nodePort := int64(port.NodePort)
-		if _, ok := seenSvc[exposedNodePort{port: nodePort}]; ok {
-			// backend for this service and NodePort has already been added to this backend group
+	for i, port := range svcBackendPorts {
+		backendPort := ports[i]
+		if _, ok := seenSvc[exposedBackendPort{svc: k8s.NamespacedNameOf(&svc), port: backendPort}]; ok {
+			// backend for this service and port has already been added to this backend group
 			continue
 		}
 
 		backend := &apploadbalancer.GrpcBackend{
-			Name:          b.Names.Backend("", svc.Namespace, svc.Name, port.Port, port.NodePort),
+			Name:          b.Names.Backend("", svc.Namespace, svc.Name, port.Port, int32(backendPort)),
 			BackendWeight: &wrappers.Int64Value{Value: bgCrd.Weight},
-			Port:          nodePort,
+			Port:          backendPort,
 			BackendType: &apploadbalancer.GrpcBackend_TargetGroups{
 				TargetGroups: &apploadbalancer.TargetGroupsBackend{
 					TargetGroupIds: []string{
@@ -119,7 +131,7 @@ func (b *GrpcBackendGroupForCrdBuilder) buildGrpcBackendsForService( //nolint:re
 					},
 				},
 			},
-			Healthchecks:        b.buildGrpcHealthChecks(bgCrd, svcBackendPorts),
+			Healthchecks:        healthChecks,
 			LoadBalancingConfig: balancingConfig,
 		}
 		if bgCrd.TLS != nil {
@@ -136,18 +148,22 @@ func (b *GrpcBackendGroupForCrdBuilder) buildGrpcBackendsForService( //nolint:re
 		}
 
 		ret = append(ret, backend)
-		seenSvc[exposedNodePort{port: nodePort}] = struct{}{}
+		seenSvc[exposedBackendPort{svc: k8s.NamespacedNameOf(&svc), port: backendPort}] = struct{}{}
 	}
 	return ret, nil
 }
 
-func (b *GrpcBackendGroupForCrdBuilder) buildGrpcHealthChecks(backend *v1alpha1.GrpcBackend, svcPorts []core.ServicePort) []*apploadbalancer.HealthCheck { //nolint:revive
+func (b *GrpcBackendGroupForCrdBuilder) buildGrpcHealthChecks(backend *v1alpha1.GrpcBackend, ports []int64) ([]*apploadbalancer.HealthCheck, error) { //nolint:revive
 	if len(backend.HealthChecks) == 0 {
-		return defaultHealthChecks
+		return defaultHealthChecks, nil
 	}
 
 	res := make([]*apploadbalancer.HealthCheck, 0, len(backend.HealthChecks))
 	for _, check := range backend.HealthChecks {
+		if check.GRPC == nil {
+			return nil, fmt.Errorf("grpc health check must be specified")
+		}
+
 		var transportSettings apploadbalancer.HealthCheck_TransportSettings
 		if backend.TLS != nil {
 			transportSettings = &apploadbalancer.HealthCheck_Tls{
@@ -181,11 +197,11 @@ func (b *GrpcBackendGroupForCrdBuilder) buildGrpcHealthChecks(backend *v1alpha1.
 				TransportSettings:  transportSettings,
 			})
 		} else {
-			for _, port := range svcPorts {
+			for _, port := range ports {
 				res = append(res, &apploadbalancer.HealthCheck{
 					Timeout:         convertDuration(check.Timeout),
 					Interval:        convertDuration(check.Interval),
-					HealthcheckPort: int64(port.NodePort),
+					HealthcheckPort: port,
 					Healthcheck: &apploadbalancer.HealthCheck_Grpc{
 						Grpc: &apploadbalancer.HealthCheck_GrpcHealthCheck{
 							ServiceName: check.GRPC.ServiceName,
@@ -199,7 +215,7 @@ func (b *GrpcBackendGroupForCrdBuilder) buildGrpcHealthChecks(backend *v1alpha1.
 		}
 	}
 
-	return res
+	return res, nil
 }
 
 func parseGrpcBGSessionAffinity(sa *v1alpha1.SessionAffinity) apploadbalancer.GrpcBackendGroup_SessionAffinity {
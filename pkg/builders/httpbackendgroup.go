@@ -35,7 +35,7 @@ func (b *HttpBackendGroupForCrdBuilder) BuildForCrd(
 ) (*apploadbalancer.BackendGroup, error) {
 	var backends []*apploadbalancer.HttpBackend
 
-	seenSvc := make(map[exposedNodePort]struct{})
+	seenSvc := make(map[exposedBackendPort]struct{})
 	seenBucket := make(map[string]struct{})
 
 	for _, bcrd := range bgCR.Spec.Backends {
@@ -79,7 +79,7 @@ func (b *HttpBackendGroupForCrdBuilder) BuildForCrd(
 }
 
 func (b *HttpBackendGroupForCrdBuilder) buildHttpBackendsForService( //nolint:revive
-	ctx context.Context, ns string, seenSvc map[exposedNodePort]struct{}, bgCrd *v1alpha1.HttpBackend,
+	ctx context.Context, ns string, seenSvc map[exposedBackendPort]struct{}, bgCrd *v1alpha1.HttpBackend,
 ) ([]*apploadbalancer.HttpBackend, error) {
 	var svc core.Service
 	err := b.Cli.Get(ctx, types.NamespacedName{
@@ -89,7 +89,11 @@ func (b *HttpBackendGroupForCrdBuilder) buildHttpBackendsForService( //nolint:re
 	if err != nil {
 		return nil, fmt.Errorf("failed to get service %s/%s: %w", ns, bgCrd.Service.Name, err)
 	}
-	if svc.Spec.Type != core.ServiceTypeNodePort {
+	podTargets, err := k8s.PodIPTargets(&svc)
+	if err != nil {
+		return nil, err
+	}
+	if !podTargets && svc.Spec.Type != core.ServiceTypeNodePort {
 		return nil, fmt.Errorf("type of service %s/%s used by CR HttpBackend %s is not NodePort",
 			svc.Namespace, svc.Name, bgCrd.Service.Name)
 	}
@@ -109,24 +113,32 @@ func (b *HttpBackendGroupForCrdBuilder) buildHttpBackendsForService( //nolint:re
 		return nil, fmt.Errorf("service %s/%s doesn't expose its port %v",
 			svc.Namespace, svc.Name, ingressBackendPort)
 	}
+	ports, err := backendPorts(ctx, b.Cli, &svc, svcBackendPorts)
+	if err != nil {
+		return nil, err
+	}
 
 	balancingConfig, err := parseBalancingConfigFromCRDConfig(bgCrd.LoadBalancingConfig)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse load balancing config: %w", err)
 	}
+	healthChecks, err := b.buildHttpHealthChecks(bgCrd, ports)
+	if err != nil {
+		return nil, err
+	}
 
 	var ret []*apploadbalancer.HttpBackend
-	for _, port := range svcBackendPorts {
-		nodePort := int64(port.NodePort)
-		if _, ok := seenSvc[exposedNodePort{port: nodePort}]; ok {
-			// backend for this service and NodePort has already been added to this backend group
+	for i, port := range svcBackendPorts {
+		backendPort := ports[i]
+		if _, ok := seenSvc[exposedBackendPort{svc: k8s.NamespacedNameOf(&svc), port: backendPort}]; ok {
+			// backend for this service and port has already been added to this backend group
 			continue
 		}
 
 		backend := &apploadbalancer.HttpBackend{
-			Name:          b.Names.Backend("", svc.Namespace, svc.Name, port.Port, port.NodePort),
+			Name:          b.Names.Backend("", svc.Namespace, svc.Name, port.Port, int32(backendPort)),
 			BackendWeight: &wrappers.Int64Value{Value: bgCrd.Weight},
-			Port:          nodePort,
+			Port:          backendPort,
 			BackendType: &apploadbalancer.HttpBackend_TargetGroups{
 				TargetGroups: &apploadbalancer.TargetGroupsBackend{
 					TargetGroupIds: []string{
@@ -134,7 +146,7 @@ func (b *HttpBackendGroupForCrdBuilder) buildHttpBackendsForService( //nolint:re
 					},
 				},
 			},
-			Healthchecks:        b.buildHttpHealthChecks(bgCrd, svcBackendPorts),
+			Healthchecks:        healthChecks,
 			UseHttp2:            bgCrd.UseHTTP2,
 			LoadBalancingConfig: balancingConfig,
 		}
@@ -152,7 +164,7 @@ func (b *HttpBackendGroupForCrdBuilder) buildHttpBackendsForService( //nolint:re
 		}
 
 		ret = append(ret, backend)
-		seenSvc[exposedNodePort{port: nodePort}] = struct{}{}
+		seenSvc[exposedBackendPort{svc: k8s.NamespacedNameOf(&svc), port: backendPort}] = struct{}{}
 	}
 	return ret, nil
 }
@@ -180,13 +192,17 @@ func (b *HttpBackendGroupForCrdBuilder) buildHttpBackendForBucket( //nolint:revi
 	}, nil
 }
 
-func (b *HttpBackendGroupForCrdBuilder) buildHttpHealthChecks(backend *v1alpha1.HttpBackend, svcPorts []core.ServicePort) []*apploadbalancer.HealthCheck { //nolint:revive
+func (b *HttpBackendGroupForCrdBuilder) buildHttpHealthChecks(backend *v1alpha1.HttpBackend, ports []int64) ([]*apploadbalancer.HealthCheck, error) { //nolint:revive
 	if len(backend.HealthChecks) == 0 {
-		return defaultHealthChecks
+		return defaultHealthChecks, nil
 	}
 
 	res := make([]*apploadbalancer.HealthCheck, 0, len(backend.HealthChecks))
 	for _, check := range backend.HealthChecks {
+		if check.HTTP == nil {
+			return nil, fmt.Errorf("http health check must be specified")
+		}
+
 		var transportSettings apploadbalancer.HealthCheck_TransportSettings
 		if backend.TLS != nil {
 			transportSettings = &apploadbalancer.HealthCheck_Tls{
@@ -220,11 +236,11 @@ func (b *HttpBackendGroupForCrdBuilder) buildHttpHealthChecks(backend *v1alpha1.
 				TransportSettings:  transportSettings,
 			})
 		} else {
-			for _, port := range svcPorts {
+			for _, port := range ports {
 				res = append(res, &apploadbalancer.HealthCheck{
 					Timeout:         convertDuration(check.Timeout),
 					Interval:        convertDuration(check.Interval),
-					HealthcheckPort: int64(port.NodePort),
+					HealthcheckPort: port,
 					Healthcheck: &apploadbalancer.HealthCheck_Http{
 						Http: &apploadbalancer.HealthCheck_HttpHealthCheck{
 							Path: check.HTTP.Path,
@@ -238,7 +254,7 @@ func (b *HttpBackendGroupForCrdBuilder) buildHttpHealthChecks(backend *v1alpha1.
 		}
 	}
 
-	return res
+	return res, nil
 }
 
 func parseHttpBGSessionAffinity(sa *v1alpha1.SessionAffinity) apploadbalancer.HttpBackendGroup_SessionAffinity { //nolint:revive
@@ -11,6 +11,7 @@ import (
 	"github.com/yandex-cloud/go-genproto/yandex/cloud/apploadbalancer/v1"
 	"github.com/yandex-cloud/yc-alb-ingress-controller/api/v1alpha1"
 	"github.com/yandex-cloud/yc-alb-ingress-controller/pkg/builders/mocks"
+	"github.com/yandex-cloud/yc-alb-ingress-controller/pkg/k8s"
 	"github.com/yandex-cloud/yc-alb-ingress-controller/pkg/metadata"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/durationpb"
@@ -721,6 +722,36 @@ func TestHttpBackendGroup_BuildForCrd(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			desc: "HealthChecksWithoutHTTP",
+			backendGroup: &v1alpha1.HttpBackendGroup{
+				TypeMeta:   metav1.TypeMeta{Kind: "HttpBackendGroup", APIVersion: "alb.yc.io/v1alpha1"},
+				ObjectMeta: metav1.ObjectMeta{Namespace: "test-ns", Name: "test-bg"},
+				Spec: v1alpha1.HttpBackendGroupSpec{
+					Backends: []*v1alpha1.HttpBackend{
+						{
+							HealthChecks: []*v1alpha1.HealthCheck{
+								{
+									GRPC:               &v1alpha1.GrpcHealthCheck{},
+									UnhealthyThreshold: 5,
+									HealthyThreshold:   5,
+								},
+							},
+							Name:   "svc_back",
+							Weight: 70,
+							Service: &v1alpha1.ServiceBackend{
+								Name: "service1",
+								Port: v1alpha1.ServiceBackendPort{
+									Number: 10001,
+								},
+							},
+						},
+					},
+				},
+			},
+			exp:     nil,
+			wantErr: true,
+		},
 		{
 			desc: "HealthChecksNoPortSpecified",
 			backendGroup: &v1alpha1.HttpBackendGroup{
@@ -914,3 +945,54 @@ func TestHttpBackendGroup_BuildForCrd(t *testing.T) {
 		})
 	}
 }
+
+func TestHttpBackendGroup_BuildForCrd_PodIPTargetsOnSamePort(t *testing.T) {
+	podIPSvc := func(name string) *v12.Service {
+		return &v12.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   "test-ns",
+				Name:        name,
+				Annotations: map[string]string{k8s.TargetMode: k8s.TargetModePodIP},
+			},
+			Spec: v12.ServiceSpec{
+				Type:  v12.ServiceTypeClusterIP,
+				Ports: []v12.ServicePort{{Name: "http", Port: 80, TargetPort: intstr.FromInt(8080)}},
+			},
+		}
+	}
+	svcBackend := func(name string) *v1alpha1.HttpBackend {
+		return &v1alpha1.HttpBackend{
+			Name:    name,
+			Weight:  50,
+			Service: &v1alpha1.ServiceBackend{Name: name, Port: v1alpha1.ServiceBackendPort{Number: 80}},
+		}
+	}
+
+	ctrl := gomock.NewController(t)
+	tgRepo := mocks.NewMockTargetGroupFinder(ctrl)
+	tgRepo.EXPECT().FindTargetGroup(gomock.Any(), gomock.Any()).AnyTimes().Return(&apploadbalancer.TargetGroup{
+		Id: "target-group-id",
+	}, nil)
+
+	b := HttpBackendGroupForCrdBuilder{
+		FolderID: "my-folder",
+		Names:    &metadata.Names{ClusterID: "my-cluster"},
+		Cli:      fake.NewClientBuilder().WithObjects(podIPSvc("svc-a"), podIPSvc("svc-b")).Build(),
+		Repo:     tgRepo,
+	}
+
+	res, err := b.BuildForCrd(context.Background(), &v1alpha1.HttpBackendGroup{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "test-ns", Name: "test-bg"},
+		Spec: v1alpha1.HttpBackendGroupSpec{
+			Backends: []*v1alpha1.HttpBackend{svcBackend("svc-a"), svcBackend("svc-b")},
+		},
+	})
+	require.NoError(t, err)
+
+	backends := res.GetHttp().GetBackends()
+	require.Len(t, backends, 2)
+	for i, name := range []string{"svc-a", "svc-b"} {
+		assert.Equal(t, b.Names.Backend("", "test-ns", name, 80, 8080), backends[i].Name)
+		assert.Equal(t, int64(8080), backends[i].Port)
+	}
+}
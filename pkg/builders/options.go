@@ -10,11 +10,31 @@ type BalancerOptions struct {
 }
 
 type ListenerOptions struct {
-	Addresses []*apploadbalancer.Address
+	Addresses       []*apploadbalancer.Address
+	StreamListeners []StreamListener
+	CustomListeners []CustomListener
+	// RedirectHTTPToHTTPS makes HTTP listeners using HTTP router redirect requests to HTTPS
+	RedirectHTTPToHTTPS bool
+}
+
+type StreamListener struct {
+	Port           int64
+	BackendGroupID string
+}
+
+// CustomListener is HTTP or HTTPS listener on a port other than 80 and 443. HTTPS listener serves TLS hosts of the
+// group, HTTP one serves requests with HTTP router or with TLS router if TLSRouter is set.
+type CustomListener struct {
+	Port      int64
+	TLS       bool
+	TLSRouter bool
 }
 
 type HandlerOptions struct {
 	AllowHTTP10 bool
+	// HTTP2 enables HTTP/2 with the limit of concurrent streams in a connection, unlimited if 0
+	HTTP2                     bool
+	HTTP2MaxConcurrentStreams int64
 }
 
 type Options struct {
@@ -0,0 +1,200 @@
+package builders
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/yandex-cloud/go-genproto/yandex/cloud/apploadbalancer/v1"
+
+	"github.com/yandex-cloud/yc-alb-ingress-controller/api/v1alpha1"
+	"github.com/yandex-cloud/yc-alb-ingress-controller/pkg/k8s"
+)
+
+// BuildRBAC converts RBAC policy of IngressGroupSettings into the balancer one
+func BuildRBAC(rbac *v1alpha1.RBAC) (*apploadbalancer.RBAC, error) {
+	if rbac == nil {
+		return nil, nil
+	}
+
+	action, err := parseRBACAction(rbac.Action)
+	if err != nil {
+		return nil, err
+	}
+	if len(rbac.Principals) == 0 {
+		return nil, fmt.Errorf("rbac principals should be specified")
+	}
+
+	ret := &apploadbalancer.RBAC{Action: action}
+	for i, principals := range rbac.Principals {
+		if len(principals.AndPrincipals) == 0 {
+			return nil, fmt.Errorf("rbac principals #%d: andPrincipals should be specified", i)
+		}
+
+		andPrincipals := make([]*apploadbalancer.Principal, 0, len(principals.AndPrincipals))
+		for j, p := range principals.AndPrincipals {
+			principal, err := buildRBACPrincipal(p)
+			if err != nil {
+				return nil, fmt.Errorf("rbac principals #%d, principal #%d: %w", i, j, err)
+			}
+			andPrincipals = append(andPrincipals, principal)
+		}
+		ret.Principals = append(ret.Principals, &apploadbalancer.Principals{AndPrincipals: andPrincipals})
+	}
+
+	return ret, nil
+}
+
+func buildRBACPrincipal(p v1alpha1.RBACPrincipal) (*apploadbalancer.Principal, error) {
+	count := 0
+	var ret *apploadbalancer.Principal
+	if p.Header != nil {
+		count++
+		matcher, err := buildRBACHeaderMatcher(p.Header)
+		if err != nil {
+			return nil, err
+		}
+		ret = &apploadbalancer.Principal{Identifier: &apploadbalancer.Principal_Header{Header: matcher}}
+	}
+	if p.RemoteIP != "" {
+		count++
+		if err := validateRemoteIP(p.RemoteIP); err != nil {
+			return nil, err
+		}
+		ret = remoteIPPrincipal(p.RemoteIP)
+	}
+	if p.Any {
+		count++
+		ret = &apploadbalancer.Principal{Identifier: &apploadbalancer.Principal_Any{Any: true}}
+	}
+
+	if count != 1 {
+		return nil, fmt.Errorf("exactly one of header, remoteIP and any should be specified")
+	}
+	return ret, nil
+}
+
+func buildRBACHeaderMatcher(h *v1alpha1.RBACHeaderMatcher) (*apploadbalancer.Principal_HeaderMatcher, error) {
+	if h.Name == "" {
+		return nil, fmt.Errorf("header name should be specified")
+	}
+
+	var match apploadbalancer.StringMatch_Match
+	count := 0
+	if h.Exact != "" {
+		count++
+		match = &apploadbalancer.StringMatch_ExactMatch{ExactMatch: h.Exact}
+	}
+	if h.Prefix != "" {
+		count++
+		match = &apploadbalancer.StringMatch_PrefixMatch{PrefixMatch: h.Prefix}
+	}
+	if h.Regex != "" {
+		count++
+		if _, err := regexp.Compile(h.Regex); err != nil {
+			return nil, fmt.Errorf("invalid regex for header %s: %w", h.Name, err)
+		}
+		match = &apploadbalancer.StringMatch_RegexMatch{RegexMatch: h.Regex}
+	}
+	if count > 1 {
+		return nil, fmt.Errorf("no more than one of exact, prefix and regex should be specified for header %s", h.Name)
+	}
+
+	ret := &apploadbalancer.Principal_HeaderMatcher{Name: h.Name}
+	if match != nil {
+		ret.Value = &apploadbalancer.StringMatch{Match: match}
+	}
+	return ret, nil
+}
+
+func parseRBACAction(action string) (apploadbalancer.RBAC_Action, error) {
+	switch strings.ToUpper(action) {
+	case "ALLOW", "":
+		return apploadbalancer.RBAC_ALLOW, nil
+	case "DENY":
+		return apploadbalancer.RBAC_DENY, nil
+	default:
+		return apploadbalancer.RBAC_ACTION_UNSPECIFIED, fmt.Errorf("rbac action must be allow or deny, found: %s", action)
+	}
+}
+
+func validateRemoteIP(s string) error {
+	if strings.Contains(s, "/") {
+		if _, _, err := net.ParseCIDR(s); err != nil {
+			return fmt.Errorf("invalid remote ip CIDR %s: %w", s, err)
+		}
+		return nil
+	}
+	if net.ParseIP(s) == nil {
+		return fmt.Errorf("invalid remote ip %s", s)
+	}
+	return nil
+}
+
+func remoteIPPrincipal(ip string) *apploadbalancer.Principal {
+	return &apploadbalancer.Principal{Identifier: &apploadbalancer.Principal_RemoteIp{RemoteIp: ip}}
+}
+
+// RBACResolver builds RBAC policy from ingress annotations.
+// A request matches the policy if its remote address belongs to any of remote IPs (when specified)
+// and it has all the headers with exactly the specified values.
+type RBACResolver struct{}
+
+func (r RBACResolver) Resolve(action, remoteIPs, headers string) (*apploadbalancer.RBAC, error) {
+	if action == "" && remoteIPs == "" && headers == "" {
+		return nil, nil
+	}
+
+	rbacAction, err := parseRBACAction(action)
+	if err != nil {
+		return nil, err
+	}
+
+	headerValues, err := k8s.ParseConfigsFromAnnotationValue(headers)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse rbac headers: %w", err)
+	}
+	names := make([]string, 0, len(headerValues))
+	for name := range headerValues {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var headerPrincipals []*apploadbalancer.Principal
+	for _, name := range names {
+		matcher, err := buildRBACHeaderMatcher(&v1alpha1.RBACHeaderMatcher{Name: name, Exact: headerValues[name]})
+		if err != nil {
+			return nil, err
+		}
+		headerPrincipals = append(headerPrincipals, &apploadbalancer.Principal{
+			Identifier: &apploadbalancer.Principal_Header{Header: matcher},
+		})
+	}
+
+	var ips []string
+	if remoteIPs != "" {
+		ips = strings.Split(remoteIPs, sep)
+	}
+	for _, ip := range ips {
+		if err := validateRemoteIP(ip); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(ips) == 0 && len(headerPrincipals) == 0 {
+		return nil, fmt.Errorf("rbac remote ips or headers should be specified")
+	}
+
+	ret := &apploadbalancer.RBAC{Action: rbacAction}
+	if len(ips) == 0 {
+		ret.Principals = []*apploadbalancer.Principals{{AndPrincipals: headerPrincipals}}
+		return ret, nil
+	}
+	for _, ip := range ips {
+		andPrincipals := append([]*apploadbalancer.Principal{remoteIPPrincipal(ip)}, headerPrincipals...)
+		ret.Principals = append(ret.Principals, &apploadbalancer.Principals{AndPrincipals: andPrincipals})
+	}
+	return ret, nil
+}
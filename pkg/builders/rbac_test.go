@@ -0,0 +1,179 @@
+package builders
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yandex-cloud/go-genproto/yandex/cloud/apploadbalancer/v1"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/yandex-cloud/yc-alb-ingress-controller/api/v1alpha1"
+)
+
+func headerPrincipal(name, exact string) *apploadbalancer.Principal {
+	ret := &apploadbalancer.Principal_HeaderMatcher{Name: name}
+	if exact != "" {
+		ret.Value = &apploadbalancer.StringMatch{Match: &apploadbalancer.StringMatch_ExactMatch{ExactMatch: exact}}
+	}
+	return &apploadbalancer.Principal{Identifier: &apploadbalancer.Principal_Header{Header: ret}}
+}
+
+func TestRBACResolver(t *testing.T) {
+	testData := []struct {
+		desc                      string
+		action, remoteIPs, header string
+		exp                       *apploadbalancer.RBAC
+		wantErr                   bool
+	}{
+		{
+			desc: "no annotations",
+		},
+		{
+			desc:      "remote ips",
+			remoteIPs: "10.0.0.0/8,192.168.1.1",
+			exp: &apploadbalancer.RBAC{
+				Action: apploadbalancer.RBAC_ALLOW,
+				Principals: []*apploadbalancer.Principals{
+					{AndPrincipals: []*apploadbalancer.Principal{remoteIPPrincipal("10.0.0.0/8")}},
+					{AndPrincipals: []*apploadbalancer.Principal{remoteIPPrincipal("192.168.1.1")}},
+				},
+			},
+		},
+		{
+			desc:   "headers",
+			action: "deny",
+			header: "X-Team=ops,X-Internal=true",
+			exp: &apploadbalancer.RBAC{
+				Action: apploadbalancer.RBAC_DENY,
+				Principals: []*apploadbalancer.Principals{
+					{AndPrincipals: []*apploadbalancer.Principal{headerPrincipal("X-Internal", "true"), headerPrincipal("X-Team", "ops")}},
+				},
+			},
+		},
+		{
+			desc:      "remote ips and headers",
+			action:    "allow",
+			remoteIPs: "10.0.0.0/8,172.16.0.0/12",
+			header:    "X-Internal=true",
+			exp: &apploadbalancer.RBAC{
+				Action: apploadbalancer.RBAC_ALLOW,
+				Principals: []*apploadbalancer.Principals{
+					{AndPrincipals: []*apploadbalancer.Principal{remoteIPPrincipal("10.0.0.0/8"), headerPrincipal("X-Internal", "true")}},
+					{AndPrincipals: []*apploadbalancer.Principal{remoteIPPrincipal("172.16.0.0/12"), headerPrincipal("X-Internal", "true")}},
+				},
+			},
+		},
+		{
+			desc:    "action without principals",
+			action:  "allow",
+			wantErr: true,
+		},
+		{
+			desc:      "unknown action",
+			action:    "reject",
+			remoteIPs: "10.0.0.0/8",
+			wantErr:   true,
+		},
+		{
+			desc:      "bad cidr",
+			remoteIPs: "10.0.0.0/33",
+			wantErr:   true,
+		},
+		{
+			desc:    "bad headers",
+			header:  "X-Internal",
+			wantErr: true,
+		},
+	}
+	for _, tc := range testData {
+		t.Run(tc.desc, func(t *testing.T) {
+			ret, err := RBACResolver{}.Resolve(tc.action, tc.remoteIPs, tc.header)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.True(t, proto.Equal(tc.exp, ret), "rbac mismatch\nexp %v\ngot %v", tc.exp, ret)
+		})
+	}
+}
+
+func TestBuildRBAC(t *testing.T) {
+	testData := []struct {
+		desc    string
+		rbac    *v1alpha1.RBAC
+		exp     *apploadbalancer.RBAC
+		wantErr bool
+	}{
+		{
+			desc: "nil",
+		},
+		{
+			desc: "OK",
+			rbac: &v1alpha1.RBAC{
+				Action: "DENY",
+				Principals: []v1alpha1.RBACPrincipals{
+					{AndPrincipals: []v1alpha1.RBACPrincipal{
+						{RemoteIP: "10.0.0.0/8"},
+						{Header: &v1alpha1.RBACHeaderMatcher{Name: "X-Internal"}},
+					}},
+					{AndPrincipals: []v1alpha1.RBACPrincipal{
+						{Header: &v1alpha1.RBACHeaderMatcher{Name: "User-Agent", Regex: "^curl/.*"}},
+					}},
+					{AndPrincipals: []v1alpha1.RBACPrincipal{{Any: true}}},
+				},
+			},
+			exp: &apploadbalancer.RBAC{
+				Action: apploadbalancer.RBAC_DENY,
+				Principals: []*apploadbalancer.Principals{
+					{AndPrincipals: []*apploadbalancer.Principal{remoteIPPrincipal("10.0.0.0/8"), headerPrincipal("X-Internal", "")}},
+					{AndPrincipals: []*apploadbalancer.Principal{{
+						Identifier: &apploadbalancer.Principal_Header{Header: &apploadbalancer.Principal_HeaderMatcher{
+							Name:  "User-Agent",
+							Value: &apploadbalancer.StringMatch{Match: &apploadbalancer.StringMatch_RegexMatch{RegexMatch: "^curl/.*"}},
+						}},
+					}}},
+					{AndPrincipals: []*apploadbalancer.Principal{{Identifier: &apploadbalancer.Principal_Any{Any: true}}}},
+				},
+			},
+		},
+		{
+			desc:    "no principals",
+			rbac:    &v1alpha1.RBAC{Action: "ALLOW"},
+			wantErr: true,
+		},
+		{
+			desc: "several identifiers in principal",
+			rbac: &v1alpha1.RBAC{
+				Action: "ALLOW",
+				Principals: []v1alpha1.RBACPrincipals{
+					{AndPrincipals: []v1alpha1.RBACPrincipal{{RemoteIP: "10.0.0.0/8", Any: true}}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			desc: "several header matches",
+			rbac: &v1alpha1.RBAC{
+				Action: "ALLOW",
+				Principals: []v1alpha1.RBACPrincipals{
+					{AndPrincipals: []v1alpha1.RBACPrincipal{
+						{Header: &v1alpha1.RBACHeaderMatcher{Name: "X-Internal", Exact: "true", Prefix: "t"}},
+					}},
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tc := range testData {
+		t.Run(tc.desc, func(t *testing.T) {
+			ret, err := BuildRBAC(tc.rbac)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.True(t, proto.Equal(tc.exp, ret), "rbac mismatch\nexp %v\ngot %v", tc.exp, ret)
+		})
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 
 	"github.com/yandex-cloud/go-genproto/yandex/cloud/apploadbalancer/v1"
 	"github.com/yandex-cloud/go-genproto/yandex/cloud/vpc/v1"
@@ -54,6 +55,22 @@ func (r *Resolvers) AllowHTTP10() *AllowHTTP10Resolver {
 	return &AllowHTTP10Resolver{}
 }
 
+func (r *Resolvers) HTTP2() *HTTP2Resolver {
+	return &HTTP2Resolver{}
+}
+
+func (r *Resolvers) StreamListeners() *StreamListenersResolver {
+	return &StreamListenersResolver{backendGroups: make(map[int64]types.NamespacedName)}
+}
+
+func (r *Resolvers) RBAC() RBACResolver {
+	return RBACResolver{}
+}
+
+func (r *Resolvers) Canary() CanaryResolver {
+	return CanaryResolver{}
+}
+
 func (r *Resolvers) RouteOpts() RouteOptsResolver {
 	return RouteOptsResolver{}
 }
@@ -120,7 +137,7 @@ func parseCookieSessionAffinity(affinity string) (*apploadbalancer.CookieSession
 func parseConnectionSessionAffinity(affinity string) (*apploadbalancer.ConnectionSessionAffinity, error) {
 	m, err := k8s.ParseConfigsFromAnnotationValue(affinity)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("failed to parse connection session affinity: %w", err)
 	}
 
 	sourceIP, ok := m["source-ip"]
@@ -140,7 +157,7 @@ func parseConnectionSessionAffinity(affinity string) (*apploadbalancer.Connectio
 func parseHealthChecks(healthChecks string) ([]*apploadbalancer.HealthCheck, error) {
 	m, err := k8s.ParseConfigsFromAnnotationValue(healthChecks)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse config: %w", err)
+		return nil, fmt.Errorf("failed to parse health checks: %w", err)
 	}
 	healthCheck := healthCheckTemplate()
 
@@ -524,11 +541,96 @@ func (r *AllowHTTP10Resolver) Result() bool {
 	return *r.AllowHTTP10
 }
 
+// HTTP2Resolver resolves HTTP/2 options of the group, values of annotations are expected to be the same
+// for all ingresses of the group
+type HTTP2Resolver struct {
+	HTTP2                bool
+	MaxConcurrentStreams int64
+}
+
+func (r *HTTP2Resolver) Resolve(http2, maxConcurrentStreams string) error {
+	switch http2 {
+	case "":
+	case "true":
+		r.HTTP2 = true
+	case "false":
+		r.HTTP2 = false
+	default:
+		return fmt.Errorf("unsupported value for http2: %s", http2)
+	}
+
+	if maxConcurrentStreams == "" {
+		return nil
+	}
+	if !r.HTTP2 {
+		return fmt.Errorf("max concurrent streams %s is set with http2 disabled", maxConcurrentStreams)
+	}
+	n, err := strconv.ParseInt(maxConcurrentStreams, 10, 64)
+	if err != nil || n < 0 {
+		return fmt.Errorf("invalid value for http2 max concurrent streams: %s", maxConcurrentStreams)
+	}
+	r.MaxConcurrentStreams = n
+	return nil
+}
+
+func (r *HTTP2Resolver) Result() (bool, int64) {
+	return r.HTTP2, r.MaxConcurrentStreams
+}
+
+type StreamListenerData struct {
+	Port         int64
+	BackendGroup types.NamespacedName
+}
+
+// StreamListenersResolver collects stream listeners declared by the ingresses of a group.
+// Each listener forwards the connections accepted on its port to a StreamBackendGroup
+// from the namespace of the ingress which declared it.
+type StreamListenersResolver struct {
+	backendGroups map[int64]types.NamespacedName
+}
+
+func (r *StreamListenersResolver) Resolve(ns, streamListeners string) error {
+	m, err := k8s.ParseConfigsFromAnnotationValue(streamListeners)
+	if err != nil {
+		return fmt.Errorf("failed to parse stream listeners: %w", err)
+	}
+
+	for portStr, bgName := range m {
+		port, err := strconv.ParseInt(portStr, 10, 64)
+		if err != nil || port <= 0 || port > 65535 {
+			return fmt.Errorf("stream listener port must be a number between 1 and 65535, found: %s", portStr)
+		}
+		if port == 80 || port == 443 {
+			return fmt.Errorf("stream listener port %d is reserved for http and https listeners", port)
+		}
+		if bgName == "" {
+			return fmt.Errorf("stream backend group should be specified for stream listener port %d", port)
+		}
+
+		bg := types.NamespacedName{Namespace: ns, Name: bgName}
+		if prev, ok := r.backendGroups[port]; ok && prev != bg {
+			return fmt.Errorf("different stream backend groups provided for port %d: %s, %s", port, prev, bg)
+		}
+		r.backendGroups[port] = bg
+	}
+
+	return nil
+}
+
+func (r *StreamListenersResolver) Result() []StreamListenerData {
+	ret := make([]StreamListenerData, 0, len(r.backendGroups))
+	for port, bg := range r.backendGroups {
+		ret = append(ret, StreamListenerData{Port: port, BackendGroup: bg})
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].Port < ret[j].Port })
+	return ret
+}
+
 type RouteOptsResolver struct{}
 
 func (r RouteOptsResolver) Resolve(
-	timeout, idleTimeout, prefixRewrite, upgradeTypes,
-	proto, useRegex, allowedMethods string,
+	timeout, idleTimeout, prefixRewrite, hostRewrite, autoHostRewrite, upgradeTypes,
+	proto, useRegex, allowedMethods, securityProfileID string,
 ) (RouteResolveOpts, error) {
 	var ret RouteResolveOpts
 	if len(timeout) > 0 {
@@ -549,6 +651,19 @@ func (r RouteOptsResolver) Resolve(
 
 	ret.PrefixRewrite = prefixRewrite
 
+	ret.HostRewrite = hostRewrite
+	switch autoHostRewrite {
+	case "true":
+		ret.AutoHostRewrite = true
+	case "false", "":
+		ret.AutoHostRewrite = false
+	default:
+		return RouteResolveOpts{}, fmt.Errorf("unsupported autoHostRewrite flag format %s", autoHostRewrite)
+	}
+	if ret.AutoHostRewrite && ret.HostRewrite != "" {
+		return RouteResolveOpts{}, fmt.Errorf("host rewrite and auto host rewrite can't be specified together")
+	}
+
 	if len(upgradeTypes) > 0 {
 		ret.UpgradeTypes = strings.Split(upgradeTypes, ",")
 	}
@@ -577,6 +692,8 @@ func (r RouteOptsResolver) Resolve(
 		ret.AllowedMethods = strings.Split(allowedMethods, ",")
 	}
 
+	ret.SecurityProfileID = securityProfileID
+
 	return ret, nil
 }
 
@@ -595,22 +712,22 @@ func (r *VirtualHostOptsResolver) Resolve(
 
 		ret.Append, err = k8s.ParseModifyHeadersFromAnnotationValue(appendHeader)
 		if err != nil {
-			return ModifyHeaderOpts{}, err
+			return ModifyHeaderOpts{}, fmt.Errorf("failed to parse append header: %w", err)
 		}
 
 		ret.Rename, err = k8s.ParseModifyHeadersFromAnnotationValue(renameHeader)
 		if err != nil {
-			return ModifyHeaderOpts{}, err
+			return ModifyHeaderOpts{}, fmt.Errorf("failed to parse rename header: %w", err)
 		}
 
 		ret.Replace, err = k8s.ParseModifyHeadersFromAnnotationValue(replaceHeader)
 		if err != nil {
-			return ModifyHeaderOpts{}, err
+			return ModifyHeaderOpts{}, fmt.Errorf("failed to parse replace header: %w", err)
 		}
 
 		removeOpts, err := k8s.ParseModifyHeadersFromAnnotationValue(removeHeader)
 		if err != nil {
-			return ModifyHeaderOpts{}, err
+			return ModifyHeaderOpts{}, fmt.Errorf("failed to parse remove header: %w", err)
 		}
 
 		if len(removeOpts) > 0 {
@@ -11,6 +11,7 @@ import (
 	"github.com/yandex-cloud/go-genproto/yandex/cloud/vpc/v1"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/durationpb"
+	"k8s.io/apimachinery/pkg/types"
 
 	"github.com/yandex-cloud/yc-alb-ingress-controller/pkg/builders/mocks"
 )
@@ -333,6 +334,144 @@ func TestAutoScalePolicy(t *testing.T) {
 	}
 }
 
+func TestStreamListeners(t *testing.T) {
+	type ingressData struct {
+		ns    string
+		value string
+	}
+	testData := []struct {
+		desc   string
+		ings   []ingressData
+		exp    []StreamListenerData
+		expErr bool
+	}{
+		{
+			desc: "OK",
+			ings: []ingressData{
+				{ns: "db", value: "5432=postgres"},
+				{ns: "iot", value: "8883=mqtt,1883=mqtt"},
+				{ns: "other", value: ""},
+			},
+			exp: []StreamListenerData{
+				{Port: 1883, BackendGroup: types.NamespacedName{Namespace: "iot", Name: "mqtt"}},
+				{Port: 5432, BackendGroup: types.NamespacedName{Namespace: "db", Name: "postgres"}},
+				{Port: 8883, BackendGroup: types.NamespacedName{Namespace: "iot", Name: "mqtt"}},
+			},
+		},
+		{
+			desc: "same listener in several ingresses",
+			ings: []ingressData{
+				{ns: "db", value: "5432=postgres"},
+				{ns: "db", value: "5432=postgres"},
+			},
+			exp: []StreamListenerData{
+				{Port: 5432, BackendGroup: types.NamespacedName{Namespace: "db", Name: "postgres"}},
+			},
+		},
+		{
+			desc: "conflicting backend groups",
+			ings: []ingressData{
+				{ns: "db", value: "5432=postgres"},
+				{ns: "db2", value: "5432=postgres"},
+			},
+			expErr: true,
+		},
+		{
+			desc:   "reserved port",
+			ings:   []ingressData{{ns: "db", value: "443=postgres"}},
+			expErr: true,
+		},
+		{
+			desc:   "bad port",
+			ings:   []ingressData{{ns: "db", value: "pg=postgres"}},
+			expErr: true,
+		},
+		{
+			desc:   "no backend group",
+			ings:   []ingressData{{ns: "db", value: "5432="}},
+			expErr: true,
+		},
+	}
+	resolvers := &Resolvers{}
+	for _, tc := range testData {
+		t.Run(tc.desc, func(t *testing.T) {
+			r := resolvers.StreamListeners()
+			var err error
+			for _, ing := range tc.ings {
+				err = r.Resolve(ing.ns, ing.value)
+				if err != nil {
+					break
+				}
+			}
+			if tc.expErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.exp, r.Result())
+		})
+	}
+}
+
+func TestHTTP2Resolver(t *testing.T) {
+	testData := []struct {
+		desc                 string
+		http2                string
+		maxConcurrentStreams string
+		expHTTP2             bool
+		expStreams           int64
+		expErr               bool
+	}{
+		{
+			desc: "not set",
+		},
+		{
+			desc:     "enabled",
+			http2:    "true",
+			expHTTP2: true,
+		},
+		{
+			desc:                 "enabled with max concurrent streams",
+			http2:                "true",
+			maxConcurrentStreams: "100",
+			expHTTP2:             true,
+			expStreams:           100,
+		},
+		{
+			desc:                 "max concurrent streams without http2",
+			http2:                "false",
+			maxConcurrentStreams: "100",
+			expErr:               true,
+		},
+		{
+			desc:   "bad http2",
+			http2:  "yes",
+			expErr: true,
+		},
+		{
+			desc:                 "negative max concurrent streams",
+			http2:                "true",
+			maxConcurrentStreams: "-1",
+			expErr:               true,
+		},
+	}
+	resolvers := &Resolvers{}
+	for _, tc := range testData {
+		t.Run(tc.desc, func(t *testing.T) {
+			r := resolvers.HTTP2()
+			err := r.Resolve(tc.http2, tc.maxConcurrentStreams)
+			if tc.expErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			http2, streams := r.Result()
+			assert.Equal(t, tc.expHTTP2, http2)
+			assert.Equal(t, tc.expStreams, streams)
+		})
+	}
+}
+
 func TestVirtualHostOptsResolver_Resolve(t *testing.T) {
 	testData := []struct {
 		desc              string
@@ -429,16 +568,19 @@ func TestVirtualHostOptsResolver_Resolve(t *testing.T) {
 
 func TestRouteOptsResolver(t *testing.T) {
 	testData := []struct {
-		desc           string
-		timeout        string
-		idleTimeout    string
-		prefixRewrite  string
-		upgradeTypes   string
-		proto          string
-		useRegex       string
-		allowedMethods string
-		exp            RouteResolveOpts
-		wantErr        bool
+		desc            string
+		timeout         string
+		idleTimeout     string
+		prefixRewrite   string
+		hostRewrite     string
+		autoHostRewrite string
+		upgradeTypes    string
+		proto           string
+		useRegex        string
+		allowedMethods  string
+		securityProfile string
+		exp             RouteResolveOpts
+		wantErr         bool
 	}{
 		{
 			desc:           "OK",
@@ -482,12 +624,38 @@ func TestRouteOptsResolver(t *testing.T) {
 			exp:      RouteResolveOpts{},
 			wantErr:  true,
 		},
+		{
+			desc:        "host rewrite",
+			hostRewrite: "bucket.website.yandexcloud.net",
+			exp:         RouteResolveOpts{HostRewrite: "bucket.website.yandexcloud.net"},
+		},
+		{
+			desc:            "auto host rewrite",
+			autoHostRewrite: "true",
+			exp:             RouteResolveOpts{AutoHostRewrite: true},
+		},
+		{
+			desc:            "security profile",
+			securityProfile: "sws-profile-id",
+			exp:             RouteResolveOpts{SecurityProfileID: "sws-profile-id"},
+		},
+		{
+			desc:            "bad autoHostRewrite format",
+			autoHostRewrite: "yes",
+			wantErr:         true,
+		},
+		{
+			desc:            "host rewrite and auto host rewrite",
+			hostRewrite:     "example.com",
+			autoHostRewrite: "true",
+			wantErr:         true,
+		},
 	}
 	resolvers := NewResolvers(nil)
 	for _, tc := range testData {
 		r := resolvers.RouteOpts()
 		t.Run(tc.desc, func(t *testing.T) {
-			ret, err := r.Resolve(tc.timeout, tc.idleTimeout, tc.prefixRewrite, tc.upgradeTypes, tc.proto, tc.useRegex, tc.allowedMethods)
+			ret, err := r.Resolve(tc.timeout, tc.idleTimeout, tc.prefixRewrite, tc.hostRewrite, tc.autoHostRewrite, tc.upgradeTypes, tc.proto, tc.useRegex, tc.allowedMethods, tc.securityProfile)
 			require.True(t, (err != nil) == tc.wantErr, "Result() error = %v)", err)
 			if !tc.wantErr {
 				assert.Equal(t, tc.exp, ret)
@@ -66,5 +66,10 @@ func BuildHTTPHandler(opts HandlerOptions) *apploadbalancer.HttpHandler {
 	if opts.AllowHTTP10 {
 		handler.ProtocolSettings = &apploadbalancer.HttpHandler_AllowHttp10{AllowHttp10: true}
 	}
+	if opts.HTTP2 {
+		handler.ProtocolSettings = &apploadbalancer.HttpHandler_Http2Options{
+			Http2Options: &apploadbalancer.Http2Options{MaxConcurrentStreams: opts.HTTP2MaxConcurrentStreams},
+		}
+	}
 	return handler
 }
@@ -90,6 +90,32 @@ func TestSNIMatches(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc: "OK with http2",
+			tlsItems: []*v1.IngressTLS{
+				{
+					Hosts:      []string{"example1.com"},
+					SecretName: "XXX1",
+				},
+			},
+			handlerOpts: HandlerOptions{HTTP2: true, HTTP2MaxConcurrentStreams: 100},
+			exp: []*apploadbalancer.SniMatch{
+				{
+					Name:        "sni-1954a6fc86a55a010c3c8e48f0603e956a6054ec",
+					ServerNames: []string{"example1.com"},
+					Handler: &apploadbalancer.TlsHandler{
+						Handler: &apploadbalancer.TlsHandler_HttpHandler{
+							HttpHandler: &apploadbalancer.HttpHandler{
+								ProtocolSettings: &apploadbalancer.HttpHandler_Http2Options{
+									Http2Options: &apploadbalancer.Http2Options{MaxConcurrentStreams: 100},
+								},
+							},
+						},
+						CertificateIds: []string{"XXX1"},
+					},
+				},
+			},
+		},
 		{
 			desc: "duplicated host+cert pair",
 			tlsItems: []*v1.IngressTLS{
@@ -0,0 +1,243 @@
+package builders
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/golang/protobuf/ptypes/wrappers"
+	"github.com/yandex-cloud/go-genproto/yandex/cloud/apploadbalancer/v1"
+	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/yandex-cloud/yc-alb-ingress-controller/api/v1alpha1"
+	ycerrors "github.com/yandex-cloud/yc-alb-ingress-controller/pkg/errors"
+	"github.com/yandex-cloud/yc-alb-ingress-controller/pkg/k8s"
+	"github.com/yandex-cloud/yc-alb-ingress-controller/pkg/metadata"
+)
+
+type StreamBackendGroupRepository interface {
+	FindTargetGroup(context.Context, string) (*apploadbalancer.TargetGroup, error)
+}
+
+type StreamBackendGroupForCrdBuilder struct {
+	FolderID string
+	Names    *metadata.Names
+	Cli      client.Client
+	Repo     StreamBackendGroupRepository
+}
+
+func (b *StreamBackendGroupForCrdBuilder) BuildForCrd(
+	ctx context.Context, bgCR *v1alpha1.StreamBackendGroup,
+) (*apploadbalancer.BackendGroup, error) {
+	var backends []*apploadbalancer.StreamBackend
+
+	seenSvc := make(map[exposedBackendPort]struct{})
+
+	for _, bcrd := range bgCR.Spec.Backends {
+		if bcrd.Service != nil {
+			bgs, err := b.buildStreamBackendsForService(ctx, bgCR.Namespace, seenSvc, bcrd)
+			if err != nil {
+				return nil, fmt.Errorf("failed to build stream backends for service %s/%s: %w", bgCR.Namespace, bcrd.Service.Name, err)
+			}
+			backends = append(backends, bgs...)
+			continue
+		}
+	}
+
+	backend := apploadbalancer.BackendGroup_Stream{
+		Stream: &apploadbalancer.StreamBackendGroup{
+			Backends:        backends,
+			SessionAffinity: parseStreamBGSessionAffinity(bgCR.Spec.SessionAffinity),
+		},
+	}
+
+	return &apploadbalancer.BackendGroup{
+		Name:        b.Names.BackendGroupForCR(bgCR.Namespace, bgCR.Name),
+		Description: fmt.Sprintf("backend group for CR %s/%s", bgCR.Namespace, bgCR.Name),
+		FolderId:    b.FolderID,
+		Backend:     &backend,
+	}, nil
+}
+
+func (b *StreamBackendGroupForCrdBuilder) buildStreamBackendsForService(
+	ctx context.Context, ns string, seenSvc map[exposedBackendPort]struct{}, bgCrd *v1alpha1.StreamBackend,
+) ([]*apploadbalancer.StreamBackend, error) {
+	var svc core.Service
+	err := b.Cli.Get(ctx, types.NamespacedName{
+		Namespace: ns,
+		Name:      bgCrd.Service.Name,
+	}, &svc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get service %s/%s: %w", ns, bgCrd.Service.Name, err)
+	}
+	podTargets, err := k8s.PodIPTargets(&svc)
+	if err != nil {
+		return nil, err
+	}
+	if !podTargets && svc.Spec.Type != core.ServiceTypeNodePort {
+		return nil, fmt.Errorf("type of service %s/%s used by CR StreamBackend %s is not NodePort",
+			svc.Namespace, svc.Name, bgCrd.Service.Name)
+	}
+
+	tgName := b.Names.TargetGroup(k8s.NamespacedNameOf(&svc))
+	tg, err := b.Repo.FindTargetGroup(ctx, tgName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find target group %s: %w", tgName, err)
+	}
+	if tg == nil {
+		return nil, ycerrors.YCResourceNotReadyError{ResourceType: "target group", Name: tgName}
+	}
+
+	ingressBackendPort := bgCrd.Service.Port
+	svcBackendPorts := nodePortsForServicePort(ingressBackendPort.Name, ingressBackendPort.Number, svc.Spec.Ports)
+	if len(svcBackendPorts) == 0 {
+		return nil, fmt.Errorf("service %s/%s doesn't expose its port %v",
+			svc.Namespace, svc.Name, ingressBackendPort)
+	}
+	ports, err := backendPorts(ctx, b.Cli, &svc, svcBackendPorts)
+	if err != nil {
+		return nil, err
+	}
+
+	balancingConfig, err := parseBalancingConfigFromCRDConfig(bgCrd.LoadBalancingConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse load balancing config: %w", err)
+	}
+
+	healthChecks, err := b.buildStreamHealthChecks(bgCrd, ports)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build health checks: %w", err)
+	}
+
+	var ret []*apploadbalancer.StreamBackend
+	for i, port := range svcBackendPorts {
+		backendPort := ports[i]
+		if _, ok := seenSvc[exposedBackendPort{svc: k8s.NamespacedNameOf(&svc), port: backendPort}]; ok {
+			// backend for this service and port has already been added to this backend group
+			continue
+		}
+
+		backend := &apploadbalancer.StreamBackend{
+			Name:          b.Names.Backend("", svc.Namespace, svc.Name, port.Port, int32(backendPort)),
+			BackendWeight: &wrappers.Int64Value{Value: bgCrd.Weight},
+			Port:          backendPort,
+			BackendType: &apploadbalancer.StreamBackend_TargetGroups{
+				TargetGroups: &apploadbalancer.TargetGroupsBackend{
+					TargetGroupIds: []string{
+						tg.Id,
+					},
+				},
+			},
+			Healthchecks:        healthChecks,
+			LoadBalancingConfig: balancingConfig,
+			EnableProxyProtocol: bgCrd.EnableProxyProtocol,
+		}
+		if bgCrd.TLS != nil {
+			backend.Tls = &apploadbalancer.BackendTls{
+				Sni: bgCrd.TLS.Sni,
+			}
+			if len(bgCrd.TLS.TrustedCa) > 0 {
+				backend.Tls.ValidationContext = &apploadbalancer.ValidationContext{
+					TrustedCa: &apploadbalancer.ValidationContext_TrustedCaBytes{
+						TrustedCaBytes: bgCrd.TLS.TrustedCa,
+					},
+				}
+			}
+		}
+
+		ret = append(ret, backend)
+		seenSvc[exposedBackendPort{svc: k8s.NamespacedNameOf(&svc), port: backendPort}] = struct{}{}
+	}
+	return ret, nil
+}
+
+func (b *StreamBackendGroupForCrdBuilder) buildStreamHealthChecks(backend *v1alpha1.StreamBackend, ports []int64) ([]*apploadbalancer.HealthCheck, error) {
+	if len(backend.HealthChecks) == 0 {
+		return defaultHealthChecks, nil
+	}
+
+	res := make([]*apploadbalancer.HealthCheck, 0, len(backend.HealthChecks))
+	for _, check := range backend.HealthChecks {
+		var transportSettings apploadbalancer.HealthCheck_TransportSettings
+		if backend.TLS != nil {
+			transportSettings = &apploadbalancer.HealthCheck_Tls{
+				Tls: &apploadbalancer.SecureTransportSettings{
+					Sni: backend.TLS.Sni,
+					ValidationContext: &apploadbalancer.ValidationContext{
+						TrustedCa: &apploadbalancer.ValidationContext_TrustedCaBytes{
+							TrustedCaBytes: backend.TLS.TrustedCa,
+						},
+					},
+				},
+			}
+		} else {
+			transportSettings = &apploadbalancer.HealthCheck_Plaintext{
+				Plaintext: &apploadbalancer.PlaintextTransportSettings{},
+			}
+		}
+
+		var hc apploadbalancer.HealthCheck_Healthcheck
+		switch {
+		case check.Stream != nil:
+			hc = &apploadbalancer.HealthCheck_Stream{
+				Stream: &apploadbalancer.HealthCheck_StreamHealthCheck{
+					Send:    textPayload(check.Stream.Send),
+					Receive: textPayload(check.Stream.Receive),
+				},
+			}
+		case check.HTTP != nil:
+			hc = &apploadbalancer.HealthCheck_Http{
+				Http: &apploadbalancer.HealthCheck_HttpHealthCheck{
+					Path: check.HTTP.Path,
+				},
+			}
+		case check.GRPC != nil:
+			hc = &apploadbalancer.HealthCheck_Grpc{
+				Grpc: &apploadbalancer.HealthCheck_GrpcHealthCheck{
+					ServiceName: check.GRPC.ServiceName,
+				},
+			}
+		default:
+			return nil, fmt.Errorf("one of stream, http or grpc health check must be specified")
+		}
+
+		checkPorts := ports
+		if check.Port != nil {
+			checkPorts = []int64{*check.Port}
+		}
+
+		for _, port := range checkPorts {
+			res = append(res, &apploadbalancer.HealthCheck{
+				Timeout:            convertDuration(check.Timeout),
+				Interval:           convertDuration(check.Interval),
+				HealthcheckPort:    port,
+				Healthcheck:        hc,
+				HealthyThreshold:   check.HealthyThreshold,
+				UnhealthyThreshold: check.UnhealthyThreshold,
+				TransportSettings:  transportSettings,
+			})
+		}
+	}
+
+	return res, nil
+}
+
+func textPayload(s string) *apploadbalancer.Payload {
+	if s == "" {
+		return nil
+	}
+	return &apploadbalancer.Payload{Payload: &apploadbalancer.Payload_Text{Text: s}}
+}
+
+func parseStreamBGSessionAffinity(sa *v1alpha1.StreamSessionAffinity) apploadbalancer.StreamBackendGroup_SessionAffinity {
+	if sa == nil || sa.Connection == nil {
+		return nil
+	}
+
+	return &apploadbalancer.StreamBackendGroup_Connection{
+		Connection: &apploadbalancer.ConnectionSessionAffinity{
+			SourceIp: sa.Connection.SourceIP,
+		},
+	}
+}
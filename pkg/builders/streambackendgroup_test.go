@@ -0,0 +1,279 @@
+package builders
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yandex-cloud/go-genproto/yandex/cloud/apploadbalancer/v1"
+	"github.com/yandex-cloud/yc-alb-ingress-controller/api/v1alpha1"
+	"github.com/yandex-cloud/yc-alb-ingress-controller/pkg/builders/mocks"
+	"github.com/yandex-cloud/yc-alb-ingress-controller/pkg/metadata"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+	v12 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestStreamBackendGroup_BuildForCrd(t *testing.T) {
+	targetGroupsBackend := &apploadbalancer.TargetGroupsBackend{
+		TargetGroupIds: []string{
+			"target-group-id",
+		},
+	}
+
+	svc1 := &v12.Service{
+		TypeMeta:   metav1.TypeMeta{Kind: "Service", APIVersion: "v1"},
+		ObjectMeta: metav1.ObjectMeta{Namespace: "test-ns", Name: "service1"},
+		Spec: v12.ServiceSpec{
+			Type: v12.ServiceTypeNodePort,
+			Ports: []v12.ServicePort{
+				{
+					Name:       "postgres",
+					Port:       5432,
+					TargetPort: intstr.IntOrString{IntVal: 5432},
+					NodePort:   30432,
+				},
+			},
+		},
+	}
+	svc2 := &v12.Service{
+		TypeMeta:   metav1.TypeMeta{Kind: "Service", APIVersion: "v1"},
+		ObjectMeta: metav1.ObjectMeta{Namespace: "test-ns", Name: "service2"},
+		Spec: v12.ServiceSpec{
+			Type: v12.ServiceTypeClusterIP,
+			Ports: []v12.ServicePort{
+				{
+					Name: "mqtt",
+					Port: 1883,
+				},
+			},
+		},
+	}
+
+	duration := 2 * time.Second
+
+	testData := []struct {
+		desc         string
+		backendGroup *v1alpha1.StreamBackendGroup
+		exp          *apploadbalancer.BackendGroup
+		wantErr      bool
+	}{
+		{
+			desc: "OK",
+			backendGroup: &v1alpha1.StreamBackendGroup{
+				TypeMeta:   metav1.TypeMeta{Kind: "StreamBackendGroup", APIVersion: "alb.yc.io/v1alpha1"},
+				ObjectMeta: metav1.ObjectMeta{Namespace: "test-ns", Name: "test-bg"},
+				Spec: v1alpha1.StreamBackendGroupSpec{
+					SessionAffinity: &v1alpha1.StreamSessionAffinity{
+						Connection: &v1alpha1.SessionAffinityConnection{SourceIP: true},
+					},
+					Backends: []*v1alpha1.StreamBackend{
+						{
+							Name:   "postgres",
+							Weight: 70,
+							Service: &v1alpha1.ServiceBackend{
+								Name: "service1",
+								Port: v1alpha1.ServiceBackendPort{
+									Number: 5432,
+								},
+							},
+							EnableProxyProtocol: true,
+						},
+					},
+				},
+			},
+			exp: &apploadbalancer.BackendGroup{
+				Name:        "bg-cr-60cf398752e89f2e5e7a130f0fdf7e203fe17410",
+				Description: "backend group for CR test-ns/test-bg",
+				FolderId:    "my-folder",
+				Backend: &apploadbalancer.BackendGroup_Stream{
+					Stream: &apploadbalancer.StreamBackendGroup{
+						Backends: []*apploadbalancer.StreamBackend{
+							{
+								Name: "backend-2e6ab7c1338beb2b7cfd07166e44b68e20773af1-5432-30432",
+								BackendWeight: &wrapperspb.Int64Value{
+									Value: 70,
+								},
+								Port: 30432,
+								BackendType: &apploadbalancer.StreamBackend_TargetGroups{
+									TargetGroups: targetGroupsBackend,
+								},
+								Healthchecks:        defaultHealthChecks,
+								EnableProxyProtocol: true,
+							},
+						},
+						SessionAffinity: &apploadbalancer.StreamBackendGroup_Connection{
+							Connection: &apploadbalancer.ConnectionSessionAffinity{SourceIp: true},
+						},
+					},
+				},
+			},
+		},
+		{
+			desc: "stream health check",
+			backendGroup: &v1alpha1.StreamBackendGroup{
+				TypeMeta:   metav1.TypeMeta{Kind: "StreamBackendGroup", APIVersion: "alb.yc.io/v1alpha1"},
+				ObjectMeta: metav1.ObjectMeta{Namespace: "test-ns", Name: "test-bg"},
+				Spec: v1alpha1.StreamBackendGroupSpec{
+					Backends: []*v1alpha1.StreamBackend{
+						{
+							Name:   "postgres",
+							Weight: 1,
+							Service: &v1alpha1.ServiceBackend{
+								Name: "service1",
+								Port: v1alpha1.ServiceBackendPort{
+									Name: "postgres",
+								},
+							},
+							TLS: &v1alpha1.BackendTLS{
+								Sni: "db.example.com",
+							},
+							HealthChecks: []*v1alpha1.StreamHealthCheck{
+								{
+									Stream: &v1alpha1.TCPHealthCheck{
+										Send:    "PING",
+										Receive: "PONG",
+									},
+									Port:               ptr.To[int64](30433),
+									HealthyThreshold:   2,
+									UnhealthyThreshold: 3,
+									Timeout:            &metav1.Duration{Duration: duration},
+									Interval:           &metav1.Duration{Duration: duration},
+								},
+							},
+						},
+					},
+				},
+			},
+			exp: &apploadbalancer.BackendGroup{
+				Name:        "bg-cr-60cf398752e89f2e5e7a130f0fdf7e203fe17410",
+				Description: "backend group for CR test-ns/test-bg",
+				FolderId:    "my-folder",
+				Backend: &apploadbalancer.BackendGroup_Stream{
+					Stream: &apploadbalancer.StreamBackendGroup{
+						Backends: []*apploadbalancer.StreamBackend{
+							{
+								Name: "backend-2e6ab7c1338beb2b7cfd07166e44b68e20773af1-5432-30432",
+								BackendWeight: &wrapperspb.Int64Value{
+									Value: 1,
+								},
+								Port: 30432,
+								BackendType: &apploadbalancer.StreamBackend_TargetGroups{
+									TargetGroups: targetGroupsBackend,
+								},
+								Tls: &apploadbalancer.BackendTls{
+									Sni: "db.example.com",
+								},
+								Healthchecks: []*apploadbalancer.HealthCheck{
+									{
+										Timeout:            &durationpb.Duration{Seconds: 2},
+										Interval:           &durationpb.Duration{Seconds: 2},
+										HealthcheckPort:    30433,
+										HealthyThreshold:   2,
+										UnhealthyThreshold: 3,
+										Healthcheck: &apploadbalancer.HealthCheck_Stream{
+											Stream: &apploadbalancer.HealthCheck_StreamHealthCheck{
+												Send:    &apploadbalancer.Payload{Payload: &apploadbalancer.Payload_Text{Text: "PING"}},
+												Receive: &apploadbalancer.Payload{Payload: &apploadbalancer.Payload_Text{Text: "PONG"}},
+											},
+										},
+										TransportSettings: &apploadbalancer.HealthCheck_Tls{
+											Tls: &apploadbalancer.SecureTransportSettings{
+												Sni: "db.example.com",
+												ValidationContext: &apploadbalancer.ValidationContext{
+													TrustedCa: &apploadbalancer.ValidationContext_TrustedCaBytes{},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			desc: "health check type not specified",
+			backendGroup: &v1alpha1.StreamBackendGroup{
+				TypeMeta:   metav1.TypeMeta{Kind: "StreamBackendGroup", APIVersion: "alb.yc.io/v1alpha1"},
+				ObjectMeta: metav1.ObjectMeta{Namespace: "test-ns", Name: "test-bg"},
+				Spec: v1alpha1.StreamBackendGroupSpec{
+					Backends: []*v1alpha1.StreamBackend{
+						{
+							Name:   "postgres",
+							Weight: 1,
+							Service: &v1alpha1.ServiceBackend{
+								Name: "service1",
+								Port: v1alpha1.ServiceBackendPort{
+									Number: 5432,
+								},
+							},
+							HealthChecks: []*v1alpha1.StreamHealthCheck{
+								{
+									HealthyThreshold: 2,
+								},
+							},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			desc: "service is not NodePort",
+			backendGroup: &v1alpha1.StreamBackendGroup{
+				TypeMeta:   metav1.TypeMeta{Kind: "StreamBackendGroup", APIVersion: "alb.yc.io/v1alpha1"},
+				ObjectMeta: metav1.ObjectMeta{Namespace: "test-ns", Name: "test-bg"},
+				Spec: v1alpha1.StreamBackendGroupSpec{
+					Backends: []*v1alpha1.StreamBackend{
+						{
+							Name:   "mqtt",
+							Weight: 1,
+							Service: &v1alpha1.ServiceBackend{
+								Name: "service2",
+								Port: v1alpha1.ServiceBackendPort{
+									Number: 1883,
+								},
+							},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+	}
+	cli := fake.NewClientBuilder().WithObjects(svc1, svc2).Build()
+	for _, tc := range testData {
+		t.Run(tc.desc, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			tgRepo := mocks.NewMockTargetGroupFinder(ctrl)
+			tgRepo.EXPECT().FindTargetGroup(gomock.Any(), gomock.Any()).AnyTimes().Return(&apploadbalancer.TargetGroup{
+				Id: "target-group-id",
+			}, nil)
+
+			b := StreamBackendGroupForCrdBuilder{
+				FolderID: "my-folder",
+				Names:    &metadata.Names{ClusterID: "my-cluster"},
+				Cli:      cli,
+				Repo:     tgRepo,
+			}
+
+			res, err := b.BuildForCrd(context.Background(), tc.backendGroup)
+			require.True(t, (err != nil) == tc.wantErr)
+			if tc.wantErr {
+				return
+			}
+			assert.Condition(t, func() bool { return proto.Equal(tc.exp, res) }, "backend groups mismatch\nexp %v\ngot %v", tc.exp, res)
+		})
+	}
+}
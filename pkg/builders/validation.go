@@ -0,0 +1,103 @@
+package builders
+
+import (
+	"fmt"
+
+	"github.com/yandex-cloud/yc-alb-ingress-controller/api/v1alpha1"
+	"github.com/yandex-cloud/yc-alb-ingress-controller/pkg/algo"
+	"github.com/yandex-cloud/yc-alb-ingress-controller/pkg/k8s"
+)
+
+// ValidateBackendOpts checks options of backends set by annotations with the same resolver as backend groups of
+// services use, conflicts of annotations of different ingresses and services are not checked
+func ValidateBackendOpts(annotations map[string]string) error {
+	r := BackendOptsResolver{}
+	opts, err := r.Resolve(
+		annotations[k8s.Protocol],
+		annotations[k8s.BalancingMode],
+		annotations[k8s.BalancingPanicThreshold],
+		annotations[k8s.BalancingLocalityAwareRouting],
+		annotations[k8s.TransportSecurity],
+		annotations[k8s.SessionAffinityHeader],
+		annotations[k8s.SessionAffinityCookie],
+		annotations[k8s.SessionAffinityConnection],
+		annotations[k8s.HealthChecks],
+	)
+	if err != nil {
+		return err
+	}
+	_, err = parseBalancingConfigFromStruct(opts.LoadBalancingConfig)
+	return err
+}
+
+// ValidateHttpBackendGroup checks the part of spec which doesn't depend on services and cloud resources
+func ValidateHttpBackendGroup(bg *v1alpha1.HttpBackendGroup) error { //nolint:revive
+	if err := validateSessionAffinity(bg.Spec.SessionAffinity); err != nil {
+		return err
+	}
+
+	names := make(map[string]struct{})
+	for _, b := range bg.Spec.Backends {
+		if b == nil {
+			continue
+		}
+		if _, ok := names[b.Name]; ok {
+			return fmt.Errorf("duplicate backend name %s", b.Name)
+		}
+		names[b.Name] = struct{}{}
+
+		if (b.Service == nil) == (b.StorageBucket == nil) {
+			return fmt.Errorf("exactly one of service and storage bucket must be specified for backend %s", b.Name)
+		}
+		for _, check := range b.HealthChecks {
+			if check != nil && check.HTTP == nil {
+				return fmt.Errorf("http health check must be specified for backend %s", b.Name)
+			}
+		}
+		if _, err := parseBalancingConfigFromCRDConfig(b.LoadBalancingConfig); err != nil {
+			return fmt.Errorf("failed to parse load balancing config of backend %s: %w", b.Name, err)
+		}
+	}
+	return nil
+}
+
+// ValidateGrpcBackendGroup checks the part of spec which doesn't depend on services and cloud resources
+func ValidateGrpcBackendGroup(bg *v1alpha1.GrpcBackendGroup) error {
+	if err := validateSessionAffinity(bg.Spec.SessionAffinity); err != nil {
+		return err
+	}
+
+	names := make(map[string]struct{})
+	for _, b := range bg.Spec.Backends {
+		if b == nil {
+			continue
+		}
+		if _, ok := names[b.Name]; ok {
+			return fmt.Errorf("duplicate backend name %s", b.Name)
+		}
+		names[b.Name] = struct{}{}
+
+		if b.Service == nil {
+			return fmt.Errorf("service must be specified for backend %s", b.Name)
+		}
+		for _, check := range b.HealthChecks {
+			if check != nil && check.GRPC == nil {
+				return fmt.Errorf("grpc health check must be specified for backend %s", b.Name)
+			}
+		}
+		if _, err := parseBalancingConfigFromCRDConfig(b.LoadBalancingConfig); err != nil {
+			return fmt.Errorf("failed to parse load balancing config of backend %s: %w", b.Name, err)
+		}
+	}
+	return nil
+}
+
+func validateSessionAffinity(sa *v1alpha1.SessionAffinity) error {
+	if sa == nil {
+		return nil
+	}
+	if algo.Count([]bool{sa.Cookie != nil, sa.Connection != nil, sa.Header != nil}, func(set bool) bool { return set }) > 1 {
+		return fmt.Errorf("no more than one session affinity type must be specified")
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package builders
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/yandex-cloud/yc-alb-ingress-controller/api/v1alpha1"
+)
+
+func TestValidateHttpBackendGroup(t *testing.T) {
+	svc := &v1alpha1.ServiceBackend{Name: "svc", Port: v1alpha1.ServiceBackendPort{Number: 80}}
+	bucket := &v1alpha1.StorageBucketBackend{Name: "bucket"}
+
+	for _, tc := range []struct {
+		desc    string
+		spec    v1alpha1.HttpBackendGroupSpec
+		wantErr bool
+	}{
+		{
+			desc: "OK",
+			spec: v1alpha1.HttpBackendGroupSpec{
+				SessionAffinity: &v1alpha1.SessionAffinity{Header: &v1alpha1.SessionAffinityHeader{HeaderName: "x-user"}},
+				Backends: []*v1alpha1.HttpBackend{
+					{Name: "svc", Service: svc, LoadBalancingConfig: &v1alpha1.LoadBalancingConfig{BalancerMode: "ROUND_ROBIN"}},
+					{Name: "bucket", StorageBucket: bucket},
+				},
+			},
+		},
+		{
+			desc: "several session affinities",
+			spec: v1alpha1.HttpBackendGroupSpec{SessionAffinity: &v1alpha1.SessionAffinity{
+				Header:     &v1alpha1.SessionAffinityHeader{HeaderName: "x-user"},
+				Connection: &v1alpha1.SessionAffinityConnection{SourceIP: true},
+			}},
+			wantErr: true,
+		},
+		{
+			desc:    "duplicate backend names",
+			spec:    v1alpha1.HttpBackendGroupSpec{Backends: []*v1alpha1.HttpBackend{{Name: "b", Service: svc}, {Name: "b", StorageBucket: bucket}}},
+			wantErr: true,
+		},
+		{
+			desc:    "service and bucket",
+			spec:    v1alpha1.HttpBackendGroupSpec{Backends: []*v1alpha1.HttpBackend{{Name: "b", Service: svc, StorageBucket: bucket}}},
+			wantErr: true,
+		},
+		{
+			desc: "no http health check",
+			spec: v1alpha1.HttpBackendGroupSpec{Backends: []*v1alpha1.HttpBackend{
+				{Name: "b", Service: svc, HealthChecks: []*v1alpha1.HealthCheck{{GRPC: &v1alpha1.GrpcHealthCheck{}}}},
+			}},
+			wantErr: true,
+		},
+		{
+			desc: "unknown balancing mode",
+			spec: v1alpha1.HttpBackendGroupSpec{Backends: []*v1alpha1.HttpBackend{
+				{Name: "b", Service: svc, LoadBalancingConfig: &v1alpha1.LoadBalancingConfig{BalancerMode: "FASTEST"}},
+			}},
+			wantErr: true,
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			err := ValidateHttpBackendGroup(&v1alpha1.HttpBackendGroup{Spec: tc.spec})
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestValidateGrpcBackendGroup(t *testing.T) {
+	svc := &v1alpha1.ServiceBackend{Name: "svc", Port: v1alpha1.ServiceBackendPort{Number: 80}}
+
+	assert.NoError(t, ValidateGrpcBackendGroup(&v1alpha1.GrpcBackendGroup{Spec: v1alpha1.GrpcBackendGroupSpec{
+		Backends: []*v1alpha1.GrpcBackend{{Name: "a", Service: svc}, {Name: "b", Service: svc}},
+	}}))
+	assert.Error(t, ValidateGrpcBackendGroup(&v1alpha1.GrpcBackendGroup{Spec: v1alpha1.GrpcBackendGroupSpec{
+		Backends: []*v1alpha1.GrpcBackend{{Name: "a"}},
+	}}))
+	assert.Error(t, ValidateGrpcBackendGroup(&v1alpha1.GrpcBackendGroup{Spec: v1alpha1.GrpcBackendGroupSpec{
+		Backends: []*v1alpha1.GrpcBackend{{Name: "a", Service: svc, HealthChecks: []*v1alpha1.HealthCheck{{HTTP: &v1alpha1.HttpHealthCheck{}}}}},
+	}}))
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/yandex-cloud/yc-alb-ingress-controller/pkg/algo/maps"
 	errors2 "github.com/yandex-cloud/yc-alb-ingress-controller/pkg/errors"
 	"github.com/yandex-cloud/yc-alb-ingress-controller/pkg/metadata"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/durationpb"
 	networking "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -19,6 +20,10 @@ type HTTPRouterData struct {
 	Router *apploadbalancer.HttpRouter
 }
 
+func (d *HTTPRouterData) HasVirtualHosts() bool {
+	return d != nil && d.Router != nil && len(d.Router.VirtualHosts) > 0
+}
+
 type HTTPRouterBuilder struct {
 	vhs map[string]*VirtualHost
 
@@ -35,6 +40,8 @@ type HTTPRouterBuilder struct {
 	routeOpts RouteResolveOpts
 	ingNs     string
 
+	defaultRBAC *apploadbalancer.RBAC
+
 	backendGroupFinder BackendGroupFinder
 }
 
@@ -51,6 +58,7 @@ type VirtualHostResolveOpts struct {
 	ModifyRequest  ModifyHeaderOpts
 
 	SecurityProfileID string
+	RBAC              *apploadbalancer.RBAC
 }
 
 type ModifyHeaderOpts struct {
@@ -61,13 +69,19 @@ type ModifyHeaderOpts struct {
 }
 
 type RouteResolveOpts struct {
-	Timeout        *durationpb.Duration
-	IdleTimeout    *durationpb.Duration
-	PrefixRewrite  string
-	UpgradeTypes   []string
-	BackendType    BackendType
-	UseRegex       bool
-	AllowedMethods []string
+	Timeout         *durationpb.Duration
+	IdleTimeout     *durationpb.Duration
+	PrefixRewrite   string
+	HostRewrite     string
+	AutoHostRewrite bool
+	UpgradeTypes    []string
+	BackendType     BackendType
+	UseRegex        bool
+	AllowedMethods  []string
+	RBAC            *apploadbalancer.RBAC
+
+	// SecurityProfileID overrides security profile of the virtual host for the route
+	SecurityProfileID string
 }
 
 type BackendGroupFinder interface {
@@ -84,6 +98,11 @@ func (b *HTTPRouterBuilder) SetOpts(
 	b.ingNs = ingNs
 }
 
+// SetDefaultRBAC sets RBAC policy for virtual hosts which have no RBAC in their options
+func (b *HTTPRouterBuilder) SetDefaultRBAC(rbac *apploadbalancer.RBAC) {
+	b.defaultRBAC = rbac
+}
+
 func (b *HTTPRouterBuilder) AddRoute(hp HostAndPath, svcName string, svcPort int64) error {
 	bgName := b.names.BackendGroupForSvcPort(types.NamespacedName{
 		Namespace: b.ingNs,
@@ -109,6 +128,32 @@ func (b *HTTPRouterBuilder) AddRoute(hp HostAndPath, svcName string, svcPort int
 	return b.appendRoute(hp, route)
 }
 
+// AddCanaryRoute adds route to the backend group splitting traffic between primary and canary services
+func (b *HTTPRouterBuilder) AddCanaryRoute(hp HostAndPath, canarySvcName string, canarySvcPort int64) error {
+	bgName := b.names.CanaryBackendGroupForSvcPort(types.NamespacedName{
+		Namespace: b.ingNs,
+		Name:      canarySvcName,
+	}, canarySvcPort)
+	bg, err := b.backendGroupFinder.FindBackendGroup(context.TODO(), bgName)
+
+	if bg == nil {
+		return errors2.ResourceNotReadyError{ResourceType: "BackendGroup", Name: bgName}
+	}
+
+	if err != nil {
+		return fmt.Errorf("error finding backend group: %w", err)
+	}
+
+	var route *apploadbalancer.Route
+	if b.routeOpts.BackendType == GRPC {
+		route = grpcRoute(hp, b.routeOpts, bg.Id)
+	} else {
+		route = httpRoute(hp, b.routeOpts, bg.Id)
+	}
+
+	return b.appendRoute(hp, route)
+}
+
 func (b *HTTPRouterBuilder) AddRouteToResource(hp HostAndPath, resourceName string) error {
 	bgName := b.names.BackendGroupForCR(b.ingNs, resourceName)
 	bg, err := b.backendGroupFinder.FindBackendGroup(context.TODO(), bgName)
@@ -135,7 +180,21 @@ func (b *HTTPRouterBuilder) AddHTTPDirectResponse(hp HostAndPath, directResponse
 	action := &apploadbalancer.HttpRoute_DirectResponse{
 		DirectResponse: directResponse,
 	}
-	route := httpRouteForAction(hp, action, b.routeOpts.AllowedMethods)
+	route := httpRouteForAction(hp, action, b.routeOpts)
+
+	return b.appendRoute(hp, route)
+}
+
+func (b *HTTPRouterBuilder) AddGRPCStatusResponse(hp HostAndPath, statusResponse *apploadbalancer.GrpcStatusResponseAction) error {
+	route := &apploadbalancer.Route{
+		Route: &apploadbalancer.Route_Grpc{
+			Grpc: &apploadbalancer.GrpcRoute{
+				Match:  &apploadbalancer.GrpcRouteMatch{Fqmn: matchForPath(hp)},
+				Action: &apploadbalancer.GrpcRoute_StatusResponse{StatusResponse: statusResponse},
+			},
+		},
+		RouteOptions: buildRouteOpts(ModifyHeaderOpts{}, ModifyHeaderOpts{}, b.routeOpts.SecurityProfileID, b.routeOpts.RBAC),
+	}
 
 	return b.appendRoute(hp, route)
 }
@@ -149,7 +208,7 @@ func (b *HTTPRouterBuilder) AddRedirectToHTTPS(hp HostAndPath) error {
 			ResponseCode:  apploadbalancer.RedirectAction_MOVED_PERMANENTLY,
 		},
 	}
-	route := httpRouteForAction(hp, action, b.routeOpts.AllowedMethods)
+	route := httpRouteForAction(hp, action, b.routeOpts)
 
 	return b.appendRoute(hp, route)
 }
@@ -158,7 +217,7 @@ func (b *HTTPRouterBuilder) AddRedirect(hp HostAndPath, redirect *apploadbalance
 	action := &apploadbalancer.HttpRoute_Redirect{
 		Redirect: redirect,
 	}
-	route := httpRouteForAction(hp, action, b.routeOpts.AllowedMethods)
+	route := httpRouteForAction(hp, action, b.routeOpts)
 
 	return b.appendRoute(hp, route)
 }
@@ -175,11 +234,12 @@ func (b *HTTPRouterBuilder) Build() *HTTPRouterData {
 	}
 
 	for i, vh := range hostOrder {
+		inheritSecurityProfile(vh.routes, vh.opts.SecurityProfileID)
 		httpVirtualHosts[i] = &apploadbalancer.VirtualHost{
 			Name:         b.names.VirtualHostForID(b.tag, b.nextVHID.Next()),
 			Authority:    []string{vh.host},
 			Routes:       vh.routes,
-			RouteOptions: buildRouteOpts(vh.opts.ModifyResponse, vh.opts.ModifyRequest, vh.opts.SecurityProfileID),
+			RouteOptions: buildRouteOpts(vh.opts.ModifyResponse, vh.opts.ModifyRequest, vh.opts.SecurityProfileID, cmp.Or(vh.opts.RBAC, b.defaultRBAC)),
 		}
 	}
 
@@ -200,38 +260,64 @@ func (b *HTTPRouterBuilder) Build() *HTTPRouterData {
 	}
 }
 
+// inheritSecurityProfile sets security profile of virtual host to its routes which have options of their own, e.g. RBAC,
+// but no security profile, so such options don't turn off the profile of virtual host
+func inheritSecurityProfile(routes []*apploadbalancer.Route, securityProfileID string) {
+	if securityProfileID == "" {
+		return
+	}
+	for _, route := range routes {
+		if route.RouteOptions != nil && route.RouteOptions.SecurityProfileId == "" {
+			route.RouteOptions.SecurityProfileId = securityProfileID
+		}
+	}
+}
+
 func httpRoute(hp HostAndPath, opts RouteResolveOpts, bgID string) *apploadbalancer.Route {
-	var action apploadbalancer.HttpRoute_Action = &apploadbalancer.HttpRoute_Route{
-		Route: &apploadbalancer.HttpRouteAction{
-			Timeout:        opts.Timeout,
-			IdleTimeout:    opts.IdleTimeout,
-			PrefixRewrite:  opts.PrefixRewrite,
-			UpgradeTypes:   opts.UpgradeTypes,
-			BackendGroupId: bgID,
-		},
+	routeAction := &apploadbalancer.HttpRouteAction{
+		Timeout:        opts.Timeout,
+		IdleTimeout:    opts.IdleTimeout,
+		PrefixRewrite:  opts.PrefixRewrite,
+		UpgradeTypes:   opts.UpgradeTypes,
+		BackendGroupId: bgID,
 	}
-	return httpRouteForAction(hp, action, opts.AllowedMethods)
+	switch {
+	case opts.HostRewrite != "":
+		routeAction.HostRewriteSpecifier = &apploadbalancer.HttpRouteAction_HostRewrite{HostRewrite: opts.HostRewrite}
+	case opts.AutoHostRewrite:
+		routeAction.HostRewriteSpecifier = &apploadbalancer.HttpRouteAction_AutoHostRewrite{AutoHostRewrite: true}
+	}
+
+	return httpRouteForAction(hp, &apploadbalancer.HttpRoute_Route{Route: routeAction}, opts)
 }
 
-func httpRouteForAction(hp HostAndPath, action apploadbalancer.HttpRoute_Action, methods []string) *apploadbalancer.Route {
+// httpRouteForAction builds route with the action, route level options apply to routes of any action
+func httpRouteForAction(hp HostAndPath, action apploadbalancer.HttpRoute_Action, opts RouteResolveOpts) *apploadbalancer.Route {
 	return &apploadbalancer.Route{
 		Route: &apploadbalancer.Route_Http{
 			Http: &apploadbalancer.HttpRoute{
-				Match:  &apploadbalancer.HttpRouteMatch{Path: matchForPath(hp), HttpMethod: methods},
+				Match:  &apploadbalancer.HttpRouteMatch{Path: matchForPath(hp), HttpMethod: opts.AllowedMethods},
 				Action: action,
 			},
 		},
+		RouteOptions: buildRouteOpts(ModifyHeaderOpts{}, ModifyHeaderOpts{}, opts.SecurityProfileID, opts.RBAC),
 	}
 }
 
 func grpcRoute(hp HostAndPath, opts RouteResolveOpts, bgID string) *apploadbalancer.Route {
-	action := &apploadbalancer.GrpcRoute_Route{
-		Route: &apploadbalancer.GrpcRouteAction{
-			MaxTimeout:     opts.Timeout,
-			IdleTimeout:    opts.IdleTimeout,
-			BackendGroupId: bgID,
-		},
+	routeAction := &apploadbalancer.GrpcRouteAction{
+		MaxTimeout:     opts.Timeout,
+		IdleTimeout:    opts.IdleTimeout,
+		BackendGroupId: bgID,
+	}
+	switch {
+	case opts.HostRewrite != "":
+		routeAction.HostRewriteSpecifier = &apploadbalancer.GrpcRouteAction_HostRewrite{HostRewrite: opts.HostRewrite}
+	case opts.AutoHostRewrite:
+		routeAction.HostRewriteSpecifier = &apploadbalancer.GrpcRouteAction_AutoHostRewrite{AutoHostRewrite: true}
 	}
+
+	action := &apploadbalancer.GrpcRoute_Route{Route: routeAction}
 	return &apploadbalancer.Route{
 		Route: &apploadbalancer.Route_Grpc{
 			Grpc: &apploadbalancer.GrpcRoute{
@@ -239,6 +325,7 @@ func grpcRoute(hp HostAndPath, opts RouteResolveOpts, bgID string) *apploadbalan
 				Action: action,
 			},
 		},
+		RouteOptions: buildRouteOpts(ModifyHeaderOpts{}, ModifyHeaderOpts{}, opts.SecurityProfileID, opts.RBAC),
 	}
 }
 
@@ -296,6 +383,11 @@ func mergeVHOpts(opts1, opts2 VirtualHostResolveOpts) (VirtualHostResolveOpts, e
 
 	opts.SecurityProfileID = cmp.Or(sID1, sID2)
 
+	if opts1.RBAC != nil && opts2.RBAC != nil && !proto.Equal(opts1.RBAC, opts2.RBAC) {
+		return opts, fmt.Errorf("conflict with vh rbac: %v and %v", opts1.RBAC, opts2.RBAC)
+	}
+	opts.RBAC = cmp.Or(opts1.RBAC, opts2.RBAC)
+
 	mergeModifyHeader := func(opts1, opts2 ModifyHeaderOpts) (ModifyHeaderOpts, error) {
 		opts := ModifyHeaderOpts{}
 
@@ -385,10 +477,10 @@ func buildModifyHeaderOpts(modifyHeader ModifyHeaderOpts) []*apploadbalancer.Hea
 	return modifyResponseHeaders
 }
 
-func buildRouteOpts(modifyResponseOpts, modifyRequestOpts ModifyHeaderOpts, securityProfileID string) *apploadbalancer.RouteOptions {
+func buildRouteOpts(modifyResponseOpts, modifyRequestOpts ModifyHeaderOpts, securityProfileID string, rbac *apploadbalancer.RBAC) *apploadbalancer.RouteOptions {
 	modifyResponseHeaders := buildModifyHeaderOpts(modifyResponseOpts)
 	modifyRequestHeaders := buildModifyHeaderOpts(modifyRequestOpts)
-	if len(modifyResponseHeaders) == 0 && len(modifyRequestHeaders) == 0 && securityProfileID == "" {
+	if len(modifyResponseHeaders) == 0 && len(modifyRequestHeaders) == 0 && securityProfileID == "" && rbac == nil {
 		return nil
 	}
 
@@ -396,6 +488,7 @@ func buildRouteOpts(modifyResponseOpts, modifyRequestOpts ModifyHeaderOpts, secu
 		ModifyResponseHeaders: modifyResponseHeaders,
 		ModifyRequestHeaders:  modifyRequestHeaders,
 		SecurityProfileId:     securityProfileID,
+		Rbac:                  rbac,
 	}
 }
 
@@ -420,6 +513,7 @@ func matchForPath(hp HostAndPath) *apploadbalancer.StringMatch {
 func (opts VirtualHostResolveOpts) Clone() VirtualHostResolveOpts {
 	return VirtualHostResolveOpts{
 		SecurityProfileID: opts.SecurityProfileID,
+		RBAC:              opts.RBAC,
 		ModifyResponse: ModifyHeaderOpts{
 			Append:  maps.Clone(opts.ModifyResponse.Append),
 			Remove:  maps.Clone(opts.ModifyResponse.Remove),
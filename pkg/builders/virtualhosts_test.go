@@ -820,3 +820,101 @@ func routesEqualExceptName(lhs, rhs *apploadbalancer.Route) bool {
 	cp.Name = rhs.Name
 	return proto.Equal(cp, rhs)
 }
+
+func TestRouteHostRewrite(t *testing.T) {
+	hp := HostAndPath{Host: "example.com", Path: "/", PathType: string(networking.PathTypePrefix)}
+
+	route := httpRoute(hp, RouteResolveOpts{HostRewrite: "bucket.website.yandexcloud.net"}, "bg-id")
+	assert.Equal(t, "bucket.website.yandexcloud.net", route.GetHttp().GetRoute().GetHostRewrite())
+
+	route = httpRoute(hp, RouteResolveOpts{AutoHostRewrite: true}, "bg-id")
+	assert.True(t, route.GetHttp().GetRoute().GetAutoHostRewrite())
+
+	route = httpRoute(hp, RouteResolveOpts{}, "bg-id")
+	assert.Nil(t, route.GetHttp().GetRoute().GetHostRewriteSpecifier())
+
+	route = grpcRoute(hp, RouteResolveOpts{HostRewrite: "api.example.com"}, "bg-id")
+	assert.Equal(t, "api.example.com", route.GetGrpc().GetRoute().GetHostRewrite())
+
+	route = grpcRoute(hp, RouteResolveOpts{AutoHostRewrite: true}, "bg-id")
+	assert.True(t, route.GetGrpc().GetRoute().GetAutoHostRewrite())
+}
+
+func TestAddGRPCStatusResponse(t *testing.T) {
+	f := NewFactory("my-folder", "", &metadata.Names{ClusterID: "my-cluster"}, &metadata.Labels{ClusterID: "my-cluster"}, nil, nil)
+	f.RestartVirtualHostIDGenerator()
+	b := f.HTTPRouterBuilder("tag", nil)
+
+	hp := HostAndPath{Host: "example.com", Path: "/api.v1.Deprecated", PathType: string(networking.PathTypePrefix)}
+	statusResponse := &apploadbalancer.GrpcStatusResponseAction{Status: apploadbalancer.GrpcStatusResponseAction_UNIMPLEMENTED}
+	require.NoError(t, b.AddGRPCStatusResponse(hp, statusResponse))
+
+	d := b.Build()
+	require.Len(t, d.Router.VirtualHosts, 1)
+	require.Len(t, d.Router.VirtualHosts[0].Routes, 1)
+	route := d.Router.VirtualHosts[0].Routes[0].GetGrpc()
+	assert.Equal(t, "/api.v1.Deprecated", route.GetMatch().GetFqmn().GetPrefixMatch())
+	assert.True(t, proto.Equal(statusResponse, route.GetStatusResponse()))
+}
+
+func TestRouteSecurityProfile(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	f := NewFactory("my-folder", "", &metadata.Names{ClusterID: "my-cluster"}, &metadata.Labels{ClusterID: "my-cluster"}, nil, nil)
+	f.RestartVirtualHostIDGenerator()
+
+	bgFinder := mocks.NewMockBackendGroupFinder(ctrl)
+	bgFinder.EXPECT().FindBackendGroup(gomock.Any(), gomock.Any()).Return(&apploadbalancer.BackendGroup{Id: "bg-id"}, nil).AnyTimes()
+	b := f.HTTPRouterBuilder("tag", bgFinder)
+
+	vhOpts := VirtualHostResolveOpts{SecurityProfileID: "vh-profile"}
+	rbac := &apploadbalancer.RBAC{Action: apploadbalancer.RBAC_DENY}
+	routes := []struct {
+		path string
+		opts RouteResolveOpts
+	}{
+		{path: "/login", opts: RouteResolveOpts{SecurityProfileID: "login-profile"}},
+		{path: "/search", opts: RouteResolveOpts{SecurityProfileID: "search-profile", BackendType: GRPC}},
+		{path: "/admin", opts: RouteResolveOpts{RBAC: rbac}},
+		{path: "/", opts: RouteResolveOpts{}},
+	}
+	for _, r := range routes {
+		b.SetOpts(vhOpts, r.opts, "default")
+		hp := HostAndPath{Host: "example.com", Path: r.path, PathType: string(networking.PathTypePrefix)}
+		require.NoError(t, b.AddRoute(hp, "svc", 80))
+	}
+
+	d := b.Build()
+	require.Len(t, d.Router.VirtualHosts, 1)
+	vh := d.Router.VirtualHosts[0]
+	assert.Equal(t, "vh-profile", vh.RouteOptions.GetSecurityProfileId())
+	require.Len(t, vh.Routes, 4)
+	assert.Equal(t, "login-profile", vh.Routes[0].RouteOptions.GetSecurityProfileId())
+	assert.Equal(t, "search-profile", vh.Routes[1].RouteOptions.GetSecurityProfileId())
+	assert.Equal(t, "vh-profile", vh.Routes[2].RouteOptions.GetSecurityProfileId())
+	assert.True(t, proto.Equal(rbac, vh.Routes[2].RouteOptions.GetRbac()))
+	assert.Nil(t, vh.Routes[3].RouteOptions)
+}
+
+func TestActionRouteOptions(t *testing.T) {
+	f := NewFactory("my-folder", "", &metadata.Names{ClusterID: "my-cluster"}, &metadata.Labels{ClusterID: "my-cluster"}, nil, nil)
+	f.RestartVirtualHostIDGenerator()
+	b := f.HTTPRouterBuilder("tag", nil)
+
+	rbac := &apploadbalancer.RBAC{Action: apploadbalancer.RBAC_DENY}
+	b.SetOpts(VirtualHostResolveOpts{SecurityProfileID: "vh-profile"}, RouteResolveOpts{RBAC: rbac}, "default")
+	hp := func(path string) HostAndPath {
+		return HostAndPath{Host: "example.com", Path: path, PathType: string(networking.PathTypePrefix)}
+	}
+	require.NoError(t, b.AddRedirect(hp("/old"), &apploadbalancer.RedirectAction{ReplaceHost: "new.example.com"}))
+	require.NoError(t, b.AddHTTPDirectResponse(hp("/teapot"), &apploadbalancer.DirectResponseAction{Status: 418}))
+	require.NoError(t, b.AddGRPCStatusResponse(hp("/api.v1.Deprecated"),
+		&apploadbalancer.GrpcStatusResponseAction{Status: apploadbalancer.GrpcStatusResponseAction_UNIMPLEMENTED}))
+
+	d := b.Build()
+	require.Len(t, d.Router.VirtualHosts, 1)
+	require.Len(t, d.Router.VirtualHosts[0].Routes, 3)
+	for _, route := range d.Router.VirtualHosts[0].Routes {
+		assert.True(t, proto.Equal(rbac, route.RouteOptions.GetRbac()), "route %s", route.Name)
+		assert.Equal(t, "vh-profile", route.RouteOptions.GetSecurityProfileId(), "route %s", route.Name)
+	}
+}
@@ -0,0 +1,230 @@
+package deploy
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/yandex-cloud/go-genproto/yandex/cloud/apploadbalancer/v1"
+	"github.com/yandex-cloud/go-genproto/yandex/cloud/operation"
+
+	ycerrors "github.com/yandex-cloud/yc-alb-ingress-controller/pkg/errors"
+	"github.com/yandex-cloud/yc-alb-ingress-controller/pkg/metadata"
+	"github.com/yandex-cloud/yc-alb-ingress-controller/pkg/yc"
+)
+
+//go:generate mockgen -destination=./mocks/adoption.go -package=mocks . BalancerAdoptionRepo
+
+type BalancerAdoptionRepo interface {
+	FindAllResources(ctx context.Context, tag string) (*yc.BalancerResources, error)
+	GetLoadBalancer(ctx context.Context, id string) (*apploadbalancer.LoadBalancer, error)
+	GetHTTPRouter(ctx context.Context, id string) (*apploadbalancer.HttpRouter, error)
+	ListLoadBalancers(ctx context.Context) ([]*apploadbalancer.LoadBalancer, error)
+	RelabelLoadBalancer(ctx context.Context, balancer *apploadbalancer.LoadBalancer) (*operation.Operation, error)
+	RelabelHTTPRouter(ctx context.Context, router *apploadbalancer.HttpRouter) (*operation.Operation, error)
+}
+
+// BalancerAdopter brings existing load balancers under control of ingress groups. The balancer and its routers are
+// renamed and relabeled as if they were created by the controller, so they are found and reconciled in place
+type BalancerAdopter struct {
+	repo     BalancerAdoptionRepo
+	names    *metadata.Names
+	labels   *metadata.Labels
+	folderID string
+}
+
+func NewBalancerAdopter(repo BalancerAdoptionRepo, names *metadata.Names, labels *metadata.Labels, folderID string) *BalancerAdopter {
+	return &BalancerAdopter{
+		repo:     repo,
+		names:    names,
+		labels:   labels,
+		folderID: folderID,
+	}
+}
+
+// Adopt renames and relabels the balancer with the given id and its routers for the group with the tag. Nothing is
+// done if the group already has the balancer. Balancers of other clusters or groups are refused, as well as the ones
+// which addresses or network would be changed by the desired balancer. The error returned on adoption requeues the
+// group, so it's reconciled once the balancer is renamed
+func (a *BalancerAdopter) Adopt(ctx context.Context, tag, balancerID string, desired *apploadbalancer.LoadBalancer) error {
+	if balancerID == "" {
+		return nil
+	}
+
+	resources, err := a.repo.FindAllResources(ctx, tag)
+	if err != nil {
+		return fmt.Errorf("failed to find resources: %w", err)
+	}
+	if resources.Balancer != nil {
+		if resources.Balancer.Id == balancerID {
+			return nil
+		}
+		return fmt.Errorf("group already has load balancer %s", resources.Balancer.Id)
+	}
+
+	balancer, err := a.repo.GetLoadBalancer(ctx, balancerID)
+	if err != nil {
+		return fmt.Errorf("failed to get load balancer %s: %w", balancerID, err)
+	}
+	if balancer.FolderId != a.folderID {
+		return fmt.Errorf("load balancer %s is in folder %s, not in folder %s of the controller", balancerID, balancer.FolderId, a.folderID)
+	}
+	if err = a.checkOwner(balancer.Labels, balancer.Name, a.names.ALB(tag)); err != nil {
+		return fmt.Errorf("load balancer %s can't be adopted: %w", balancerID, err)
+	}
+	if desired != nil {
+		if err = checkAdoptable(balancer, desired); err != nil {
+			return fmt.Errorf("load balancer %s can't be adopted: %w", balancerID, err)
+		}
+	}
+
+	var ops []*operation.Operation
+	routers, err := a.adoptableRouters(ctx, tag, balancer, resources)
+	if err != nil {
+		return err
+	}
+	for _, router := range routers {
+		op, err := a.repo.RelabelHTTPRouter(ctx, router)
+		if err != nil {
+			return fmt.Errorf("failed to relabel http router %s: %w", router.Id, err)
+		}
+		ops = append(ops, op)
+	}
+
+	balancer.Name = a.names.ALB(tag)
+	balancer.Labels = a.adoptedLabels(balancer.Labels)
+	op, err := a.repo.RelabelLoadBalancer(ctx, balancer)
+	if err != nil {
+		return fmt.Errorf("failed to relabel load balancer %s: %w", balancerID, err)
+	}
+	ops = append(ops, op)
+	return ycerrors.OperationIncompleteError{ID: ops[len(ops)-1].Id}
+}
+
+// adoptableRouters returns routers of the http listener and of the default handler of the tls one renamed for the
+// group. Routers the group already has, the ones used by other balancers and the one used by both listeners as the
+// tls router aren't adopted, the controller creates new ones instead
+func (a *BalancerAdopter) adoptableRouters(ctx context.Context, tag string, balancer *apploadbalancer.LoadBalancer, resources *yc.BalancerResources) ([]*apploadbalancer.HttpRouter, error) {
+	var httpRouterID, tlsRouterID string
+	for _, l := range balancer.Listeners {
+		if id := l.GetHttp().GetHandler().GetHttpRouterId(); id != "" && httpRouterID == "" {
+			httpRouterID = id
+		}
+		if id := l.GetTls().GetDefaultHandler().GetHttpHandler().GetHttpRouterId(); id != "" && tlsRouterID == "" {
+			tlsRouterID = id
+		}
+	}
+	if tlsRouterID == httpRouterID {
+		tlsRouterID = ""
+	}
+	if resources.Router != nil {
+		httpRouterID = ""
+	}
+	if resources.TLSRouter != nil {
+		tlsRouterID = ""
+	}
+	if httpRouterID == "" && tlsRouterID == "" {
+		return nil, nil
+	}
+
+	balancers, err := a.repo.ListLoadBalancers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list load balancers: %w", err)
+	}
+	shared := make(map[string]bool)
+	for _, b := range balancers {
+		if b.Id == balancer.Id {
+			continue
+		}
+		for _, l := range b.Listeners {
+			shared[l.GetHttp().GetHandler().GetHttpRouterId()] = true
+			shared[l.GetTls().GetDefaultHandler().GetHttpHandler().GetHttpRouterId()] = true
+			for _, sni := range l.GetTls().GetSniHandlers() {
+				shared[sni.GetHandler().GetHttpHandler().GetHttpRouterId()] = true
+			}
+		}
+	}
+
+	var ret []*apploadbalancer.HttpRouter
+	for _, r := range []struct{ id, name string }{
+		{id: httpRouterID, name: a.names.Router(tag)},
+		{id: tlsRouterID, name: a.names.RouterTLS(tag)},
+	} {
+		if r.id == "" || shared[r.id] {
+			continue
+		}
+		router, err := a.repo.GetHTTPRouter(ctx, r.id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get http router %s: %w", r.id, err)
+		}
+		if err = a.checkOwner(router.Labels, router.Name, r.name); err != nil {
+			return nil, fmt.Errorf("http router %s can't be adopted: %w", r.id, err)
+		}
+		router.Name = r.name
+		router.Labels = a.adoptedLabels(router.Labels)
+		ret = append(ret, router)
+	}
+	return ret, nil
+}
+
+// checkOwner refuses resources labeled by controllers of other clusters and the ones of other groups of this cluster
+func (a *BalancerAdopter) checkOwner(labels map[string]string, name, adoptedName string) error {
+	owner, ok := labels[a.labels.ClusterLabelName]
+	if !ok {
+		return nil
+	}
+	if owner != a.labels.ClusterID {
+		return fmt.Errorf("owned by cluster %s", owner)
+	}
+	if name != adoptedName {
+		return fmt.Errorf("managed by another ingress group as %s", name)
+	}
+	return nil
+}
+
+func (a *BalancerAdopter) adoptedLabels(labels map[string]string) map[string]string {
+	ret := make(map[string]string, len(labels)+2)
+	for k, v := range labels {
+		ret[k] = v
+	}
+	for k, v := range a.labels.Default() {
+		ret[k] = v
+	}
+	return ret
+}
+
+// checkAdoptable refuses balancers which would be moved to another network or lose their addresses on reconciliation
+func checkAdoptable(balancer, desired *apploadbalancer.LoadBalancer) error {
+	if desired.NetworkId != "" && balancer.NetworkId != desired.NetworkId {
+		return fmt.Errorf("it's in network %s, not in network %s of the group", balancer.NetworkId, desired.NetworkId)
+	}
+
+	desiredAddresses := make(map[string]bool)
+	for _, l := range desired.Listeners {
+		for _, e := range l.Endpoints {
+			for _, addr := range e.Addresses {
+				desiredAddresses[address(addr)] = true
+			}
+		}
+	}
+	for _, l := range balancer.Listeners {
+		for _, e := range l.Endpoints {
+			for _, addr := range e.Addresses {
+				if s := address(addr); s != "" && !desiredAddresses[s] {
+					return fmt.Errorf("address %s of listener %s is missing in the group, set it in addresses of ingress group settings", s, l.Name)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func address(addr *apploadbalancer.Address) string {
+	switch {
+	case addr.GetExternalIpv4Address() != nil:
+		return addr.GetExternalIpv4Address().GetAddress()
+	case addr.GetExternalIpv6Address() != nil:
+		return addr.GetExternalIpv6Address().GetAddress()
+	default:
+		return addr.GetInternalIpv4Address().GetAddress()
+	}
+}
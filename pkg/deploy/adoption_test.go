@@ -0,0 +1,164 @@
+package deploy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/yandex-cloud/go-genproto/yandex/cloud/apploadbalancer/v1"
+	"github.com/yandex-cloud/go-genproto/yandex/cloud/operation"
+
+	"github.com/yandex-cloud/yc-alb-ingress-controller/pkg/deploy/mocks"
+	ycerrors "github.com/yandex-cloud/yc-alb-ingress-controller/pkg/errors"
+	"github.com/yandex-cloud/yc-alb-ingress-controller/pkg/metadata"
+	"github.com/yandex-cloud/yc-alb-ingress-controller/pkg/yc"
+)
+
+func TestBalancerAdopter(t *testing.T) {
+	ctx := context.Background()
+	fakeOp := &operation.Operation{Id: "1234"}
+	names := &metadata.Names{ClusterID: "my-cluster"}
+	labels := &metadata.Labels{ClusterLabelName: "cluster_ref_label", ClusterID: "my-cluster"}
+
+	ipv4 := func(s string) *apploadbalancer.Address {
+		return &apploadbalancer.Address{Address: &apploadbalancer.Address_ExternalIpv4Address{
+			ExternalIpv4Address: &apploadbalancer.ExternalIpv4Address{Address: s},
+		}}
+	}
+	httpListener := func(routerID string, addrs ...*apploadbalancer.Address) *apploadbalancer.Listener {
+		return &apploadbalancer.Listener{
+			Name:      "http",
+			Endpoints: []*apploadbalancer.Endpoint{{Addresses: addrs, Ports: []int64{80}}},
+			Listener: &apploadbalancer.Listener_Http{Http: &apploadbalancer.HttpListener{
+				Handler: &apploadbalancer.HttpHandler{HttpRouterId: routerID},
+			}},
+		}
+	}
+	existing := func(balancerLabels map[string]string) *apploadbalancer.LoadBalancer {
+		return &apploadbalancer.LoadBalancer{
+			Id:        "alb-id",
+			Name:      "legacy-alb",
+			FolderId:  "folder",
+			NetworkId: "network",
+			Labels:    balancerLabels,
+			Listeners: []*apploadbalancer.Listener{httpListener("router-id", ipv4("1.2.3.4"))},
+		}
+	}
+	desired := &apploadbalancer.LoadBalancer{
+		NetworkId: "network",
+		Listeners: []*apploadbalancer.Listener{httpListener("", ipv4("1.2.3.4"))},
+	}
+
+	for _, tc := range []struct {
+		desc    string
+		setup   func(repo *mocks.MockBalancerAdoptionRepo)
+		desired *apploadbalancer.LoadBalancer
+		wantErr error
+	}{
+		{
+			desc: "adopted",
+			setup: func(repo *mocks.MockBalancerAdoptionRepo) {
+				repo.EXPECT().FindAllResources(gomock.Any(), "tag").Return(&yc.BalancerResources{}, nil)
+				repo.EXPECT().GetLoadBalancer(gomock.Any(), "alb-id").Return(existing(map[string]string{"team": "web"}), nil)
+				repo.EXPECT().ListLoadBalancers(gomock.Any()).Return([]*apploadbalancer.LoadBalancer{
+					existing(nil),
+					{Id: "other", Listeners: []*apploadbalancer.Listener{httpListener("other-router")}},
+				}, nil)
+				repo.EXPECT().GetHTTPRouter(gomock.Any(), "router-id").Return(&apploadbalancer.HttpRouter{Id: "router-id", Name: "legacy-router"}, nil)
+				repo.EXPECT().RelabelHTTPRouter(gomock.Any(), &apploadbalancer.HttpRouter{
+					Id:     "router-id",
+					Name:   names.Router("tag"),
+					Labels: labels.Default(),
+				}).Return(fakeOp, nil)
+				relabeled := existing(map[string]string{"team": "web", "system": "yc-alb-ingress", "cluster_ref_label": "my-cluster"})
+				relabeled.Name = names.ALB("tag")
+				repo.EXPECT().RelabelLoadBalancer(gomock.Any(), relabeled).Return(fakeOp, nil)
+			},
+			desired: desired,
+			wantErr: ycerrors.OperationIncompleteError{ID: fakeOp.Id},
+		},
+		{
+			desc: "already adopted",
+			setup: func(repo *mocks.MockBalancerAdoptionRepo) {
+				repo.EXPECT().FindAllResources(gomock.Any(), "tag").Return(&yc.BalancerResources{Balancer: existing(nil)}, nil)
+			},
+			desired: desired,
+		},
+		{
+			desc: "group has another balancer",
+			setup: func(repo *mocks.MockBalancerAdoptionRepo) {
+				repo.EXPECT().FindAllResources(gomock.Any(), "tag").Return(&yc.BalancerResources{
+					Balancer: &apploadbalancer.LoadBalancer{Id: "another-id"},
+				}, nil)
+			},
+			desired: desired,
+			wantErr: assert.AnError,
+		},
+		{
+			desc: "owned by another cluster",
+			setup: func(repo *mocks.MockBalancerAdoptionRepo) {
+				repo.EXPECT().FindAllResources(gomock.Any(), "tag").Return(&yc.BalancerResources{}, nil)
+				repo.EXPECT().GetLoadBalancer(gomock.Any(), "alb-id").Return(existing(map[string]string{"cluster_ref_label": "other-cluster"}), nil)
+			},
+			desired: desired,
+			wantErr: assert.AnError,
+		},
+		{
+			desc: "managed by another group",
+			setup: func(repo *mocks.MockBalancerAdoptionRepo) {
+				repo.EXPECT().FindAllResources(gomock.Any(), "tag").Return(&yc.BalancerResources{}, nil)
+				b := existing(labels.Default())
+				b.Name = names.ALB("other-tag")
+				repo.EXPECT().GetLoadBalancer(gomock.Any(), "alb-id").Return(b, nil)
+			},
+			desired: desired,
+			wantErr: assert.AnError,
+		},
+		{
+			desc: "address missing in group",
+			setup: func(repo *mocks.MockBalancerAdoptionRepo) {
+				repo.EXPECT().FindAllResources(gomock.Any(), "tag").Return(&yc.BalancerResources{}, nil)
+				repo.EXPECT().GetLoadBalancer(gomock.Any(), "alb-id").Return(existing(nil), nil)
+			},
+			desired: &apploadbalancer.LoadBalancer{
+				NetworkId: "network",
+				Listeners: []*apploadbalancer.Listener{httpListener("", ipv4(""))},
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			desc: "router shared with another balancer",
+			setup: func(repo *mocks.MockBalancerAdoptionRepo) {
+				repo.EXPECT().FindAllResources(gomock.Any(), "tag").Return(&yc.BalancerResources{}, nil)
+				repo.EXPECT().GetLoadBalancer(gomock.Any(), "alb-id").Return(existing(nil), nil)
+				repo.EXPECT().ListLoadBalancers(gomock.Any()).Return([]*apploadbalancer.LoadBalancer{
+					{Id: "other", Listeners: []*apploadbalancer.Listener{httpListener("router-id")}},
+				}, nil)
+				relabeled := existing(labels.Default())
+				relabeled.Name = names.ALB("tag")
+				repo.EXPECT().RelabelLoadBalancer(gomock.Any(), relabeled).Return(fakeOp, nil)
+			},
+			desired: desired,
+			wantErr: ycerrors.OperationIncompleteError{ID: fakeOp.Id},
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mocks.NewMockBalancerAdoptionRepo(ctrl)
+			tc.setup(repo)
+			err := NewBalancerAdopter(repo, names, labels, "folder").Adopt(ctx, "tag", "alb-id", tc.desired)
+			switch tc.wantErr {
+			case nil:
+				assert.NoError(t, err)
+			case assert.AnError:
+				assert.Error(t, err)
+				assert.NotErrorIs(t, err, ycerrors.OperationIncompleteError{ID: fakeOp.Id})
+			default:
+				assert.Equal(t, tc.wantErr, err)
+			}
+		})
+	}
+}
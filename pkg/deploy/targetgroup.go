@@ -3,6 +3,8 @@ package deploy
 import (
 	"context"
 	"fmt"
+	"sync"
+	"time"
 
 	"github.com/yandex-cloud/go-genproto/yandex/cloud/apploadbalancer/v1"
 	"github.com/yandex-cloud/go-genproto/yandex/cloud/operation"
@@ -26,15 +28,31 @@ type TargetGroupRepo interface {
 
 type TargetGroupDeployer struct {
 	repo TargetGroupRepo
+
+	// deregistrationDelay is time targets which left a service are kept in its target group,
+	// so that requests in flight to them are completed
+	deregistrationDelay time.Duration
+
+	mu sync.Mutex
+	// deregistering holds time when targets left the service by target group name and target
+	deregistering map[string]map[string]time.Time
+	now           func() time.Time
 }
 
-func NewServiceDeployer(repo TargetGroupRepo) *TargetGroupDeployer {
+func NewServiceDeployer(repo TargetGroupRepo, deregistrationDelay time.Duration) *TargetGroupDeployer {
 	return &TargetGroupDeployer{
-		repo: repo,
+		repo:                repo,
+		deregistrationDelay: deregistrationDelay,
+		deregistering:       make(map[string]map[string]time.Time),
+		now:                 time.Now,
 	}
 }
 
 func (d *TargetGroupDeployer) Undeploy(ctx context.Context, name string) (*apploadbalancer.TargetGroup, error) {
+	d.mu.Lock()
+	delete(d.deregistering, name)
+	d.mu.Unlock()
+
 	tg, err := d.repo.FindTargetGroup(ctx, name)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find target group: %w", err)
@@ -74,6 +92,7 @@ func (d *TargetGroupDeployer) Deploy(ctx context.Context, expected *apploadbalan
 		return nil, ycerrors.OperationIncompleteError{ID: ops[0].Id}
 	}
 
+	expected.Targets = d.keepDeregisteringTargets(expected.Name, expected.Targets, actual.Targets)
 	if tgUpdateNeeded(expected.Targets, actual.Targets) {
 		expected.Id = actual.Id
 		op, err := d.repo.UpdateTargetGroup(ctx, expected)
@@ -89,6 +108,74 @@ func (d *TargetGroupDeployer) Deploy(ctx context.Context, expected *apploadbalan
 	return actual, nil
 }
 
+// keepDeregisteringTargets appends actual targets missing in the expected ones to them until deregistration delay
+// passes since they were found missing for the first time. Kept targets stay live ones of the target group,
+// so the balancer keeps sending them new requests during the delay
+func (d *TargetGroupDeployer) keepDeregisteringTargets(name string, expected, actual []*apploadbalancer.Target) []*apploadbalancer.Target {
+	if d.deregistrationDelay == 0 {
+		return expected
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	expectedKeys := make(map[string]struct{}, len(expected))
+	for _, t := range expected {
+		expectedKeys[targetKey(t)] = struct{}{}
+	}
+
+	now := d.now()
+	deregistering := make(map[string]time.Time)
+	for _, t := range actual {
+		key := targetKey(t)
+		if _, ok := expectedKeys[key]; ok {
+			continue
+		}
+
+		since, ok := d.deregistering[name][key]
+		if !ok {
+			since = now
+		}
+		if now.Sub(since) >= d.deregistrationDelay {
+			continue
+		}
+
+		deregistering[key] = since
+		expected = append(expected, t)
+	}
+
+	if len(deregistering) == 0 {
+		delete(d.deregistering, name)
+	} else {
+		d.deregistering[name] = deregistering
+	}
+	return expected
+}
+
+// DeregistrationPending returns time left until the next target kept in the target group by deregistration delay
+// can be removed, zero if there are no such targets
+func (d *TargetGroupDeployer) DeregistrationPending(name string) time.Duration {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var ret time.Duration
+	now := d.now()
+	for _, since := range d.deregistering[name] {
+		left := d.deregistrationDelay - now.Sub(since)
+		if left <= 0 {
+			left = time.Second
+		}
+		if ret == 0 || left < ret {
+			ret = left
+		}
+	}
+	return ret
+}
+
+func targetKey(t *apploadbalancer.Target) string {
+	return t.GetSubnetId() + "/" + t.GetIpAddress()
+}
+
 func tgUpdateNeeded(actual, expected []*apploadbalancer.Target) bool {
 	if len(expected) != len(actual) {
 		return true
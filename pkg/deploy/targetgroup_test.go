@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
@@ -53,7 +54,7 @@ func TestTargetGroupDeployer(t *testing.T) {
 		repo.EXPECT().FindTargetGroup(gomock.Any(), "tg").Return(nil, nil)
 		repo.EXPECT().CreateTargetGroup(gomock.Any(), expTG).Return(fakeOp, nil)
 
-		d := NewServiceDeployer(repo)
+		d := NewServiceDeployer(repo, 0)
 		_, err := d.Deploy(ctx, expTG)
 		assert.ErrorAs(t, err, &ycerrors.OperationIncompleteError{})
 	})
@@ -68,7 +69,7 @@ func TestTargetGroupDeployer(t *testing.T) {
 		repo.EXPECT().UpdateTargetGroup(gomock.Any(), expTG).Return(fakeOp, nil)
 		repo.EXPECT().ListTargetGroupOperations(gomock.Any(), gomock.Any()).Times(1).Return(nil, nil)
 
-		d := NewServiceDeployer(repo)
+		d := NewServiceDeployer(repo, 0)
 		_, err := d.Deploy(ctx, expTG)
 		assert.ErrorAs(t, err, &ycerrors.OperationIncompleteError{})
 	})
@@ -81,7 +82,7 @@ func TestTargetGroupDeployer(t *testing.T) {
 		repo.EXPECT().FindTargetGroup(gomock.Any(), "tg").Return(tg, nil)
 		repo.EXPECT().DeleteTargetGroup(gomock.Any(), tg).Return(ycerrors.OperationIncompleteError{ID: fakeOp.Id})
 
-		d := NewServiceDeployer(repo)
+		d := NewServiceDeployer(repo, 0)
 		_, err := d.Undeploy(ctx, "tg")
 		assert.ErrorAs(t, err, &ycerrors.OperationIncompleteError{})
 	})
@@ -96,7 +97,7 @@ func TestTargetGroupDeployer(t *testing.T) {
 		repo.EXPECT().UpdateTargetGroup(gomock.Any(), expTG).Return(nil, fmt.Errorf("error"))
 		repo.EXPECT().ListTargetGroupOperations(gomock.Any(), gomock.Any()).Times(1).Return(nil, nil)
 
-		d := NewServiceDeployer(repo)
+		d := NewServiceDeployer(repo, 0)
 		_, err := d.Deploy(ctx, expTG)
 		assert.NotNil(t, err)
 		assert.NotErrorIs(t, errOpIncomplete, err)
@@ -111,8 +112,42 @@ func TestTargetGroupDeployer(t *testing.T) {
 		repo.EXPECT().FindTargetGroup(gomock.Any(), "tg").Return(tg, nil)
 		repo.EXPECT().ListTargetGroupOperations(gomock.Any(), gomock.Any()).Times(1).Return(nil, nil)
 
-		d := NewServiceDeployer(repo)
+		d := NewServiceDeployer(repo, 0)
 		_, err := d.Deploy(ctx, expTG)
 		assert.Nil(t, err)
 	})
+
+	t.Run("deregistration delay", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repo := mocks.NewMockTargetGroupRepo(ctrl)
+
+		now := time.Now()
+		d := NewServiceDeployer(repo, time.Minute)
+		d.now = func() time.Time { return now }
+
+		// t2 left the service, it is kept in the group and t3 is added
+		repo.EXPECT().FindTargetGroup(gomock.Any(), "tg").Return(makeTargetGroup("tg", t1, t2), nil)
+		repo.EXPECT().ListTargetGroupOperations(gomock.Any(), gomock.Any()).Return(nil, nil)
+		repo.EXPECT().UpdateTargetGroup(gomock.Any(), makeTargetGroup("tg", t1, t3, t2)).Return(fakeOp, nil)
+		_, err := d.Deploy(ctx, makeTargetGroup("tg", t1, t3))
+		assert.ErrorAs(t, err, &ycerrors.OperationIncompleteError{})
+		assert.Equal(t, time.Minute, d.DeregistrationPending("tg"))
+
+		// delay has not expired yet
+		now = now.Add(40 * time.Second)
+		repo.EXPECT().FindTargetGroup(gomock.Any(), "tg").Return(makeTargetGroup("tg", t1, t3, t2), nil)
+		repo.EXPECT().ListTargetGroupOperations(gomock.Any(), gomock.Any()).Return(nil, nil)
+		_, err = d.Deploy(ctx, makeTargetGroup("tg", t1, t3))
+		assert.NoError(t, err)
+		assert.Equal(t, 20*time.Second, d.DeregistrationPending("tg"))
+
+		// delay has expired
+		now = now.Add(20 * time.Second)
+		repo.EXPECT().FindTargetGroup(gomock.Any(), "tg").Return(makeTargetGroup("tg", t1, t3, t2), nil)
+		repo.EXPECT().ListTargetGroupOperations(gomock.Any(), gomock.Any()).Return(nil, nil)
+		repo.EXPECT().UpdateTargetGroup(gomock.Any(), makeTargetGroup("tg", t1, t3)).Return(fakeOp, nil)
+		_, err = d.Deploy(ctx, makeTargetGroup("tg", t1, t3))
+		assert.ErrorAs(t, err, &ycerrors.OperationIncompleteError{})
+		assert.Zero(t, d.DeregistrationPending("tg"))
+	})
 }
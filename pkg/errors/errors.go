@@ -2,6 +2,7 @@ package errors2
 
 import (
 	"fmt"
+	"time"
 )
 
 type OperationIncompleteError struct {
@@ -31,3 +32,14 @@ type YCResourceNotReadyError struct {
 func (e YCResourceNotReadyError) Error() string {
 	return fmt.Sprintf("resource %s (%s) not ready", e.ResourceType, e.Name)
 }
+
+// TargetsDeregistrationError is returned when targets which left a service are kept in its target group until
+// deregistration delay expires, reconciliation should be repeated after RequeueAfter to remove them
+type TargetsDeregistrationError struct {
+	TargetGroup  string
+	RequeueAfter time.Duration
+}
+
+func (e TargetsDeregistrationError) Error() string {
+	return fmt.Sprintf("targets of target group %s are being deregistered, %s left", e.TargetGroup, e.RequeueAfter)
+}
@@ -1,10 +1,16 @@
 package k8s
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"regexp"
+	"strconv"
 	"strings"
 
+	networking "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
 )
 
 const (
@@ -25,16 +31,53 @@ const (
 
 	AllowHTTP10 = prefix + "/allow-http10"
 
+	// HTTP2 enables HTTP/2 on listeners of the group, HTTP2MaxConcurrentStreams limits the number of concurrent
+	// streams in a connection. Both must have the same value on all ingresses of the group.
+	HTTP2                     = prefix + "/http2"
+	HTTP2MaxConcurrentStreams = prefix + "/http2-max-concurrent-streams"
+
+	// RedirectHTTPToHTTPS makes HTTP listener of the group redirect all requests to HTTPS instead of routing them with
+	// HTTP router, which is not built then. Hosts without TLS are served over HTTPS with the default certificate.
+	RedirectHTTPToHTTPS = prefix + "/redirect-http-to-https"
+
+	// StreamListeners declares stream (TCP) listeners of the balancer as a list of port=StreamBackendGroup pairs,
+	// e.g. "5432=postgres,1883=mqtt". Connections are forwarded as is, so TLS traffic is passed through to backends.
+	StreamListeners = prefix + "/stream-listeners"
+
 	RequestTimeout = prefix + "/request-timeout"
 	IdleTimeout    = prefix + "/idle-timeout"
 	PrefixRewrite  = prefix + "/prefix-rewrite"
 	UpgradeTypes   = prefix + "/upgrade-types"
 	AllowedMethods = prefix + "/allowed-methods"
 
+	// HostRewrite replaces Host header of requests with the value, AutoHostRewrite replaces it with the address of the target
+	HostRewrite     = prefix + "/host-rewrite"
+	AutoHostRewrite = prefix + "/auto-host-rewrite"
+
 	Protocol          = prefix + "/protocol"
 	TransportSecurity = prefix + "/transport-security"
 	HealthChecks      = prefix + "/health-checks"
 
+	// RBAC of virtual hosts. Remote IPs are a list of CIDRs or IPs, headers are a list of name=value pairs.
+	// A request matches if it comes from any of the remote IPs and has all the headers.
+	RBACAction    = prefix + "/rbac-action"
+	RBACRemoteIPs = prefix + "/rbac-remote-ips"
+	RBACHeaders   = prefix + "/rbac-headers"
+
+	// RBAC of routes, same format as for virtual hosts
+	RouteRBACAction    = prefix + "/route-rbac-action"
+	RouteRBACRemoteIPs = prefix + "/route-rbac-remote-ips"
+	RouteRBACHeaders   = prefix + "/route-rbac-headers"
+
+	// RouteSecurityProfileID is Smart Web Security profile of routes, it takes precedence over SecurityProfileID
+	// of their virtual hosts, so e.g. login endpoints may be protected by a profile with stricter rate limits
+	RouteSecurityProfileID = prefix + "/route-security-profile-id"
+
+	// Canary marks ingress as a canary of the ingress with the same host and path in the group.
+	// Canary weight is a percentage of requests routed to the canary service, 0 by default.
+	Canary       = prefix + "/canary"
+	CanaryWeight = prefix + "/canary-weight"
+
 	UseRegex     = prefix + "/use-regex"
 	OrderInGroup = prefix + "/group-order"
 
@@ -67,9 +110,25 @@ const (
 	DirectResponsePrefix = prefix + "/direct-response."
 	RedirectPrefix       = prefix + "/redirect."
 
+	// GRPCStatusResponsePrefix declares backend resources of kind GrpcStatusResponse answering with gRPC status,
+	// e.g. "status=UNIMPLEMENTED"
+	GRPCStatusResponsePrefix = prefix + "/grpc-status-response."
+
+	// CertificateIDs maps TLS hosts of ingress to IDs of existing Certificate Manager certificates as a list of
+	// host=certID pairs, e.g. managed Let's Encrypt certificates
+	CertificateIDs = prefix + "/certificate-ids"
+
+	// LockboxSecretVersions pins versions of Lockbox secrets referenced by TLS items of ingress as a list of
+	// secretID=versionID pairs, the current versions of the other secrets are used
+	LockboxSecretVersions = prefix + "/lockbox-secret-versions"
+
 	DefaultIngressClass = "ingressclass.kubernetes.io/is-default-class"
 
 	PreferIPv6Targets = prefix + "/prefer-ipv6-targets"
+
+	// TargetMode of service is either "node" (cluster nodes serving NodePort are targets) or "pod-ip"
+	// (ready pods are targets and traffic goes directly to their container port)
+	TargetMode = prefix + "/target-mode"
 )
 
 func GetBalancerTag(o metav1.Object) string {
@@ -84,71 +143,159 @@ func HasBalancerTag(o metav1.Object) bool {
 	return ok
 }
 
+// ParseConfigsFromAnnotationValue parses value of a multi-field annotation. The value is either a JSON or YAML mapping,
+// which allows commas and equals signs in values, or comma separated key=value pairs
 func ParseConfigsFromAnnotationValue(s string) (map[string]string, error) {
 	if len(s) == 0 {
 		return nil, nil
 	}
+	if isStructuredAnnotationValue(s) {
+		return parseStructuredAnnotationValue(s, false)
+	}
 
 	result := make(map[string]string)
 
 	elements := strings.Split(s, ",")
 	for _, element := range elements {
-		words := strings.Split(element, "=")
-		if len(words) != 2 {
-			return nil, fmt.Errorf("wrong config format in annotation: %s", s)
-		}
-
-		if len(words[0]) == 0 {
-			return nil, fmt.Errorf("empty key in annotation: %s", s)
+		key, value, err := parseAnnotationElement(element)
+		if err != nil {
+			return nil, fmt.Errorf("%w in annotation: %s", err, s)
 		}
 
-		result[words[0]] = words[1]
+		result[key] = value
 	}
 
 	return result, nil
 }
 
+// ParseModifyHeadersFromAnnotationValue parses the value like ParseConfigsFromAnnotationValue, but values of repeated
+// headers are joined with commas. In JSON or YAML mapping they may be set by a list
 func ParseModifyHeadersFromAnnotationValue(s string) (map[string]string, error) {
 	if len(s) == 0 {
 		return nil, nil
 	}
+	if isStructuredAnnotationValue(s) {
+		return parseStructuredAnnotationValue(s, true)
+	}
 
 	result := make(map[string]string)
 
 	elements := strings.Split(s, ",")
 	for _, element := range elements {
-		words := strings.Split(element, "=")
-		if len(words) != 2 {
-			return nil, fmt.Errorf("wrong config format in annotation: %s", s)
+		key, value, err := parseAnnotationElement(element)
+		if err != nil {
+			return nil, fmt.Errorf("%w in annotation: %s", err, s)
 		}
 
-		if len(words[0]) == 0 {
-			return nil, fmt.Errorf("empty key in annotation: %s", s)
-		}
-
-		if _, has := result[words[0]]; has {
-			result[words[0]] += "," + words[1]
+		if _, has := result[key]; has {
+			result[key] += "," + value
 		} else {
-			result[words[0]] = words[1]
+			result[key] = value
 		}
 	}
 
 	return result, nil
 }
 
-func GetIngressGroupAnnotation(g *IngressGroup, annotation string) (string, error) {
-	result := ""
+func parseAnnotationElement(element string) (string, string, error) {
+	words := strings.Split(element, "=")
+	if len(words) != 2 {
+		return "", "", fmt.Errorf("wrong config format of %q, expected key=value, use JSON or YAML mapping for values with commas or equals signs", element)
+	}
+
+	if len(words[0]) == 0 {
+		return "", "", fmt.Errorf("empty key of %q", element)
+	}
 
-	for _, item := range g.Items {
-		curr := item.Annotations[annotation]
-		if result == "" {
-			result = curr
+	return words[0], words[1], nil
+}
+
+// yamlKeyRe matches the beginning of YAML block mapping, legacy values can't start with it as their keys are
+// followed by equals sign
+var yamlKeyRe = regexp.MustCompile(`^[\w.-]+:(\s|$)`)
+
+func isStructuredAnnotationValue(s string) bool {
+	s = strings.TrimSpace(s)
+	return strings.HasPrefix(s, "{") || yamlKeyRe.MatchString(s)
+}
+
+func parseStructuredAnnotationValue(s string, allowLists bool) (map[string]string, error) {
+	data, err := yaml.YAMLToJSON([]byte(s))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse annotation as JSON or YAML: %w", err)
+	}
+
+	var raw map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err = decoder.Decode(&raw); err != nil {
+		return nil, fmt.Errorf("annotation must be a JSON or YAML mapping: %w", err)
+	}
+
+	result := make(map[string]string, len(raw))
+	for key, value := range raw {
+		if len(key) == 0 {
+			return nil, fmt.Errorf("empty key in annotation: %s", s)
+		}
+
+		list, isList := value.([]interface{})
+		if !isList {
+			result[key], err = annotationScalar(value)
+			if err != nil {
+				return nil, fmt.Errorf("wrong value of key %q: %w", key, err)
+			}
+			continue
 		}
 
-		if curr != "" && curr != result {
-			return "", fmt.Errorf("%s annotation has different values in single ingressgroup %s, values: %s, %s", annotation, g.Tag, curr, result)
+		if !allowLists {
+			_, err = annotationScalar(value)
+			return nil, fmt.Errorf("wrong value of key %q: %w", key, err)
+		}
+		values := make([]string, 0, len(list))
+		for _, item := range list {
+			v, err := annotationScalar(item)
+			if err != nil {
+				return nil, fmt.Errorf("wrong value of key %q: %w", key, err)
+			}
+			values = append(values, v)
 		}
+		result[key] = strings.Join(values, ",")
 	}
 
 	return result, nil
 }
+
+func annotationScalar(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case json.Number:
+		return v.String(), nil
+	case []interface{}:
+		return "", fmt.Errorf("must be a string, number or boolean, got list")
+	default:
+		return "", fmt.Errorf("must be a string, number or boolean, got mapping")
+	}
+}
+
+// HostCertificateIDs returns certificate IDs of hosts set by CertificateIDs annotation of ingress, hosts must be listed
+// in its TLS items
+func HostCertificateIDs(ing networking.Ingress) (map[string]string, error) {
+	certIDs, err := ParseConfigsFromAnnotationValue(ing.GetAnnotations()[CertificateIDs])
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse certificate ids: %w", err)
+	}
+	for host, certID := range certIDs {
+		if certID == "" {
+			return nil, fmt.Errorf("empty certificate id for host %s", host)
+		}
+		if !IsTLS(host, ing.Spec.TLS) {
+			return nil, fmt.Errorf("host %s with certificate id %s is not listed in tls of ingress", host, certID)
+		}
+	}
+	return certIDs, nil
+}
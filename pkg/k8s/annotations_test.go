@@ -4,6 +4,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	networking "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 type parseAnnTestCase struct {
@@ -48,10 +50,53 @@ var parseAnnTestCases = []parseAnnTestCase{
 		value:   "key1=1+1=5",
 		wantErr: true,
 	},
+	{
+		name:  "json",
+		value: `{"body": "a=b, c=d", "status": 200, "remove_query": true, "empty": null}`,
+		exp:   map[string]string{"body": "a=b, c=d", "status": "200", "remove_query": "true", "empty": ""},
+	},
+	{
+		name:  "yaml",
+		value: "path: replace_prefix\nreplace_prefix: \"/v1/a,b=c\"\n",
+		exp:   map[string]string{"path": "replace_prefix", "replace_prefix": "/v1/a,b=c"},
+	},
+	{
+		name:  "yaml with single key",
+		value: "port: 8080",
+		exp:   map[string]string{"port": "8080"},
+	},
+	{
+		name:  "yaml flow mapping",
+		value: "{name: x-user, ttl: 1h}",
+		exp:   map[string]string{"name": "x-user", "ttl": "1h"},
+	},
+	{
+		name:    "invalid json",
+		value:   `{"key1": "value1"`,
+		wantErr: true,
+	},
+	{
+		name:    "nested mapping",
+		value:   `{"key1": {"key2": "value2"}}`,
+		wantErr: true,
+	},
+	{
+		name:    "empty structured key",
+		value:   `{"": "value1"}`,
+		wantErr: true,
+	},
 }
 
 func TestParseConfigsFromAnnotationValue(t *testing.T) {
-	for _, tc := range parseAnnTestCases {
+	var testCases []parseAnnTestCase
+	testCases = append(testCases, parseAnnTestCases...)
+	testCases = append(testCases, parseAnnTestCase{
+		name:    "list",
+		value:   `{"key1": ["value1", "value2"]}`,
+		wantErr: true,
+	})
+
+	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			actual, err := ParseConfigsFromAnnotationValue(tc.value)
 			if tc.wantErr {
@@ -81,6 +126,16 @@ func TestParseModifyHeadersFromAnnotationValue(t *testing.T) {
 				exp:     map[string]string{"h1": "v1,v2", "h2": "v4,v3", "h3": "v5"},
 				wantErr: false,
 			},
+			{
+				name:  "header values list",
+				value: "X-Robots-Tag: [noarchive, nofollow]\nX-Query: a=b",
+				exp:   map[string]string{"X-Robots-Tag": "noarchive,nofollow", "X-Query": "a=b"},
+			},
+			{
+				name:    "nested list",
+				value:   `{"h1": [["v1"]]}`,
+				wantErr: true,
+			},
 		}...,
 	)
 
@@ -96,3 +151,51 @@ func TestParseModifyHeadersFromAnnotationValue(t *testing.T) {
 		})
 	}
 }
+
+func TestHostCertificateIDs(t *testing.T) {
+	testCases := []struct {
+		name    string
+		value   string
+		exp     map[string]string
+		wantErr bool
+	}{
+		{
+			name:  "empty",
+			value: "",
+			exp:   nil,
+		},
+		{
+			name:  "OK",
+			value: "example.com=cert-1,www.example.com=cert-2",
+			exp:   map[string]string{"example.com": "cert-1", "www.example.com": "cert-2"},
+		},
+		{
+			name:    "empty certificate id",
+			value:   "example.com=",
+			wantErr: true,
+		},
+		{
+			name:    "host not listed in tls",
+			value:   "example.com=cert-1,api.example.com=cert-2",
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ing := networking.Ingress{
+				ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{CertificateIDs: tc.value}},
+				Spec: networking.IngressSpec{
+					TLS: []networking.IngressTLS{{Hosts: []string{"example.com", "www.example.com"}}},
+				},
+			}
+			actual, err := HostCertificateIDs(ing)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.exp, actual)
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package k8s
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"time"
+
+	"github.com/yandex-cloud/go-genproto/yandex/cloud/certificatemanager/v1"
+)
+
+// ExpiryCheckInterval is the longest time between checks of expiry of a certificate, so warnings about
+// expiring certificates are repeated
+const ExpiryCheckInterval = 24 * time.Hour
+
+// CertificateNotAfter returns expiration time of the leaf certificate, the first one of PEM encoded chain
+func CertificateNotAfter(chain []byte) (time.Time, error) {
+	block, _ := pem.Decode(chain)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return time.Time{}, fmt.Errorf("failed to decode certificate")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to parse certificate: %w", err)
+	}
+	return cert.NotAfter, nil
+}
+
+// ExpiresWithin reports whether certificate expiring at notAfter expires within window from now
+func ExpiresWithin(notAfter, now time.Time, window time.Duration) bool {
+	return notAfter.Before(now.Add(window))
+}
+
+// NextExpiryCheck returns time after which expiry of certificate expiring at notAfter should be checked again:
+// the moment it enters the warning window, or ExpiryCheckInterval if it's sooner
+func NextExpiryCheck(notAfter, now time.Time, window time.Duration) time.Duration {
+	next := notAfter.Add(-window).Sub(now)
+	if next <= 0 || next > ExpiryCheckInterval {
+		return ExpiryCheckInterval
+	}
+	return next
+}
+
+// CheckCertificateReplacement returns error if certificate expiring at notAfter is already expired and so must not
+// replace the current one which is still valid
+func CheckCertificateReplacement(notAfter time.Time, current *certificatemanager.Certificate, now time.Time) error {
+	if notAfter.After(now) || current.GetNotAfter() == nil {
+		return nil
+	}
+	if currentNotAfter := current.GetNotAfter().AsTime(); currentNotAfter.After(now) {
+		return fmt.Errorf("new certificate expired at %s while the current one is valid until %s",
+			notAfter.Format(time.RFC3339), currentNotAfter.Format(time.RFC3339))
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package k8s
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yandex-cloud/go-genproto/yandex/cloud/certificatemanager/v1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func selfSignedCertificate(t *testing.T, notAfter time.Time) []byte {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "example.com"},
+		NotBefore:    notAfter.Add(-90 * 24 * time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func TestCertificateNotAfter(t *testing.T) {
+	notAfter := time.Date(2026, 12, 1, 0, 0, 0, 0, time.UTC)
+	leaf := selfSignedCertificate(t, notAfter)
+	chain := append(leaf, selfSignedCertificate(t, notAfter.AddDate(1, 0, 0))...)
+
+	actual, err := CertificateNotAfter(chain)
+	require.NoError(t, err)
+	assert.True(t, notAfter.Equal(actual))
+
+	_, err = CertificateNotAfter([]byte("not a certificate"))
+	assert.Error(t, err)
+}
+
+func TestNextExpiryCheck(t *testing.T) {
+	now := time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)
+	window := 30 * 24 * time.Hour
+
+	assert.Equal(t, ExpiryCheckInterval, NextExpiryCheck(now.Add(window+48*time.Hour), now, window))
+	assert.Equal(t, 2*time.Hour, NextExpiryCheck(now.Add(window+2*time.Hour), now, window))
+	assert.Equal(t, ExpiryCheckInterval, NextExpiryCheck(now.Add(time.Hour), now, window))
+	assert.Equal(t, ExpiryCheckInterval, NextExpiryCheck(now.Add(-time.Hour), now, window))
+
+	assert.False(t, ExpiresWithin(now.Add(window+time.Hour), now, window))
+	assert.True(t, ExpiresWithin(now.Add(window-time.Hour), now, window))
+}
+
+func TestCheckCertificateReplacement(t *testing.T) {
+	now := time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)
+	valid := &certificatemanager.Certificate{NotAfter: timestamppb.New(now.Add(time.Hour))}
+	expired := &certificatemanager.Certificate{NotAfter: timestamppb.New(now.Add(-time.Hour))}
+
+	testCases := []struct {
+		name     string
+		notAfter time.Time
+		current  *certificatemanager.Certificate
+		wantErr  bool
+	}{
+		{name: "valid replaces valid", notAfter: now.Add(48 * time.Hour), current: valid},
+		{name: "valid replaces expired", notAfter: now.Add(48 * time.Hour), current: expired},
+		{name: "expired replaces expired", notAfter: now.Add(-48 * time.Hour), current: expired},
+		{name: "expired replaces not issued", notAfter: now.Add(-48 * time.Hour), current: &certificatemanager.Certificate{}},
+		{name: "expired replaces valid", notAfter: now.Add(-48 * time.Hour), current: valid, wantErr: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := CheckCertificateReplacement(tc.notAfter, tc.current, now)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
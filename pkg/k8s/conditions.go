@@ -0,0 +1,47 @@
+package k8s
+
+import (
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Conditions of resources reconciled by the controller
+const (
+	ConditionReady       = "Ready"
+	ConditionReconciling = "Reconciling"
+	ConditionDegraded    = "Degraded"
+)
+
+// Reasons of conditions, the same reasons are used by events of reconciliation
+const (
+	ReasonReconciliationComplete = "ReconciliationComplete"
+	ReasonReconciliationRequeue  = "ReconciliationRequeue"
+	ReasonReconciliationFailed   = "ReconciliationFailed"
+)
+
+// Reasons of ingress rules statuses, rules of groups failed to reconcile have the reason of the group condition
+const (
+	ReasonRuleAccepted = "Accepted"
+	ReasonRuleInvalid  = "Invalid"
+)
+
+// SetReconcileConditions sets Ready, Reconciling and Degraded conditions by the reason of the reconciliation outcome:
+// complete reconciliation makes the resource ready, requeued one keeps it reconciling and failed one degrades it
+func SetReconcileConditions(conditions *[]metav1.Condition, generation int64, reason, message string) {
+	for _, t := range []string{ConditionReady, ConditionReconciling, ConditionDegraded} {
+		status := metav1.ConditionFalse
+		switch {
+		case t == ConditionReady && reason == ReasonReconciliationComplete,
+			t == ConditionReconciling && reason == ReasonReconciliationRequeue,
+			t == ConditionDegraded && reason == ReasonReconciliationFailed:
+			status = metav1.ConditionTrue
+		}
+		meta.SetStatusCondition(conditions, metav1.Condition{
+			Type:               t,
+			Status:             status,
+			Reason:             reason,
+			Message:            message,
+			ObservedGeneration: generation,
+		})
+	}
+}
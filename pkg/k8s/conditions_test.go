@@ -0,0 +1,47 @@
+package k8s
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSetReconcileConditions(t *testing.T) {
+	for _, tc := range []struct {
+		reason string
+		exp    map[string]metav1.ConditionStatus
+	}{
+		{
+			reason: ReasonReconciliationComplete,
+			exp:    map[string]metav1.ConditionStatus{ConditionReady: metav1.ConditionTrue, ConditionReconciling: metav1.ConditionFalse, ConditionDegraded: metav1.ConditionFalse},
+		},
+		{
+			reason: ReasonReconciliationRequeue,
+			exp:    map[string]metav1.ConditionStatus{ConditionReady: metav1.ConditionFalse, ConditionReconciling: metav1.ConditionTrue, ConditionDegraded: metav1.ConditionFalse},
+		},
+		{
+			reason: ReasonReconciliationFailed,
+			exp:    map[string]metav1.ConditionStatus{ConditionReady: metav1.ConditionFalse, ConditionReconciling: metav1.ConditionFalse, ConditionDegraded: metav1.ConditionTrue},
+		},
+	} {
+		t.Run(tc.reason, func(t *testing.T) {
+			// conditions of the previous reconciliation are replaced
+			var conditions []metav1.Condition
+			SetReconcileConditions(&conditions, 1, ReasonReconciliationFailed, "failed")
+			SetReconcileConditions(&conditions, 2, tc.reason, "message")
+
+			assert.Len(t, conditions, 3)
+			for conditionType, status := range tc.exp {
+				c := meta.FindStatusCondition(conditions, conditionType)
+				if assert.NotNil(t, c, conditionType) {
+					assert.Equal(t, status, c.Status, conditionType)
+					assert.Equal(t, tc.reason, c.Reason)
+					assert.Equal(t, "message", c.Message)
+					assert.Equal(t, int64(2), c.ObservedGeneration)
+				}
+			}
+		})
+	}
+}
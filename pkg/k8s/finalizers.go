@@ -6,7 +6,12 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-type FinalizerManager struct{ Client client.Client }
+type FinalizerManager struct {
+	Client client.Client
+
+	// DryRun keeps finalizers of deleted objects, because their cloud resources are not deleted in dry-run mode
+	DryRun bool
+}
 
 func (m *FinalizerManager) UpdateFinalizer(ctx context.Context, o client.Object, finalizer string) error {
 	var i int
@@ -23,6 +28,9 @@ func (m *FinalizerManager) UpdateFinalizer(ctx context.Context, o client.Object,
 }
 
 func (m *FinalizerManager) RemoveFinalizer(ctx context.Context, o client.Object, finalizer string) error {
+	if m.DryRun {
+		return nil
+	}
 	var i int
 	finalizers := o.GetFinalizers()
 	l := len(finalizers)
@@ -0,0 +1,992 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	core "k8s.io/api/core/v1"
+	networking "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	GatewayAPIGroup   = "gateway.networking.k8s.io"
+	GatewayAPIVersion = "v1"
+
+	GatewayKind   = "Gateway"
+	HTTPRouteKind = "HTTPRoute"
+	GRPCRouteKind = "GRPCRoute"
+
+	gatewayTagPrefix = "gateway."
+
+	albGroup = "alb.yc.io"
+)
+
+var (
+	GatewayClassGVK = schema.GroupVersionKind{Group: GatewayAPIGroup, Version: GatewayAPIVersion, Kind: "GatewayClass"}
+	GatewayGVK      = schema.GroupVersionKind{Group: GatewayAPIGroup, Version: GatewayAPIVersion, Kind: GatewayKind}
+	HTTPRouteGVK    = schema.GroupVersionKind{Group: GatewayAPIGroup, Version: GatewayAPIVersion, Kind: HTTPRouteKind}
+	GRPCRouteGVK    = schema.GroupVersionKind{Group: GatewayAPIGroup, Version: GatewayAPIVersion, Kind: GRPCRouteKind}
+)
+
+// Reasons used in Gateway API conditions
+const (
+	GatewayReasonAccepted                   = "Accepted"
+	GatewayReasonProgrammed                 = "Programmed"
+	GatewayReasonResolvedRefs               = "ResolvedRefs"
+	GatewayReasonPending                    = "Pending"
+	GatewayReasonInvalid                    = "Invalid"
+	GatewayReasonUnsupportedAddress         = "UnsupportedAddress"
+	GatewayReasonUnsupportedProtocol        = "UnsupportedProtocol"
+	GatewayReasonPortUnavailable            = "PortUnavailable"
+	GatewayReasonInvalidCertificateRef      = "InvalidCertificateRef"
+	GatewayReasonNotAllowedByListeners      = "NotAllowedByListeners"
+	GatewayReasonNoMatchingListenerHostname = "NoMatchingListenerHostname"
+	GatewayReasonNoMatchingParent           = "NoMatchingParent"
+	GatewayReasonUnsupportedValue           = "UnsupportedValue"
+	GatewayReasonRefNotPermitted            = "RefNotPermitted"
+	GatewayReasonInvalidKind                = "InvalidKind"
+)
+
+// Gateway API objects are not part of client-go, so the controller reads them as unstructured
+// and decodes only the fields it understands into the types below.
+
+type GatewayClass struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec GatewayClassSpec `json:"spec"`
+}
+
+type GatewayClassSpec struct {
+	ControllerName string `json:"controllerName"`
+}
+
+type Gateway struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec GatewaySpec `json:"spec"`
+}
+
+type GatewaySpec struct {
+	GatewayClassName string            `json:"gatewayClassName"`
+	Listeners        []GatewayListener `json:"listeners"`
+	Addresses        []GatewayAddress  `json:"addresses,omitempty"`
+}
+
+type GatewayListener struct {
+	Name          string                `json:"name"`
+	Hostname      *string               `json:"hostname,omitempty"`
+	Port          int32                 `json:"port"`
+	Protocol      string                `json:"protocol"`
+	TLS           *GatewayTLSConfig     `json:"tls,omitempty"`
+	AllowedRoutes *GatewayAllowedRoutes `json:"allowedRoutes,omitempty"`
+}
+
+type GatewayTLSConfig struct {
+	Mode            *string                  `json:"mode,omitempty"`
+	CertificateRefs []GatewayObjectReference `json:"certificateRefs,omitempty"`
+}
+
+type GatewayObjectReference struct {
+	Group     *string `json:"group,omitempty"`
+	Kind      *string `json:"kind,omitempty"`
+	Name      string  `json:"name"`
+	Namespace *string `json:"namespace,omitempty"`
+}
+
+type GatewayAllowedRoutes struct {
+	Namespaces *GatewayRouteNamespaces `json:"namespaces,omitempty"`
+	Kinds      []GatewayRouteGroupKind `json:"kinds,omitempty"`
+}
+
+type GatewayRouteNamespaces struct {
+	From     *string               `json:"from,omitempty"`
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+}
+
+type GatewayRouteGroupKind struct {
+	Group *string `json:"group,omitempty"`
+	Kind  string  `json:"kind"`
+}
+
+type GatewayAddress struct {
+	Type  *string `json:"type,omitempty"`
+	Value string  `json:"value"`
+}
+
+type ParentReference struct {
+	Group       *string `json:"group,omitempty"`
+	Kind        *string `json:"kind,omitempty"`
+	Namespace   *string `json:"namespace,omitempty"`
+	Name        string  `json:"name"`
+	SectionName *string `json:"sectionName,omitempty"`
+	Port        *int32  `json:"port,omitempty"`
+}
+
+type BackendRef struct {
+	Group     *string `json:"group,omitempty"`
+	Kind      *string `json:"kind,omitempty"`
+	Name      string  `json:"name"`
+	Namespace *string `json:"namespace,omitempty"`
+	Port      *int32  `json:"port,omitempty"`
+	Weight    *int32  `json:"weight,omitempty"`
+}
+
+type RouteFilter struct {
+	Type string `json:"type"`
+}
+
+type HeaderMatch struct {
+	Type  *string `json:"type,omitempty"`
+	Name  string  `json:"name"`
+	Value string  `json:"value"`
+}
+
+type HTTPRoute struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec HTTPRouteSpec `json:"spec"`
+}
+
+type HTTPRouteSpec struct {
+	ParentRefs []ParentReference `json:"parentRefs,omitempty"`
+	Hostnames  []string          `json:"hostnames,omitempty"`
+	Rules      []HTTPRouteRule   `json:"rules,omitempty"`
+}
+
+type HTTPRouteRule struct {
+	Matches     []HTTPRouteMatch `json:"matches,omitempty"`
+	Filters     []RouteFilter    `json:"filters,omitempty"`
+	BackendRefs []BackendRef     `json:"backendRefs,omitempty"`
+}
+
+type HTTPRouteMatch struct {
+	Path        *HTTPPathMatch `json:"path,omitempty"`
+	Headers     []HeaderMatch  `json:"headers,omitempty"`
+	QueryParams []HeaderMatch  `json:"queryParams,omitempty"`
+	Method      *string        `json:"method,omitempty"`
+}
+
+type HTTPPathMatch struct {
+	Type  *string `json:"type,omitempty"`
+	Value *string `json:"value,omitempty"`
+}
+
+type GRPCRoute struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec GRPCRouteSpec `json:"spec"`
+}
+
+type GRPCRouteSpec struct {
+	ParentRefs []ParentReference `json:"parentRefs,omitempty"`
+	Hostnames  []string          `json:"hostnames,omitempty"`
+	Rules      []GRPCRouteRule   `json:"rules,omitempty"`
+}
+
+type GRPCRouteRule struct {
+	Matches     []GRPCRouteMatch `json:"matches,omitempty"`
+	Filters     []RouteFilter    `json:"filters,omitempty"`
+	BackendRefs []BackendRef     `json:"backendRefs,omitempty"`
+}
+
+type GRPCRouteMatch struct {
+	Method  *GRPCMethodMatch `json:"method,omitempty"`
+	Headers []HeaderMatch    `json:"headers,omitempty"`
+}
+
+type GRPCMethodMatch struct {
+	Type    *string `json:"type,omitempty"`
+	Service *string `json:"service,omitempty"`
+	Method  *string `json:"method,omitempty"`
+}
+
+// GatewayGroup is an IngressGroup translated from a Gateway and the routes attached to it.
+// Items contain one synthetic Ingress for the Gateway itself (carrying group-level annotations
+// and certificates) followed by one synthetic Ingress per accepted route.
+type GatewayGroup struct {
+	IngressGroup
+
+	Object  *unstructured.Unstructured
+	Gateway *Gateway
+
+	// Deleting is set when the Gateway is being deleted or is no more managed by this controller
+	Deleting bool
+
+	Error     *GatewayError
+	Listeners []GatewayListenerResult
+	Routes    []GatewayRouteResult
+}
+
+type GatewayListenerResult struct {
+	Name           string
+	AttachedRoutes int32
+	Error          *GatewayError
+}
+
+type GatewayRouteResult struct {
+	Object     *unstructured.Unstructured
+	ParentRefs []ParentReference
+	Error      *GatewayError
+}
+
+type GatewayError struct {
+	Reason  string
+	Message string
+}
+
+func (e *GatewayError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Reason, e.Message)
+}
+
+func gatewayErrorf(reason string, format string, args ...any) *GatewayError {
+	return &GatewayError{Reason: reason, Message: fmt.Sprintf(format, args...)}
+}
+
+// IsResolvedRefsError reports whether the error is about an unresolvable reference
+func (e *GatewayError) IsResolvedRefsError() bool {
+	return e.Reason == GatewayReasonRefNotPermitted || e.Reason == GatewayReasonInvalidKind
+}
+
+// GatewayTag returns the name of the ingress group built from the gateway
+func GatewayTag(gw types.NamespacedName) string {
+	return gatewayTagPrefix + gw.Namespace + "." + gw.Name
+}
+
+// GatewayForTag returns the gateway the ingress group was built from
+func GatewayForTag(tag string) (types.NamespacedName, bool) {
+	if !strings.HasPrefix(tag, gatewayTagPrefix) {
+		return types.NamespacedName{}, false
+	}
+
+	// namespace is a DNS label and has no dots, while the gateway name may have them
+	ns, name, ok := strings.Cut(strings.TrimPrefix(tag, gatewayTagPrefix), ".")
+	if !ok || ns == "" || name == "" {
+		return types.NamespacedName{}, false
+	}
+	return types.NamespacedName{Namespace: ns, Name: name}, true
+}
+
+type GatewayLoader struct {
+	cli client.Client
+}
+
+func NewGatewayLoader(cli client.Client) *GatewayLoader {
+	return &GatewayLoader{cli: cli}
+}
+
+func (l *GatewayLoader) Load(ctx context.Context, nsName types.NamespacedName) (*GatewayGroup, error) {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(GatewayGVK)
+	err := l.cli.Get(ctx, nsName, obj)
+	if errors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get gateway: %w", err)
+	}
+
+	snapshot, err := loadGatewaySnapshot(ctx, l.cli)
+	if err != nil {
+		return nil, err
+	}
+
+	return snapshot.translate(obj)
+}
+
+// ListGatewayIngresses returns synthetic ingresses of all the gateways managed by this controller
+func ListGatewayIngresses(ctx context.Context, cli client.Client) ([]networking.Ingress, error) {
+	snapshot, err := loadGatewaySnapshot(ctx, cli)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []networking.Ingress
+	for i := range snapshot.gateways {
+		g, err := snapshot.translate(&snapshot.gateways[i])
+		if err != nil {
+			return nil, err
+		}
+
+		if g.Deleting {
+			continue
+		}
+		result = append(result, g.Items...)
+	}
+
+	return result, nil
+}
+
+type gatewaySnapshot struct {
+	classes    map[string]GatewayClass
+	gateways   []unstructured.Unstructured
+	routes     []unstructured.Unstructured
+	namespaces map[string]labels.Set
+}
+
+func loadGatewaySnapshot(ctx context.Context, cli client.Client) (*gatewaySnapshot, error) {
+	s := &gatewaySnapshot{
+		classes:    make(map[string]GatewayClass),
+		namespaces: make(map[string]labels.Set),
+	}
+
+	classes, err := listUnstructured(ctx, cli, GatewayClassGVK)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list gateway classes: %w", err)
+	}
+	for _, item := range classes {
+		var class GatewayClass
+		if err = runtime.DefaultUnstructuredConverter.FromUnstructured(item.Object, &class); err != nil {
+			return nil, fmt.Errorf("failed to decode gateway class %s: %w", item.GetName(), err)
+		}
+		s.classes[class.Name] = class
+	}
+
+	s.gateways, err = listUnstructured(ctx, cli, GatewayGVK)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list gateways: %w", err)
+	}
+
+	for _, gvk := range []schema.GroupVersionKind{HTTPRouteGVK, GRPCRouteGVK} {
+		routes, err := listUnstructured(ctx, cli, gvk)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s: %w", gvk.Kind, err)
+		}
+		s.routes = append(s.routes, routes...)
+	}
+
+	var nsList core.NamespaceList
+	if err = cli.List(ctx, &nsList); err != nil {
+		return nil, fmt.Errorf("failed to list namespaces: %w", err)
+	}
+	for _, ns := range nsList.Items {
+		s.namespaces[ns.Name] = ns.Labels
+	}
+
+	return s, nil
+}
+
+func listUnstructured(ctx context.Context, cli client.Client, gvk schema.GroupVersionKind) ([]unstructured.Unstructured, error) {
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+	err := cli.List(ctx, list)
+	if meta.IsNoMatchError(err) {
+		// Gateway API CRDs are not installed
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+func (s *gatewaySnapshot) translate(obj *unstructured.Unstructured) (*GatewayGroup, error) {
+	var gw Gateway
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, &gw); err != nil {
+		return nil, fmt.Errorf("failed to decode gateway %s/%s: %w", obj.GetNamespace(), obj.GetName(), err)
+	}
+
+	g := &GatewayGroup{
+		IngressGroup: IngressGroup{Tag: GatewayTag(NamespacedNameOf(obj))},
+		Object:       obj,
+		Gateway:      &gw,
+	}
+
+	class, ok := s.classes[gw.Spec.GatewayClassName]
+	if !ok || class.Spec.ControllerName != ControllerName || !gw.DeletionTimestamp.IsZero() {
+		g.Deleting = true
+		return g, nil
+	}
+
+	var routes []gatewayRoute
+	for i := range s.routes {
+		route, err := decodeRoute(&s.routes[i])
+		if err != nil {
+			return nil, err
+		}
+		routes = append(routes, route)
+	}
+
+	translateGateway(g, routes, s.namespaces)
+	return g, nil
+}
+
+// gatewayRoute is a common view of HTTPRoute and GRPCRoute
+type gatewayRoute struct {
+	object    *unstructured.Unstructured
+	meta      metav1.ObjectMeta
+	kind      string
+	parents   []ParentReference
+	hostnames []string
+
+	http *HTTPRoute
+	grpc *GRPCRoute
+}
+
+func decodeRoute(obj *unstructured.Unstructured) (gatewayRoute, error) {
+	route := gatewayRoute{object: obj, kind: obj.GetKind()}
+	switch route.kind {
+	case HTTPRouteKind:
+		route.http = &HTTPRoute{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, route.http); err != nil {
+			return route, fmt.Errorf("failed to decode http route %s/%s: %w", obj.GetNamespace(), obj.GetName(), err)
+		}
+		route.meta, route.parents, route.hostnames = route.http.ObjectMeta, route.http.Spec.ParentRefs, route.http.Spec.Hostnames
+	case GRPCRouteKind:
+		route.grpc = &GRPCRoute{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, route.grpc); err != nil {
+			return route, fmt.Errorf("failed to decode grpc route %s/%s: %w", obj.GetNamespace(), obj.GetName(), err)
+		}
+		route.meta, route.parents, route.hostnames = route.grpc.ObjectMeta, route.grpc.Spec.ParentRefs, route.grpc.Spec.Hostnames
+	default:
+		return route, fmt.Errorf("unknown route kind %s", route.kind)
+	}
+	return route, nil
+}
+
+// translateGateway fills the group with synthetic ingresses built from the gateway and the routes
+// attached to it, and records per-listener and per-route results used to report Gateway API statuses.
+func translateGateway(g *GatewayGroup, routes []gatewayRoute, namespaces map[string]labels.Set) {
+	gw := g.Gateway
+
+	gwIng := networking.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        syntheticIngressName(GatewayKind, gw.Name),
+			Namespace:   gw.Namespace,
+			Annotations: albAnnotations(gw.Annotations, g.Tag),
+		},
+	}
+	if err := setGatewayAddresses(&gwIng, gw.Spec.Addresses); err != nil {
+		g.Error = err
+		return
+	}
+
+	listeners := make(map[string]*GatewayListenerResult)
+	for _, l := range gw.Spec.Listeners {
+		g.Listeners = append(g.Listeners, GatewayListenerResult{Name: l.Name, Error: validateListener(gw, l)})
+	}
+	for i := range g.Listeners {
+		listeners[g.Listeners[i].Name] = &g.Listeners[i]
+	}
+
+	sort.SliceStable(routes, func(i, j int) bool {
+		if !routes[i].meta.CreationTimestamp.Equal(&routes[j].meta.CreationTimestamp) {
+			return routes[i].meta.CreationTimestamp.Before(&routes[j].meta.CreationTimestamp)
+		}
+		if routes[i].meta.Namespace != routes[j].meta.Namespace {
+			return routes[i].meta.Namespace < routes[j].meta.Namespace
+		}
+		return routes[i].meta.Name < routes[j].meta.Name
+	})
+
+	// hosts served by https listeners without hostname, they get certificates of the listener
+	tlsHostsByListener := make(map[string][]string)
+	var items []networking.Ingress
+	for _, route := range routes {
+		parents := parentRefsForGateway(route, gw)
+		if len(parents) == 0 {
+			continue
+		}
+
+		result := GatewayRouteResult{Object: route.object, ParentRefs: parents}
+		hosts, tlsHosts, attached, err := attachRoute(gw, route, parents, listeners, namespaces)
+		if err == nil {
+			var ing networking.Ingress
+			ing, err = routeToIngress(route, hosts, tlsHosts, g.Tag)
+			if err == nil {
+				items = append(items, ing)
+				for _, l := range attached {
+					listeners[l.Name].AttachedRoutes++
+					if l.Protocol == "HTTPS" && (l.Hostname == nil || *l.Hostname == "") {
+						tlsHostsByListener[l.Name] = appendUnique(tlsHostsByListener[l.Name], hosts...)
+					}
+				}
+			}
+		}
+		result.Error = err
+		g.Routes = append(g.Routes, result)
+	}
+
+	for _, l := range gw.Spec.Listeners {
+		if l.Protocol != "HTTPS" || listeners[l.Name].Error != nil {
+			continue
+		}
+
+		hosts := tlsHostsByListener[l.Name]
+		if l.Hostname != nil && *l.Hostname != "" {
+			hosts = []string{*l.Hostname}
+		}
+		if len(hosts) == 0 {
+			continue
+		}
+
+		for _, ref := range l.TLS.CertificateRefs {
+			gwIng.Spec.TLS = append(gwIng.Spec.TLS, networking.IngressTLS{Hosts: hosts, SecretName: ref.Name})
+		}
+	}
+
+	g.Items = append([]networking.Ingress{gwIng}, items...)
+}
+
+func albAnnotations(source map[string]string, tag string) map[string]string {
+	result := map[string]string{}
+	for k, v := range source {
+		if strings.HasPrefix(k, prefix+"/") {
+			result[k] = v
+		}
+	}
+	result[AlbTag] = tag
+	return result
+}
+
+func setGatewayAddresses(ing *networking.Ingress, addresses []GatewayAddress) *GatewayError {
+	for _, address := range addresses {
+		if address.Type != nil && *address.Type != "IPAddress" {
+			return gatewayErrorf(GatewayReasonUnsupportedAddress, "address type %s is not supported", *address.Type)
+		}
+
+		key := ExternalIPv4Address
+		if IsIPv6(address.Value) {
+			key = ExternalIPv6Address
+		}
+		if _, ok := ing.Annotations[key]; ok {
+			return gatewayErrorf(GatewayReasonUnsupportedAddress, "only one address of each ip family is supported")
+		}
+		ing.Annotations[key] = address.Value
+	}
+	return nil
+}
+
+func validateListener(gw *Gateway, l GatewayListener) *GatewayError {
+	switch l.Protocol {
+	case "HTTP":
+		if l.Port != 80 {
+			return gatewayErrorf(GatewayReasonPortUnavailable, "HTTP listener %s must use port 80", l.Name)
+		}
+		return nil
+	case "HTTPS":
+		if l.Port != 443 {
+			return gatewayErrorf(GatewayReasonPortUnavailable, "HTTPS listener %s must use port 443", l.Name)
+		}
+	default:
+		return gatewayErrorf(GatewayReasonUnsupportedProtocol, "protocol %s of listener %s is not supported", l.Protocol, l.Name)
+	}
+
+	if l.TLS == nil || len(l.TLS.CertificateRefs) == 0 {
+		return gatewayErrorf(GatewayReasonInvalidCertificateRef, "HTTPS listener %s has no certificate refs", l.Name)
+	}
+	if l.TLS.Mode != nil && *l.TLS.Mode != "Terminate" {
+		return gatewayErrorf(GatewayReasonUnsupportedValue, "tls mode %s of listener %s is not supported", *l.TLS.Mode, l.Name)
+	}
+	for _, ref := range l.TLS.CertificateRefs {
+		if (ref.Group != nil && *ref.Group != "") || (ref.Kind != nil && *ref.Kind != "Secret") {
+			return gatewayErrorf(GatewayReasonInvalidCertificateRef, "certificate ref %s of listener %s must be a Secret", ref.Name, l.Name)
+		}
+		if ref.Namespace != nil && *ref.Namespace != gw.Namespace {
+			return gatewayErrorf(GatewayReasonRefNotPermitted, "certificate ref %s of listener %s must be in the gateway namespace", ref.Name, l.Name)
+		}
+	}
+	return nil
+}
+
+func parentRefsForGateway(route gatewayRoute, gw *Gateway) []ParentReference {
+	var result []ParentReference
+	for _, ref := range route.parents {
+		if ref.Group != nil && *ref.Group != GatewayAPIGroup {
+			continue
+		}
+		if ref.Kind != nil && *ref.Kind != GatewayKind {
+			continue
+		}
+		ns := route.meta.Namespace
+		if ref.Namespace != nil {
+			ns = *ref.Namespace
+		}
+		if ns == gw.Namespace && ref.Name == gw.Name {
+			result = append(result, ref)
+		}
+	}
+	return result
+}
+
+// attachRoute finds listeners the route is attached to and returns hosts of the route and those of them served over TLS
+func attachRoute(
+	gw *Gateway, route gatewayRoute, parents []ParentReference,
+	listeners map[string]*GatewayListenerResult, namespaces map[string]labels.Set,
+) ([]string, []string, []GatewayListener, *GatewayError) {
+	var (
+		hosts, tlsHosts []string
+		attached        []GatewayListener
+		lastErr         = gatewayErrorf(GatewayReasonNoMatchingParent, "no listener of gateway %s/%s matches parent refs", gw.Namespace, gw.Name)
+	)
+
+	for _, l := range gw.Spec.Listeners {
+		if listeners[l.Name].Error != nil || !parentsSelectListener(parents, l) {
+			continue
+		}
+
+		if err := listenerAllowsRoute(gw, l, route, namespaces); err != nil {
+			lastErr = err
+			continue
+		}
+
+		listenerHosts := intersectHostnames(l.Hostname, route.hostnames)
+		if len(listenerHosts) == 0 {
+			lastErr = gatewayErrorf(GatewayReasonNoMatchingListenerHostname, "no hostname of the route matches listener %s", l.Name)
+			continue
+		}
+
+		if l.Protocol == "HTTPS" {
+			for _, h := range listenerHosts {
+				if h == "*" {
+					lastErr = gatewayErrorf(GatewayReasonUnsupportedValue, "route attached to HTTPS listener %s without hostname must specify hostnames", l.Name)
+					listenerHosts = nil
+					break
+				}
+			}
+			if len(listenerHosts) == 0 {
+				continue
+			}
+			tlsHosts = appendUnique(tlsHosts, listenerHosts...)
+		}
+
+		hosts = appendUnique(hosts, listenerHosts...)
+		attached = append(attached, l)
+	}
+
+	if len(attached) == 0 {
+		return nil, nil, nil, lastErr
+	}
+	return hosts, tlsHosts, attached, nil
+}
+
+func parentsSelectListener(parents []ParentReference, l GatewayListener) bool {
+	for _, ref := range parents {
+		if ref.SectionName != nil && *ref.SectionName != l.Name {
+			continue
+		}
+		if ref.Port != nil && *ref.Port != l.Port {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+func listenerAllowsRoute(gw *Gateway, l GatewayListener, route gatewayRoute, namespaces map[string]labels.Set) *GatewayError {
+	from, kinds := "Same", []GatewayRouteGroupKind(nil)
+	var selector *metav1.LabelSelector
+	if l.AllowedRoutes != nil {
+		kinds = l.AllowedRoutes.Kinds
+		if l.AllowedRoutes.Namespaces != nil {
+			if l.AllowedRoutes.Namespaces.From != nil {
+				from = *l.AllowedRoutes.Namespaces.From
+			}
+			selector = l.AllowedRoutes.Namespaces.Selector
+		}
+	}
+
+	kindAllowed := len(kinds) == 0
+	for _, k := range kinds {
+		if (k.Group == nil || *k.Group == GatewayAPIGroup) && k.Kind == route.kind {
+			kindAllowed = true
+		}
+	}
+	if !kindAllowed {
+		return gatewayErrorf(GatewayReasonNotAllowedByListeners, "listener %s does not allow %s", l.Name, route.kind)
+	}
+
+	switch from {
+	case "All":
+		return nil
+	case "Same":
+		if route.meta.Namespace == gw.Namespace {
+			return nil
+		}
+	case "Selector":
+		if selector == nil {
+			break
+		}
+		s, err := metav1.LabelSelectorAsSelector(selector)
+		if err != nil {
+			return gatewayErrorf(GatewayReasonNotAllowedByListeners, "invalid namespace selector of listener %s: %s", l.Name, err)
+		}
+		if s.Matches(namespaces[route.meta.Namespace]) {
+			return nil
+		}
+	}
+	return gatewayErrorf(GatewayReasonNotAllowedByListeners, "listener %s does not allow routes from namespace %s", l.Name, route.meta.Namespace)
+}
+
+func intersectHostnames(listenerHost *string, routeHosts []string) []string {
+	if listenerHost == nil || *listenerHost == "" {
+		if len(routeHosts) == 0 {
+			return []string{"*"}
+		}
+		return routeHosts
+	}
+
+	if len(routeHosts) == 0 {
+		return []string{*listenerHost}
+	}
+
+	var result []string
+	for _, h := range routeHosts {
+		switch {
+		case hostnameMatches(*listenerHost, h):
+			result = appendUnique(result, h)
+		case hostnameMatches(h, *listenerHost):
+			result = appendUnique(result, *listenerHost)
+		}
+	}
+	return result
+}
+
+func hostnameMatches(pattern, host string) bool {
+	if pattern == host {
+		return true
+	}
+	if strings.HasPrefix(pattern, "*.") {
+		suffix := strings.TrimPrefix(pattern, "*")
+		return strings.HasSuffix(host, suffix) && len(host) > len(suffix)
+	}
+	return false
+}
+
+func appendUnique(s []string, values ...string) []string {
+	for _, v := range values {
+		found := false
+		for _, e := range s {
+			if e == v {
+				found = true
+				break
+			}
+		}
+		if !found {
+			s = append(s, v)
+		}
+	}
+	return s
+}
+
+// syntheticIngressName names ingress translated from the Gateway API object by its kind, since a gateway and routes
+// of different kinds may have the same names in one namespace, while data of ingresses of the group is keyed by their names
+func syntheticIngressName(kind, name string) string {
+	return strings.ToLower(kind) + "-" + name
+}
+
+func routeToIngress(route gatewayRoute, hosts, tlsHosts []string, tag string) (networking.Ingress, *GatewayError) {
+	ing := networking.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              syntheticIngressName(route.kind, route.meta.Name),
+			Namespace:         route.meta.Namespace,
+			CreationTimestamp: route.meta.CreationTimestamp,
+			Annotations:       albAnnotations(route.meta.Annotations, tag),
+		},
+	}
+	if len(tlsHosts) > 0 {
+		// certificates are attached by the gateway ingress, here TLS only marks hosts served by HTTPS listeners
+		ing.Spec.TLS = []networking.IngressTLS{{Hosts: tlsHosts}}
+	}
+
+	var (
+		paths []networking.HTTPIngressPath
+		err   *GatewayError
+	)
+	if route.http != nil {
+		paths, err = httpRoutePaths(route.http, ing.Annotations)
+	} else {
+		ing.Annotations[Protocol] = "grpc"
+		paths, err = grpcRoutePaths(route.grpc)
+	}
+	if err != nil {
+		return ing, err
+	}
+
+	for _, host := range hosts {
+		ing.Spec.Rules = append(ing.Spec.Rules, networking.IngressRule{
+			Host: host,
+			IngressRuleValue: networking.IngressRuleValue{
+				HTTP: &networking.HTTPIngressRuleValue{Paths: paths},
+			},
+		})
+	}
+
+	return ing, nil
+}
+
+func httpRoutePaths(route *HTTPRoute, annotations map[string]string) ([]networking.HTTPIngressPath, *GatewayError) {
+	var (
+		paths                []networking.HTTPIngressPath
+		hasRegex, hasNoRegex bool
+	)
+
+	for _, rule := range route.Spec.Rules {
+		backend, err := ruleBackend(route.Namespace, rule.Filters, rule.BackendRefs)
+		if err != nil {
+			return nil, err
+		}
+
+		matches := rule.Matches
+		if len(matches) == 0 {
+			matches = []HTTPRouteMatch{{}}
+		}
+
+		for _, m := range matches {
+			if len(m.Headers) > 0 || len(m.QueryParams) > 0 || m.Method != nil {
+				return nil, gatewayErrorf(GatewayReasonUnsupportedValue, "only path matches are supported")
+			}
+
+			pathType, value := "PathPrefix", "/"
+			if m.Path != nil && m.Path.Type != nil {
+				pathType = *m.Path.Type
+			}
+			if m.Path != nil && m.Path.Value != nil {
+				value = *m.Path.Value
+			}
+
+			var ingPathType networking.PathType
+			switch pathType {
+			case "PathPrefix":
+				ingPathType, hasNoRegex = networking.PathTypePrefix, true
+			case "Exact":
+				ingPathType, hasNoRegex = networking.PathTypeExact, true
+			case "RegularExpression":
+				ingPathType, hasRegex = networking.PathTypeExact, true
+			default:
+				return nil, gatewayErrorf(GatewayReasonUnsupportedValue, "path match type %s is not supported", pathType)
+			}
+
+			paths = append(paths, networking.HTTPIngressPath{
+				Path:     value,
+				PathType: &ingPathType,
+				Backend:  backend,
+			})
+		}
+	}
+
+	if hasRegex && hasNoRegex {
+		return nil, gatewayErrorf(GatewayReasonUnsupportedValue, "RegularExpression path matches can't be combined with other path match types in one route")
+	}
+	if hasRegex {
+		annotations[UseRegex] = "true"
+	}
+
+	return paths, nil
+}
+
+func grpcRoutePaths(route *GRPCRoute) ([]networking.HTTPIngressPath, *GatewayError) {
+	var paths []networking.HTTPIngressPath
+
+	for _, rule := range route.Spec.Rules {
+		backend, err := ruleBackend(route.Namespace, rule.Filters, rule.BackendRefs)
+		if err != nil {
+			return nil, err
+		}
+
+		matches := rule.Matches
+		if len(matches) == 0 {
+			matches = []GRPCRouteMatch{{}}
+		}
+
+		for _, m := range matches {
+			if len(m.Headers) > 0 {
+				return nil, gatewayErrorf(GatewayReasonUnsupportedValue, "header matches are not supported")
+			}
+
+			pathType, value := networking.PathTypePrefix, "/"
+			if m.Method != nil {
+				if m.Method.Type != nil && *m.Method.Type != "Exact" {
+					return nil, gatewayErrorf(GatewayReasonUnsupportedValue, "method match type %s is not supported", *m.Method.Type)
+				}
+
+				service, method := "", ""
+				if m.Method.Service != nil {
+					service = *m.Method.Service
+				}
+				if m.Method.Method != nil {
+					method = *m.Method.Method
+				}
+
+				switch {
+				case service != "" && method != "":
+					pathType, value = networking.PathTypeExact, "/"+service+"/"+method
+				case service != "":
+					value = "/" + service + "/"
+				case method != "":
+					return nil, gatewayErrorf(GatewayReasonUnsupportedValue, "method match without service is not supported")
+				}
+			}
+
+			pt := pathType
+			paths = append(paths, networking.HTTPIngressPath{
+				Path:     value,
+				PathType: &pt,
+				Backend:  backend,
+			})
+		}
+	}
+
+	return paths, nil
+}
+
+func ruleBackend(ns string, filters []RouteFilter, refs []BackendRef) (networking.IngressBackend, *GatewayError) {
+	if len(filters) > 0 {
+		return networking.IngressBackend{}, gatewayErrorf(GatewayReasonUnsupportedValue, "filters are not supported")
+	}
+	if len(refs) != 1 {
+		return networking.IngressBackend{}, gatewayErrorf(GatewayReasonUnsupportedValue,
+			"exactly one backend ref per rule is supported, use HttpBackendGroup or GrpcBackendGroup for traffic splitting")
+	}
+
+	ref := refs[0]
+	if ref.Namespace != nil && *ref.Namespace != ns {
+		return networking.IngressBackend{}, gatewayErrorf(GatewayReasonRefNotPermitted, "backend %s is in another namespace", ref.Name)
+	}
+
+	group, kind := "", "Service"
+	if ref.Group != nil {
+		group = *ref.Group
+	}
+	if ref.Kind != nil {
+		kind = *ref.Kind
+	}
+
+	switch {
+	case group == "" && kind == "Service":
+		if ref.Port == nil {
+			return networking.IngressBackend{}, gatewayErrorf(GatewayReasonUnsupportedValue, "port of service %s is required", ref.Name)
+		}
+		return networking.IngressBackend{
+			Service: &networking.IngressServiceBackend{
+				Name: ref.Name,
+				Port: networking.ServiceBackendPort{Number: *ref.Port},
+			},
+		}, nil
+	case group == albGroup && (kind == "HttpBackendGroup" || kind == "GrpcBackendGroup"):
+		apiGroup := albGroup
+		return networking.IngressBackend{
+			Resource: &core.TypedLocalObjectReference{
+				APIGroup: &apiGroup,
+				Kind:     kind,
+				Name:     ref.Name,
+			},
+		}, nil
+	}
+
+	return networking.IngressBackend{}, gatewayErrorf(GatewayReasonInvalidKind, "backend kind %s/%s is not supported", group, kind)
+}
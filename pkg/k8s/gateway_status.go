@@ -0,0 +1,264 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+
+	networking "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	GatewayConditionAccepted     = "Accepted"
+	GatewayConditionProgrammed   = "Programmed"
+	GatewayConditionResolvedRefs = "ResolvedRefs"
+)
+
+type gatewayStatus struct {
+	Addresses  []GatewayAddress        `json:"addresses,omitempty"`
+	Conditions []metav1.Condition      `json:"conditions,omitempty"`
+	Listeners  []gatewayListenerStatus `json:"listeners,omitempty"`
+}
+
+type gatewayListenerStatus struct {
+	Name           string                  `json:"name"`
+	SupportedKinds []GatewayRouteGroupKind `json:"supportedKinds"`
+	AttachedRoutes int32                   `json:"attachedRoutes"`
+	Conditions     []metav1.Condition      `json:"conditions"`
+}
+
+type routeStatus struct {
+	Parents []routeParentStatus `json:"parents"`
+}
+
+type routeParentStatus struct {
+	ParentRef      ParentReference    `json:"parentRef"`
+	ControllerName string             `json:"controllerName"`
+	Conditions     []metav1.Condition `json:"conditions,omitempty"`
+}
+
+type gatewayClassStatus struct {
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// GatewayStatusUpdater reports results of the gateway reconciliation into statuses of Gateway API objects
+type GatewayStatusUpdater struct {
+	Client client.Client
+}
+
+// SetClassAccepted marks the gateway class as accepted by this controller
+func (u *GatewayStatusUpdater) SetClassAccepted(ctx context.Context, name string) error {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(GatewayClassGVK)
+	if err := u.Client.Get(ctx, client.ObjectKey{Name: name}, obj); err != nil {
+		return fmt.Errorf("failed to get gateway class: %w", err)
+	}
+
+	var status gatewayClassStatus
+	if err := decodeStatus(obj, &status); err != nil {
+		return err
+	}
+
+	meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+		Type:               GatewayConditionAccepted,
+		Status:             metav1.ConditionTrue,
+		Reason:             GatewayReasonAccepted,
+		ObservedGeneration: obj.GetGeneration(),
+	})
+
+	return u.patchStatus(ctx, obj, status)
+}
+
+// SetGatewayStatus updates statuses of the gateway and routes attached to it.
+// lbStatus is nil when the balancer is not deployed (yet), then the addresses reported before are kept,
+// reconcileErr is the error of building or deploying the balancer, if any.
+func (u *GatewayStatusUpdater) SetGatewayStatus(ctx context.Context, g *GatewayGroup, lbStatus *networking.IngressStatus, reconcileErr error) error {
+	if err := u.setGatewayStatus(ctx, g, lbStatus, reconcileErr); err != nil {
+		return fmt.Errorf("failed to set gateway status: %w", err)
+	}
+
+	for _, route := range g.Routes {
+		if err := u.setRouteStatus(ctx, g, route); err != nil {
+			return fmt.Errorf("failed to set route %s/%s status: %w", route.Object.GetNamespace(), route.Object.GetName(), err)
+		}
+	}
+
+	return nil
+}
+
+func (u *GatewayStatusUpdater) setGatewayStatus(ctx context.Context, g *GatewayGroup, lbStatus *networking.IngressStatus, reconcileErr error) error {
+	obj := g.Object
+	generation := obj.GetGeneration()
+
+	var status gatewayStatus
+	if err := decodeStatus(obj, &status); err != nil {
+		return err
+	}
+
+	if lbStatus != nil {
+		status.Addresses = nil
+		for _, ing := range lbStatus.LoadBalancer.Ingress {
+			addrType := "IPAddress"
+			status.Addresses = append(status.Addresses, GatewayAddress{Type: &addrType, Value: ing.IP})
+		}
+	}
+
+	accepted := metav1.Condition{
+		Type:               GatewayConditionAccepted,
+		Status:             metav1.ConditionTrue,
+		Reason:             GatewayReasonAccepted,
+		ObservedGeneration: generation,
+	}
+	if g.Error != nil {
+		accepted.Status, accepted.Reason, accepted.Message = metav1.ConditionFalse, g.Error.Reason, g.Error.Message
+	}
+	meta.SetStatusCondition(&status.Conditions, accepted)
+
+	programmed := metav1.Condition{
+		Type:               GatewayConditionProgrammed,
+		Status:             metav1.ConditionTrue,
+		Reason:             GatewayReasonProgrammed,
+		ObservedGeneration: generation,
+	}
+	switch {
+	case g.Error != nil:
+		programmed.Status, programmed.Reason, programmed.Message = metav1.ConditionFalse, GatewayReasonInvalid, g.Error.Message
+	case reconcileErr != nil:
+		programmed.Status, programmed.Reason, programmed.Message = metav1.ConditionFalse, GatewayReasonPending, reconcileErr.Error()
+	case len(status.Addresses) == 0:
+		programmed.Status, programmed.Reason, programmed.Message = metav1.ConditionFalse, GatewayReasonPending, "balancer has no addresses yet"
+	}
+	meta.SetStatusCondition(&status.Conditions, programmed)
+
+	old := make(map[string]gatewayListenerStatus)
+	for _, l := range status.Listeners {
+		old[l.Name] = l
+	}
+
+	status.Listeners = nil
+	group := GatewayAPIGroup
+	for _, l := range g.Listeners {
+		ls := gatewayListenerStatus{
+			Name:           l.Name,
+			SupportedKinds: []GatewayRouteGroupKind{{Group: &group, Kind: HTTPRouteKind}, {Group: &group, Kind: GRPCRouteKind}},
+			AttachedRoutes: l.AttachedRoutes,
+			Conditions:     old[l.Name].Conditions,
+		}
+
+		cAccepted := metav1.Condition{Type: GatewayConditionAccepted, Status: metav1.ConditionTrue, Reason: GatewayReasonAccepted, ObservedGeneration: generation}
+		cResolved := metav1.Condition{Type: GatewayConditionResolvedRefs, Status: metav1.ConditionTrue, Reason: GatewayReasonResolvedRefs, ObservedGeneration: generation}
+		cProgrammed := programmed
+		if l.Error != nil {
+			if l.Error.IsResolvedRefsError() || l.Error.Reason == GatewayReasonInvalidCertificateRef {
+				cResolved.Status, cResolved.Reason, cResolved.Message = metav1.ConditionFalse, l.Error.Reason, l.Error.Message
+			} else {
+				cAccepted.Status, cAccepted.Reason, cAccepted.Message = metav1.ConditionFalse, l.Error.Reason, l.Error.Message
+			}
+			cProgrammed.Status, cProgrammed.Reason, cProgrammed.Message = metav1.ConditionFalse, GatewayReasonInvalid, l.Error.Message
+		}
+		meta.SetStatusCondition(&ls.Conditions, cAccepted)
+		meta.SetStatusCondition(&ls.Conditions, cResolved)
+		meta.SetStatusCondition(&ls.Conditions, cProgrammed)
+
+		status.Listeners = append(status.Listeners, ls)
+	}
+
+	return u.patchStatus(ctx, obj, status)
+}
+
+func (u *GatewayStatusUpdater) setRouteStatus(ctx context.Context, g *GatewayGroup, route GatewayRouteResult) error {
+	obj := route.Object
+	generation := obj.GetGeneration()
+
+	var status routeStatus
+	if err := decodeStatus(obj, &status); err != nil {
+		return err
+	}
+
+	parents := make([]routeParentStatus, 0, len(status.Parents))
+	old := make(map[string]routeParentStatus)
+	for _, p := range status.Parents {
+		// statuses of this gateway are rebuilt below, the ones of other gateways and controllers are kept
+		if p.ControllerName == ControllerName && isParentRefOf(p.ParentRef, obj.GetNamespace(), g.Gateway) {
+			old[parentRefKey(p.ParentRef)] = p
+			continue
+		}
+		parents = append(parents, p)
+	}
+
+	for _, ref := range route.ParentRefs {
+		ps := routeParentStatus{
+			ParentRef:      ref,
+			ControllerName: ControllerName,
+			Conditions:     old[parentRefKey(ref)].Conditions,
+		}
+
+		cAccepted := metav1.Condition{Type: GatewayConditionAccepted, Status: metav1.ConditionTrue, Reason: GatewayReasonAccepted, ObservedGeneration: generation}
+		cResolved := metav1.Condition{Type: GatewayConditionResolvedRefs, Status: metav1.ConditionTrue, Reason: GatewayReasonResolvedRefs, ObservedGeneration: generation}
+		if route.Error != nil {
+			if route.Error.IsResolvedRefsError() {
+				cResolved.Status, cResolved.Reason, cResolved.Message = metav1.ConditionFalse, route.Error.Reason, route.Error.Message
+			}
+			reason := route.Error.Reason
+			if route.Error.IsResolvedRefsError() {
+				reason = GatewayReasonUnsupportedValue
+			}
+			cAccepted.Status, cAccepted.Reason, cAccepted.Message = metav1.ConditionFalse, reason, route.Error.Message
+		}
+		meta.SetStatusCondition(&ps.Conditions, cAccepted)
+		meta.SetStatusCondition(&ps.Conditions, cResolved)
+
+		parents = append(parents, ps)
+	}
+	status.Parents = parents
+
+	return u.patchStatus(ctx, obj, status)
+}
+
+func isParentRefOf(ref ParentReference, routeNs string, gw *Gateway) bool {
+	ns := routeNs
+	if ref.Namespace != nil {
+		ns = *ref.Namespace
+	}
+	return ns == gw.Namespace && ref.Name == gw.Name
+}
+
+func parentRefKey(ref ParentReference) string {
+	key := ref.Name
+	if ref.Namespace != nil {
+		key = *ref.Namespace + "/" + key
+	}
+	if ref.SectionName != nil {
+		key += "#" + *ref.SectionName
+	}
+	if ref.Port != nil {
+		key += fmt.Sprintf(":%d", *ref.Port)
+	}
+	return key
+}
+
+func decodeStatus(obj *unstructured.Unstructured, status any) error {
+	raw, ok := obj.Object["status"].(map[string]any)
+	if !ok {
+		return nil
+	}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(raw, status); err != nil {
+		return fmt.Errorf("failed to decode status of %s %s/%s: %w", obj.GetKind(), obj.GetNamespace(), obj.GetName(), err)
+	}
+	return nil
+}
+
+func (u *GatewayStatusUpdater) patchStatus(ctx context.Context, obj *unstructured.Unstructured, status any) error {
+	raw, err := runtime.DefaultUnstructuredConverter.ToUnstructured(status)
+	if err != nil {
+		return fmt.Errorf("failed to encode status: %w", err)
+	}
+
+	old := obj.DeepCopy()
+	obj.Object["status"] = raw
+	return u.Client.Status().Patch(ctx, obj, client.MergeFrom(old))
+}
@@ -0,0 +1,341 @@
+package k8s
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	core "k8s.io/api/core/v1"
+	networking "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func toRoute(t *testing.T, kind string, obj any) gatewayRoute {
+	raw, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	require.NoError(t, err)
+	u := &unstructured.Unstructured{Object: raw}
+	u.SetKind(kind)
+
+	route, err := decodeRoute(u)
+	require.NoError(t, err)
+	return route
+}
+
+func TestTranslateGateway(t *testing.T) {
+	gw := Gateway{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "gw",
+			Namespace: "infra",
+			Annotations: map[string]string{
+				Subnets:         "subnet-a,subnet-b",
+				"other.io/some": "value",
+			},
+		},
+		Spec: GatewaySpec{
+			GatewayClassName: "alb",
+			Listeners: []GatewayListener{
+				{Name: "http", Port: 80, Protocol: "HTTP", AllowedRoutes: &GatewayAllowedRoutes{
+					Namespaces: &GatewayRouteNamespaces{From: ptr("All")},
+				}},
+				{Name: "https", Port: 443, Protocol: "HTTPS", Hostname: ptr("*.example.com"),
+					TLS: &GatewayTLSConfig{CertificateRefs: []GatewayObjectReference{{Name: "wildcard"}}},
+					AllowedRoutes: &GatewayAllowedRoutes{
+						Namespaces: &GatewayRouteNamespaces{From: ptr("Selector"), Selector: &metav1.LabelSelector{
+							MatchLabels: map[string]string{"team": "web"},
+						}},
+					},
+				},
+				{Name: "tcp", Port: 5432, Protocol: "TCP"},
+			},
+			Addresses: []GatewayAddress{{Value: "1.2.3.4"}},
+		},
+	}
+
+	httpRoute := HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "web", CreationTimestamp: metav1.Unix(100, 0)},
+		Spec: HTTPRouteSpec{
+			ParentRefs: []ParentReference{{Name: "gw", Namespace: ptr("infra")}},
+			Hostnames:  []string{"a.example.com"},
+			Rules: []HTTPRouteRule{
+				{
+					Matches: []HTTPRouteMatch{
+						{Path: &HTTPPathMatch{Type: ptr("Exact"), Value: ptr("/api")}},
+						{Path: &HTTPPathMatch{Value: ptr("/static")}},
+					},
+					BackendRefs: []BackendRef{{Name: "api", Port: ptr(int32(8080))}},
+				},
+				{
+					BackendRefs: []BackendRef{{Name: "split", Group: ptr("alb.yc.io"), Kind: ptr("HttpBackendGroup")}},
+				},
+			},
+		},
+	}
+
+	grpcRoute := GRPCRoute{
+		ObjectMeta: metav1.ObjectMeta{Name: "grpc", Namespace: "rpc", CreationTimestamp: metav1.Unix(200, 0)},
+		Spec: GRPCRouteSpec{
+			ParentRefs: []ParentReference{{Name: "gw", Namespace: ptr("infra"), SectionName: ptr("http")}},
+			Hostnames:  []string{"rpc.internal"},
+			Rules: []GRPCRouteRule{
+				{
+					Matches: []GRPCRouteMatch{
+						{Method: &GRPCMethodMatch{Service: ptr("echo.Echo"), Method: ptr("Say")}},
+						{Method: &GRPCMethodMatch{Service: ptr("health.Health")}},
+					},
+					BackendRefs: []BackendRef{{Name: "echo", Port: ptr(int32(9090))}},
+				},
+			},
+		},
+	}
+
+	splitRoute := HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{Name: "split", Namespace: "infra", CreationTimestamp: metav1.Unix(300, 0)},
+		Spec: HTTPRouteSpec{
+			ParentRefs: []ParentReference{{Name: "gw"}},
+			Rules: []HTTPRouteRule{
+				{BackendRefs: []BackendRef{{Name: "a", Port: ptr(int32(80))}, {Name: "b", Port: ptr(int32(80))}}},
+			},
+		},
+	}
+
+	foreignRoute := HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{Name: "foreign", Namespace: "infra"},
+		Spec: HTTPRouteSpec{
+			ParentRefs: []ParentReference{{Name: "other-gw"}},
+		},
+	}
+
+	g := &GatewayGroup{IngressGroup: IngressGroup{Tag: GatewayTag(types.NamespacedName{Namespace: "infra", Name: "gw"})}, Gateway: &gw}
+	routes := []gatewayRoute{
+		toRoute(t, GRPCRouteKind, &grpcRoute),
+		toRoute(t, HTTPRouteKind, &splitRoute),
+		toRoute(t, HTTPRouteKind, &httpRoute),
+		toRoute(t, HTTPRouteKind, &foreignRoute),
+	}
+	namespaces := map[string]labels.Set{"web": {"team": "web"}, "rpc": {}, "infra": {}}
+
+	translateGateway(g, routes, namespaces)
+
+	require.Nil(t, g.Error)
+	assert.Equal(t, "gateway.infra.gw", g.Tag)
+
+	require.Len(t, g.Listeners, 3)
+	assert.Nil(t, g.Listeners[0].Error)
+	assert.Equal(t, int32(2), g.Listeners[0].AttachedRoutes)
+	assert.Nil(t, g.Listeners[1].Error)
+	assert.Equal(t, int32(1), g.Listeners[1].AttachedRoutes)
+	require.NotNil(t, g.Listeners[2].Error)
+	assert.Equal(t, GatewayReasonUnsupportedProtocol, g.Listeners[2].Error.Reason)
+
+	require.Len(t, g.Routes, 3)
+	assert.Equal(t, "web", g.Routes[0].Object.GetName())
+	assert.Nil(t, g.Routes[0].Error)
+	assert.Equal(t, "grpc", g.Routes[1].Object.GetName())
+	assert.Nil(t, g.Routes[1].Error)
+	assert.Equal(t, "split", g.Routes[2].Object.GetName())
+	require.NotNil(t, g.Routes[2].Error)
+	assert.Equal(t, GatewayReasonUnsupportedValue, g.Routes[2].Error.Reason)
+
+	prefix, exact := networking.PathTypePrefix, networking.PathTypeExact
+	exp := []networking.Ingress{
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "gateway-gw",
+				Namespace: "infra",
+				Annotations: map[string]string{
+					AlbTag:              "gateway.infra.gw",
+					Subnets:             "subnet-a,subnet-b",
+					ExternalIPv4Address: "1.2.3.4",
+				},
+			},
+			Spec: networking.IngressSpec{
+				TLS: []networking.IngressTLS{{Hosts: []string{"*.example.com"}, SecretName: "wildcard"}},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "httproute-web",
+				Namespace:         "web",
+				CreationTimestamp: metav1.Unix(100, 0),
+				Annotations:       map[string]string{AlbTag: "gateway.infra.gw"},
+			},
+			Spec: networking.IngressSpec{
+				TLS: []networking.IngressTLS{{Hosts: []string{"a.example.com"}}},
+				Rules: []networking.IngressRule{
+					{
+						Host: "a.example.com",
+						IngressRuleValue: networking.IngressRuleValue{HTTP: &networking.HTTPIngressRuleValue{
+							Paths: []networking.HTTPIngressPath{
+								{Path: "/api", PathType: &exact, Backend: networking.IngressBackend{
+									Service: &networking.IngressServiceBackend{Name: "api", Port: networking.ServiceBackendPort{Number: 8080}},
+								}},
+								{Path: "/static", PathType: &prefix, Backend: networking.IngressBackend{
+									Service: &networking.IngressServiceBackend{Name: "api", Port: networking.ServiceBackendPort{Number: 8080}},
+								}},
+								{Path: "/", PathType: &prefix, Backend: networking.IngressBackend{
+									Resource: &core.TypedLocalObjectReference{APIGroup: ptr("alb.yc.io"), Kind: "HttpBackendGroup", Name: "split"},
+								}},
+							},
+						}},
+					},
+				},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "grpcroute-grpc",
+				Namespace:         "rpc",
+				CreationTimestamp: metav1.Unix(200, 0),
+				Annotations:       map[string]string{AlbTag: "gateway.infra.gw", Protocol: "grpc"},
+			},
+			Spec: networking.IngressSpec{
+				Rules: []networking.IngressRule{
+					{
+						Host: "rpc.internal",
+						IngressRuleValue: networking.IngressRuleValue{HTTP: &networking.HTTPIngressRuleValue{
+							Paths: []networking.HTTPIngressPath{
+								{Path: "/echo.Echo/Say", PathType: &exact, Backend: networking.IngressBackend{
+									Service: &networking.IngressServiceBackend{Name: "echo", Port: networking.ServiceBackendPort{Number: 9090}},
+								}},
+								{Path: "/health.Health/", PathType: &prefix, Backend: networking.IngressBackend{
+									Service: &networking.IngressServiceBackend{Name: "echo", Port: networking.ServiceBackendPort{Number: 9090}},
+								}},
+							},
+						}},
+					},
+				},
+			},
+		},
+	}
+	assert.Equal(t, exp, g.Items)
+}
+
+func TestTranslateGateway_NotAllowed(t *testing.T) {
+	gw := Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "gw", Namespace: "infra"},
+		Spec: GatewaySpec{
+			Listeners: []GatewayListener{
+				{Name: "http", Port: 80, Protocol: "HTTP", Hostname: ptr("*.example.com")},
+				{Name: "https", Port: 443, Protocol: "HTTPS", TLS: &GatewayTLSConfig{
+					CertificateRefs: []GatewayObjectReference{{Name: "cert", Namespace: ptr("other")}},
+				}},
+			},
+		},
+	}
+
+	testData := []struct {
+		desc   string
+		route  HTTPRoute
+		reason string
+	}{
+		{
+			desc: "other namespace",
+			route: HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{Name: "r", Namespace: "web"},
+				Spec:       HTTPRouteSpec{ParentRefs: []ParentReference{{Name: "gw", Namespace: ptr("infra")}}},
+			},
+			reason: GatewayReasonNotAllowedByListeners,
+		},
+		{
+			desc: "hostname mismatch",
+			route: HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{Name: "r", Namespace: "infra"},
+				Spec: HTTPRouteSpec{
+					ParentRefs: []ParentReference{{Name: "gw"}},
+					Hostnames:  []string{"example.org"},
+				},
+			},
+			reason: GatewayReasonNoMatchingListenerHostname,
+		},
+		{
+			desc: "invalid listener",
+			route: HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{Name: "r", Namespace: "infra"},
+				Spec:       HTTPRouteSpec{ParentRefs: []ParentReference{{Name: "gw", SectionName: ptr("https")}}},
+			},
+			reason: GatewayReasonNoMatchingParent,
+		},
+		{
+			desc: "cross namespace backend",
+			route: HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{Name: "r", Namespace: "infra"},
+				Spec: HTTPRouteSpec{
+					ParentRefs: []ParentReference{{Name: "gw"}},
+					Rules: []HTTPRouteRule{
+						{BackendRefs: []BackendRef{{Name: "svc", Namespace: ptr("web"), Port: ptr(int32(80))}}},
+					},
+				},
+			},
+			reason: GatewayReasonRefNotPermitted,
+		},
+	}
+
+	for _, entry := range testData {
+		t.Run(entry.desc, func(t *testing.T) {
+			g := &GatewayGroup{IngressGroup: IngressGroup{Tag: "gateway.infra.gw"}, Gateway: &gw}
+			translateGateway(g, []gatewayRoute{toRoute(t, HTTPRouteKind, &entry.route)}, nil)
+
+			require.NotNil(t, g.Listeners[1].Error)
+			assert.Equal(t, GatewayReasonRefNotPermitted, g.Listeners[1].Error.Reason)
+
+			require.Len(t, g.Routes, 1)
+			require.NotNil(t, g.Routes[0].Error)
+			assert.Equal(t, entry.reason, g.Routes[0].Error.Reason)
+			assert.Len(t, g.Items, 1)
+		})
+	}
+}
+
+func TestTranslateGateway_SameNames(t *testing.T) {
+	gw := Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "app"},
+		Spec:       GatewaySpec{Listeners: []GatewayListener{{Name: "http", Port: 80, Protocol: "HTTP"}}},
+	}
+	httpRoute := HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "app"},
+		Spec: HTTPRouteSpec{
+			ParentRefs: []ParentReference{{Name: "app"}},
+			Hostnames:  []string{"web.example.com"},
+			Rules:      []HTTPRouteRule{{BackendRefs: []BackendRef{{Name: "web", Port: ptr(int32(80))}}}},
+		},
+	}
+	grpcRoute := GRPCRoute{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "app"},
+		Spec: GRPCRouteSpec{
+			ParentRefs: []ParentReference{{Name: "app"}},
+			Hostnames:  []string{"rpc.example.com"},
+			Rules:      []GRPCRouteRule{{BackendRefs: []BackendRef{{Name: "rpc", Port: ptr(int32(9090))}}}},
+		},
+	}
+
+	g := &GatewayGroup{IngressGroup: IngressGroup{Tag: "gateway.app.app"}, Gateway: &gw}
+	translateGateway(g, []gatewayRoute{toRoute(t, HTTPRouteKind, &httpRoute), toRoute(t, GRPCRouteKind, &grpcRoute)}, nil)
+
+	require.Nil(t, g.Error)
+	require.Len(t, g.Routes, 2)
+	assert.Nil(t, g.Routes[0].Error)
+	assert.Nil(t, g.Routes[1].Error)
+
+	var names []string
+	for _, ing := range g.Items {
+		names = append(names, NamespacedNameOf(&ing).String())
+	}
+	assert.Equal(t, []string{"app/gateway-app", "app/httproute-app", "app/grpcroute-app"}, names)
+}
+
+func TestGatewayForTag(t *testing.T) {
+	gw, ok := GatewayForTag(GatewayTag(types.NamespacedName{Namespace: "ns", Name: "gw.with.dots"}))
+	assert.True(t, ok)
+	assert.Equal(t, types.NamespacedName{Namespace: "ns", Name: "gw.with.dots"}, gw)
+
+	_, ok = GatewayForTag("default")
+	assert.False(t, ok)
+}
@@ -0,0 +1,117 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+
+	networking "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// IngressServiceIndex indexes ingresses by names of services used by their backends
+	IngressServiceIndex = "ingressService"
+	// PrimaryIngressGroupIndex indexes non-canary ingresses by tag of their group
+	PrimaryIngressGroupIndex = "primaryIngressGroup"
+	// CanaryIngressGroupIndex indexes canary ingresses by tag of their group
+	CanaryIngressGroupIndex = "canaryIngressGroup"
+	// RouteServiceIndex indexes Gateway API routes by names of services used by their backend refs
+	RouteServiceIndex = "routeService"
+)
+
+// IndexIngresses registers field indexes of ingresses used to find primaries and canaries of the group
+// without listing all the ingresses
+func IndexIngresses(ctx context.Context, indexer client.FieldIndexer) error {
+	for field, extract := range map[string]client.IndexerFunc{
+		IngressServiceIndex:      ingressServiceNames,
+		PrimaryIngressGroupIndex: ingressGroupOf(false),
+		CanaryIngressGroupIndex:  ingressGroupOf(true),
+	} {
+		if err := indexer.IndexField(ctx, &networking.Ingress{}, field, extract); err != nil {
+			return fmt.Errorf("failed to index ingresses by %s: %w", field, err)
+		}
+	}
+	return nil
+}
+
+// IndexRoutes registers field index of HTTPRoutes and GRPCRoutes by services of their backends
+func IndexRoutes(ctx context.Context, indexer client.FieldIndexer) error {
+	for _, gvk := range []schema.GroupVersionKind{HTTPRouteGVK, GRPCRouteGVK} {
+		route := &unstructured.Unstructured{}
+		route.SetGroupVersionKind(gvk)
+		if err := indexer.IndexField(ctx, route, RouteServiceIndex, routeServiceNames); err != nil {
+			return fmt.Errorf("failed to index %s by services: %w", gvk.Kind, err)
+		}
+	}
+	return nil
+}
+
+func ingressServiceNames(obj client.Object) []string {
+	ing := obj.(*networking.Ingress)
+
+	var ret []string
+	if ing.Spec.DefaultBackend != nil && ing.Spec.DefaultBackend.Service != nil {
+		ret = append(ret, ing.Spec.DefaultBackend.Service.Name)
+	}
+	for _, rule := range ing.Spec.Rules {
+		if rule.HTTP == nil {
+			continue
+		}
+		for _, path := range rule.HTTP.Paths {
+			if path.Backend.Service != nil {
+				ret = append(ret, path.Backend.Service.Name)
+			}
+		}
+	}
+	return ret
+}
+
+// routeServiceNames returns names of services in the namespace of the route used by its backend refs,
+// refs to other namespaces are refused on translation of the route
+func routeServiceNames(obj client.Object) []string {
+	route, err := decodeRoute(obj.(*unstructured.Unstructured))
+	if err != nil {
+		return nil
+	}
+
+	var refs []BackendRef
+	if route.http != nil {
+		for _, rule := range route.http.Spec.Rules {
+			refs = append(refs, rule.BackendRefs...)
+		}
+	}
+	if route.grpc != nil {
+		for _, rule := range route.grpc.Spec.Rules {
+			refs = append(refs, rule.BackendRefs...)
+		}
+	}
+
+	var ret []string
+	for _, ref := range refs {
+		if (ref.Group != nil && *ref.Group != "") || (ref.Kind != nil && *ref.Kind != "Service") {
+			continue
+		}
+		if ref.Namespace != nil && *ref.Namespace != obj.GetNamespace() {
+			continue
+		}
+		ret = append(ret, ref.Name)
+	}
+	return ret
+}
+
+func ingressGroupOf(canary bool) client.IndexerFunc {
+	return func(obj client.Object) []string {
+		ing := obj.(*networking.Ingress)
+		if IsCanary(ing) != canary {
+			return nil
+		}
+		return []string{GetBalancerTag(ing)}
+	}
+}
+
+// IsCanary tells if the ingress is a canary of the ingress with the same host and path in the group
+func IsCanary(ing *networking.Ingress) bool {
+	return ing.GetAnnotations()[Canary] == "true"
+}
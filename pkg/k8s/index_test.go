@@ -0,0 +1,29 @@
+package k8s
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestRouteServiceNames(t *testing.T) {
+	route := &HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "web", Name: "route"},
+		Spec: HTTPRouteSpec{Rules: []HTTPRouteRule{
+			{BackendRefs: []BackendRef{{Name: "svc-a", Port: ptr[int32](80)}}},
+			{BackendRefs: []BackendRef{{Name: "svc-b", Kind: ptr("Service"), Namespace: ptr("web")}}},
+			{BackendRefs: []BackendRef{{Name: "other-ns", Namespace: ptr("infra")}}},
+			{BackendRefs: []BackendRef{{Name: "bg", Group: ptr(albGroup), Kind: ptr("HttpBackendGroup")}}},
+		}},
+	}
+	raw, err := runtime.DefaultUnstructuredConverter.ToUnstructured(route)
+	require.NoError(t, err)
+	u := &unstructured.Unstructured{Object: raw}
+	u.SetGroupVersionKind(HTTPRouteGVK)
+
+	assert.Equal(t, []string{"svc-a", "svc-b"}, routeServiceNames(u))
+}
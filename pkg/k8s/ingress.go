@@ -16,13 +16,19 @@ type IngressLoader interface {
 }
 
 type ingressLoader struct {
-	cli client.Client
+	cli          client.Client
+	withGateways bool
 }
 
 func NewIngressLoader(cli client.Client) IngressLoader {
 	return &ingressLoader{cli: cli}
 }
 
+// NewIngressLoaderWithGateways returns loader which also lists ingresses translated from Gateway API objects
+func NewIngressLoaderWithGateways(cli client.Client) IngressLoader {
+	return &ingressLoader{cli: cli, withGateways: true}
+}
+
 func (l *ingressLoader) List(ctx context.Context, opts ...client.ListOption) ([]networking.Ingress, error) {
 	var ingList networking.IngressList
 	err := l.cli.List(ctx, &ingList, opts...)
@@ -47,6 +53,14 @@ func (l *ingressLoader) List(ctx context.Context, opts ...client.ListOption) ([]
 		}
 	}
 
+	if l.withGateways {
+		gwIngs, err := ListGatewayIngresses(ctx, l.cli)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list gateway ingresses: %w", err)
+		}
+		result = append(result, gwIngs...)
+	}
+
 	return result, nil
 }
 
@@ -22,6 +22,22 @@ type IngressGroup struct {
 	Deleted []v1.Ingress
 }
 
+const (
+	// WarningOverriddenBySettings is reason of warnings about ingress annotations overridden by IngressGroupSettings
+	WarningOverriddenBySettings = "OverriddenByGroupSettings"
+	// WarningConflictingAnnotation is reason of warnings about values of group-wide annotations which differ from the
+	// ones of other ingresses of the group
+	WarningConflictingAnnotation = "ConflictingGroupAnnotation"
+)
+
+// GroupWarning is a problem of a single ingress which doesn't fail reconciliation of its group, e.g. ignored value of
+// group-wide annotation
+type GroupWarning struct {
+	Ingress types.NamespacedName
+	Reason  string
+	Message string
+}
+
 type Loader struct {
 	cli client.Client
 }
@@ -0,0 +1,66 @@
+package k8s
+
+import (
+	"fmt"
+	"strings"
+
+	networking "k8s.io/api/networking/v1"
+)
+
+// LockboxSecretIDPrefix in spec.tls[].secretName of ingress references Lockbox secret instead of Kubernetes one,
+// e.g. yc-lockbox-secret-id-e6q8rs3cdaq3bpcjf1r4. The secret keeps certificate chain and private key in
+// tls.crt and tls.key entries like Kubernetes TLS secrets do.
+const LockboxSecretIDPrefix = "yc-lockbox-secret-id-"
+
+// LockboxReference is a version of Lockbox secret referenced by ingress, empty VersionID stands for the current one
+type LockboxReference struct {
+	SecretID  string
+	VersionID string
+}
+
+func IsLockboxSecret(secretName string) bool {
+	return strings.HasPrefix(secretName, LockboxSecretIDPrefix)
+}
+
+// PinnedLockboxVersions returns versions of Lockbox secrets pinned by LockboxSecretVersions annotation of ingress
+func PinnedLockboxVersions(ing networking.Ingress) (map[string]string, error) {
+	versions, err := ParseConfigsFromAnnotationValue(ing.GetAnnotations()[LockboxSecretVersions])
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse lockbox secret versions: %w", err)
+	}
+	for secretID, versionID := range versions {
+		if versionID == "" {
+			return nil, fmt.Errorf("empty version of lockbox secret %s", secretID)
+		}
+	}
+	return versions, nil
+}
+
+// LockboxReferences returns Lockbox secrets referenced by TLS items of ingress
+func LockboxReferences(ing networking.Ingress) ([]LockboxReference, error) {
+	versions, err := PinnedLockboxVersions(ing)
+	if err != nil {
+		return nil, err
+	}
+
+	var ret []LockboxReference
+	seen := make(map[string]struct{})
+	for _, tls := range ing.Spec.TLS {
+		if !IsLockboxSecret(tls.SecretName) {
+			continue
+		}
+		secretID := strings.TrimPrefix(tls.SecretName, LockboxSecretIDPrefix)
+		if _, ok := seen[secretID]; ok {
+			continue
+		}
+		seen[secretID] = struct{}{}
+		ret = append(ret, LockboxReference{SecretID: secretID, VersionID: versions[secretID]})
+	}
+
+	for secretID := range versions {
+		if _, ok := seen[secretID]; !ok {
+			return nil, fmt.Errorf("version of lockbox secret %s is pinned, but the secret is not referenced by tls of ingress", secretID)
+		}
+	}
+	return ret, nil
+}
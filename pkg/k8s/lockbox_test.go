@@ -0,0 +1,66 @@
+package k8s
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	networking "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestLockboxReferences(t *testing.T) {
+	ingress := func(versions string, secretNames ...string) networking.Ingress {
+		ing := networking.Ingress{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "ing"}}
+		if versions != "" {
+			ing.Annotations = map[string]string{LockboxSecretVersions: versions}
+		}
+		for _, name := range secretNames {
+			ing.Spec.TLS = append(ing.Spec.TLS, networking.IngressTLS{Hosts: []string{"example.com"}, SecretName: name})
+		}
+		return ing
+	}
+
+	testData := []struct {
+		desc    string
+		ing     networking.Ingress
+		exp     []LockboxReference
+		wantErr bool
+	}{
+		{
+			desc: "current versions",
+			ing:  ingress("", "k8s-secret", LockboxSecretIDPrefix+"secret-1", LockboxSecretIDPrefix+"secret-2", LockboxSecretIDPrefix+"secret-1"),
+			exp:  []LockboxReference{{SecretID: "secret-1"}, {SecretID: "secret-2"}},
+		},
+		{
+			desc: "pinned version",
+			ing:  ingress("secret-2=version-1", LockboxSecretIDPrefix+"secret-1", LockboxSecretIDPrefix+"secret-2"),
+			exp:  []LockboxReference{{SecretID: "secret-1"}, {SecretID: "secret-2", VersionID: "version-1"}},
+		},
+		{
+			desc:    "pinned version of secret not referenced",
+			ing:     ingress("secret-2=version-1", LockboxSecretIDPrefix+"secret-1"),
+			wantErr: true,
+		},
+		{
+			desc:    "empty version",
+			ing:     ingress("secret-1=", LockboxSecretIDPrefix+"secret-1"),
+			wantErr: true,
+		},
+		{
+			desc: "no lockbox secrets",
+			ing:  ingress("", "k8s-secret"),
+		},
+	}
+	for _, tc := range testData {
+		t.Run(tc.desc, func(t *testing.T) {
+			refs, err := LockboxReferences(tc.ing)
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.exp, refs)
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/yandex-cloud/go-genproto/yandex/cloud/certificatemanager/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/yandex-cloud/yc-alb-ingress-controller/api/v1alpha1"
+	"github.com/yandex-cloud/yc-alb-ingress-controller/pkg/metrics"
+	"github.com/yandex-cloud/yc-alb-ingress-controller/pkg/yc"
+)
+
+// ManagedResourcesCounter periodically exports the number of cloud resources managed by the controller.
+// Balancers, routers and groups are counted by ingress group statuses and backend group CRs, certificates are
+// counted in the cloud along with exporting days left until their expiration.
+type ManagedResourcesCounter struct {
+	Client   client.Client
+	CertRepo yc.CertRepo
+	Interval time.Duration
+	Log      logr.Logger
+}
+
+// Start implements manager.Runnable
+func (c *ManagedResourcesCounter) Start(ctx context.Context) error {
+	ticker := time.NewTicker(c.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := c.Count(ctx); err != nil {
+				c.Log.Error(err, "failed to count managed resources")
+			}
+		}
+	}
+}
+
+func (c *ManagedResourcesCounter) Count(ctx context.Context) error {
+	var statuses v1alpha1.IngressGroupStatusList
+	if err := c.Client.List(ctx, &statuses); err != nil {
+		return fmt.Errorf("failed to list ingress group statuses: %w", err)
+	}
+
+	var httpBGs v1alpha1.HttpBackendGroupList
+	if err := c.Client.List(ctx, &httpBGs); err != nil {
+		return fmt.Errorf("failed to list http backend groups: %w", err)
+	}
+	var grpcBGs v1alpha1.GrpcBackendGroupList
+	if err := c.Client.List(ctx, &grpcBGs); err != nil {
+		return fmt.Errorf("failed to list grpc backend groups: %w", err)
+	}
+	var streamBGs v1alpha1.StreamBackendGroupList
+	if err := c.Client.List(ctx, &streamBGs); err != nil {
+		return fmt.Errorf("failed to list stream backend groups: %w", err)
+	}
+
+	certs, err := c.CertRepo.LoadCertificates(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load certificates: %w", err)
+	}
+
+	counts := countManagedResources(statuses.Items, len(httpBGs.Items)+len(grpcBGs.Items)+len(streamBGs.Items))
+	counts[metrics.ResourceCertificate] = len(certs)
+	for resource, count := range counts {
+		metrics.ManagedResources.WithLabelValues(resource).Set(float64(count))
+	}
+
+	metrics.CertificateExpiryDays.Reset()
+	for name, days := range certificateExpiryDays(certs, time.Now()) {
+		metrics.CertificateExpiryDays.WithLabelValues(name).Set(days)
+	}
+	return nil
+}
+
+// certificateExpiryDays skips certificates which are not issued yet
+func certificateExpiryDays(certs map[string]*certificatemanager.Certificate, now time.Time) map[string]float64 {
+	ret := make(map[string]float64)
+	for name, cert := range certs {
+		if cert.GetNotAfter() == nil {
+			continue
+		}
+		ret[name] = cert.GetNotAfter().AsTime().Sub(now).Hours() / 24
+	}
+	return ret
+}
+
+func countManagedResources(statuses []v1alpha1.IngressGroupStatus, crBackendGroups int) map[string]int {
+	var balancers, routers int
+	bgIDs, tgIDs := sets.NewString(), sets.NewString()
+	for _, status := range statuses {
+		if status.LoadBalancerID != "" {
+			balancers++
+		}
+		if status.HTTPRouterID != "" {
+			routers++
+		}
+		if status.TLSRouterID != "" {
+			routers++
+		}
+		bgIDs.Insert(status.BackendGroupIDs...)
+		tgIDs.Insert(status.TargetGroupIDs...)
+	}
+
+	return map[string]int{
+		metrics.ResourceBalancer:     balancers,
+		metrics.ResourceHTTPRouter:   routers,
+		metrics.ResourceBackendGroup: bgIDs.Len() + crBackendGroups,
+		metrics.ResourceTargetGroup:  tgIDs.Len(),
+	}
+}
@@ -0,0 +1,53 @@
+package k8s
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yandex-cloud/go-genproto/yandex/cloud/certificatemanager/v1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/yandex-cloud/yc-alb-ingress-controller/api/v1alpha1"
+	"github.com/yandex-cloud/yc-alb-ingress-controller/pkg/metrics"
+)
+
+func TestCountManagedResources(t *testing.T) {
+	statuses := []v1alpha1.IngressGroupStatus{
+		{
+			LoadBalancerID:  "alb-1",
+			HTTPRouterID:    "router-1",
+			TLSRouterID:     "router-2",
+			BackendGroupIDs: []string{"bg-1", "bg-2"},
+			TargetGroupIDs:  []string{"tg-1"},
+		},
+		{
+			LoadBalancerID:  "alb-2",
+			HTTPRouterID:    "router-3",
+			BackendGroupIDs: []string{"bg-2"},
+			TargetGroupIDs:  []string{"tg-1", "tg-2"},
+		},
+		{},
+	}
+
+	assert.Equal(t, map[string]int{
+		metrics.ResourceBalancer:     2,
+		metrics.ResourceHTTPRouter:   3,
+		metrics.ResourceBackendGroup: 5,
+		metrics.ResourceTargetGroup:  2,
+	}, countManagedResources(statuses, 3))
+}
+
+func TestCertificateExpiryDays(t *testing.T) {
+	now := time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)
+	certs := map[string]*certificatemanager.Certificate{
+		"valid":   {NotAfter: timestamppb.New(now.Add(36 * time.Hour))},
+		"expired": {NotAfter: timestamppb.New(now.Add(-48 * time.Hour))},
+		"pending": {},
+	}
+
+	assert.Equal(t, map[string]float64{
+		"valid":   1.5,
+		"expired": -2,
+	}, certificateExpiryDays(certs, now))
+}
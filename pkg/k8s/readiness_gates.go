@@ -0,0 +1,54 @@
+package k8s
+
+import (
+	"strings"
+
+	core "k8s.io/api/core/v1"
+)
+
+// ReadinessGatePrefix is a prefix of condition types of pod readiness gates set by the controller. Pods opt in by
+// declaring readiness gate with condition type ReadinessGatePrefix + name of the service, the condition becomes
+// true once the pod is reported healthy by every balancer serving the service in every zone.
+const ReadinessGatePrefix = "target-health.alb.yc.io/"
+
+func ReadinessGateConditionType(svcName string) core.PodConditionType {
+	return core.PodConditionType(ReadinessGatePrefix + svcName)
+}
+
+// ReadinessGateServices returns names of services whose target health is awaited by readiness gates of the pod
+func ReadinessGateServices(pod *core.Pod) []string {
+	var ret []string
+	for _, gate := range pod.Spec.ReadinessGates {
+		if name := strings.TrimPrefix(string(gate.ConditionType), ReadinessGatePrefix); name != string(gate.ConditionType) {
+			ret = append(ret, name)
+		}
+	}
+	return ret
+}
+
+// WaitsForTargetHealth reports whether the pod is ready apart from the readiness gate of the service,
+// so it has to become a target and pass health checks of balancers to become ready
+func WaitsForTargetHealth(pod *core.Pod, svcName string) bool {
+	if pod.DeletionTimestamp != nil || !hasReadinessGate(pod, svcName) {
+		return false
+	}
+	return podConditionTrue(pod, core.ContainersReady) && !podConditionTrue(pod, ReadinessGateConditionType(svcName))
+}
+
+func hasReadinessGate(pod *core.Pod, svcName string) bool {
+	for _, gate := range pod.Spec.ReadinessGates {
+		if gate.ConditionType == ReadinessGateConditionType(svcName) {
+			return true
+		}
+	}
+	return false
+}
+
+func podConditionTrue(pod *core.Pod, condType core.PodConditionType) bool {
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == condType {
+			return cond.Status == core.ConditionTrue
+		}
+	}
+	return false
+}
@@ -0,0 +1,68 @@
+package k8s
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	core "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestWaitsForTargetHealth(t *testing.T) {
+	podWith := func(gates []string, conditions map[core.PodConditionType]core.ConditionStatus) *core.Pod {
+		pod := &core.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pod"}}
+		for _, gate := range gates {
+			pod.Spec.ReadinessGates = append(pod.Spec.ReadinessGates, core.PodReadinessGate{ConditionType: core.PodConditionType(gate)})
+		}
+		for condType, status := range conditions {
+			pod.Status.Conditions = append(pod.Status.Conditions, core.PodCondition{Type: condType, Status: status})
+		}
+		return pod
+	}
+
+	testData := []struct {
+		desc string
+		pod  *core.Pod
+		exp  bool
+	}{
+		{
+			desc: "containers ready, gate pending",
+			pod:  podWith([]string{"target-health.alb.yc.io/svc"}, map[core.PodConditionType]core.ConditionStatus{core.ContainersReady: core.ConditionTrue}),
+			exp:  true,
+		},
+		{
+			desc: "gate of other service",
+			pod:  podWith([]string{"target-health.alb.yc.io/other"}, map[core.PodConditionType]core.ConditionStatus{core.ContainersReady: core.ConditionTrue}),
+		},
+		{
+			desc: "containers not ready",
+			pod:  podWith([]string{"target-health.alb.yc.io/svc"}, map[core.PodConditionType]core.ConditionStatus{core.ContainersReady: core.ConditionFalse}),
+		},
+		{
+			desc: "gate already true",
+			pod: podWith([]string{"target-health.alb.yc.io/svc"}, map[core.PodConditionType]core.ConditionStatus{
+				core.ContainersReady:          core.ConditionTrue,
+				"target-health.alb.yc.io/svc": core.ConditionTrue,
+			}),
+		},
+		{
+			desc: "no gates",
+			pod:  podWith(nil, map[core.PodConditionType]core.ConditionStatus{core.ContainersReady: core.ConditionTrue}),
+		},
+	}
+
+	for _, tc := range testData {
+		t.Run(tc.desc, func(t *testing.T) {
+			assert.Equal(t, tc.exp, WaitsForTargetHealth(tc.pod, "svc"))
+		})
+	}
+}
+
+func TestReadinessGateServices(t *testing.T) {
+	pod := &core.Pod{Spec: core.PodSpec{ReadinessGates: []core.PodReadinessGate{
+		{ConditionType: "target-health.alb.yc.io/svc1"},
+		{ConditionType: "example.com/other"},
+		{ConditionType: "target-health.alb.yc.io/svc2"},
+	}}}
+	assert.Equal(t, []string{"svc1", "svc2"}, ReadinessGateServices(pod))
+}
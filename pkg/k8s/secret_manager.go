@@ -78,7 +78,7 @@ func (m *secretManager) ManageGroup(_ context.Context, group *IngressGroup) {
 	secrets := ParseSecrets(group.Items)
 
 	for secret := range secrets {
-		if strings.HasPrefix(secret.Name, CertIDPrefix) {
+		if strings.HasPrefix(secret.Name, CertIDPrefix) || IsLockboxSecret(secret.Name) {
 			continue
 		}
 
@@ -132,6 +132,10 @@ func ParseSecrets(ings []networking.Ingress) map[types.NamespacedName]struct{} {
 
 	for _, item := range ings {
 		for _, tls := range item.Spec.TLS {
+			if tls.SecretName == "" {
+				continue
+			}
+
 			result[types.NamespacedName{
 				Name:      tls.SecretName,
 				Namespace: item.Namespace,
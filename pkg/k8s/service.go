@@ -19,6 +19,8 @@ type ServiceLoader interface {
 
 type DefaultServiceLoader struct {
 	Client client.Client
+	// GatewayAPI enables lookup of service references in Gateway API routes
+	GatewayAPI bool
 }
 
 type ServiceToReconcile struct {
@@ -40,7 +42,7 @@ func (l *DefaultServiceLoader) Load(ctx context.Context, name types.NamespacedNa
 
 	deleted := svc.DeletionTimestamp != nil
 	hasfinalizer := hasFinalizer(&svc, Finalizer)
-	refs, err := getServiceIngressRefs(ctx, l.Client, svc)
+	refs, err := getServiceIngressRefs(ctx, l.Client, svc, l.GatewayAPI)
 	if err != nil {
 		return ServiceToReconcile{}, fmt.Errorf("failed to get service ingress refs: %w", err)
 	}
@@ -81,12 +83,20 @@ func IsServiceReferencedByIngress(svc v1.Service, ing networking.Ingress) bool {
 	return false
 }
 
-func getServiceIngressRefs(ctx context.Context, cli client.Client, svc v1.Service) (map[string]IngressGroup, error) {
+func getServiceIngressRefs(ctx context.Context, cli client.Client, svc v1.Service, withGateways bool) (map[string]IngressGroup, error) {
 	ings, err := getManagedIngresses(ctx, cli)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get managed ingresses: %w", err)
 	}
 
+	if withGateways {
+		gwIngs, err := ListGatewayIngresses(ctx, cli)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get gateway ingresses: %w", err)
+		}
+		ings = append(ings, gwIngs...)
+	}
+
 	refs := make(map[string]struct{})
 
 	for _, ing := range ings {
@@ -103,6 +113,10 @@ func getServiceIngressRefs(ctx context.Context, cli client.Client, svc v1.Servic
 	if err != nil {
 		return nil, fmt.Errorf("failed to get service grpc backend group refs: %w", err)
 	}
+	streamBgRefs, err := getServiceStreamBGRefs(ctx, cli, svc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get service stream backend group refs: %w", err)
+	}
 
 	for _, ing := range ings {
 		for _, bg := range httpBgRefs {
@@ -115,6 +129,11 @@ func getServiceIngressRefs(ctx context.Context, cli client.Client, svc v1.Servic
 				refs[ing.Annotations[AlbTag]] = struct{}{}
 			}
 		}
+		for _, bg := range streamBgRefs {
+			if isBGReferencedByIngress(ing, "StreamBackendGroup", NamespacedNameOf(&bg)) {
+				refs[ing.Annotations[AlbTag]] = struct{}{}
+			}
+		}
 	}
 
 	groups := make(map[string]IngressGroup)
@@ -135,6 +154,62 @@ func getServiceIngressRefs(ctx context.Context, cli client.Client, svc v1.Servic
 	return groups, nil
 }
 
+// BackendGroupsOfService returns names of backend group resources having backends of the service which are used by
+// ingresses of the groups
+func BackendGroupsOfService(ctx context.Context, cli client.Client, svc v1.Service, groups map[string]IngressGroup) ([]types.NamespacedName, error) {
+	type ref struct {
+		kind string
+		name types.NamespacedName
+	}
+	var refs []ref
+	httpBgRefs, err := getServiceHTTPBGRefs(ctx, cli, svc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get service http backend group refs: %w", err)
+	}
+	for _, bg := range httpBgRefs {
+		refs = append(refs, ref{kind: "HttpBackendGroup", name: NamespacedNameOf(&bg)})
+	}
+	grpcBgRefs, err := getServiceGRPCBGRefs(ctx, cli, svc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get service grpc backend group refs: %w", err)
+	}
+	for _, bg := range grpcBgRefs {
+		refs = append(refs, ref{kind: "GrpcBackendGroup", name: NamespacedNameOf(&bg)})
+	}
+	streamBgRefs, err := getServiceStreamBGRefs(ctx, cli, svc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get service stream backend group refs: %w", err)
+	}
+	for _, bg := range streamBgRefs {
+		refs = append(refs, ref{kind: "StreamBackendGroup", name: NamespacedNameOf(&bg)})
+	}
+
+	var ret []types.NamespacedName
+	seen := make(map[types.NamespacedName]struct{})
+	for _, r := range refs {
+		if _, ok := seen[r.name]; ok {
+			continue
+		}
+		for _, g := range groups {
+			if isGroupReferencingBG(g, r.kind, r.name) {
+				seen[r.name] = struct{}{}
+				ret = append(ret, r.name)
+				break
+			}
+		}
+	}
+	return ret, nil
+}
+
+func isGroupReferencingBG(g IngressGroup, bgKind string, bgName types.NamespacedName) bool {
+	for _, ing := range g.Items {
+		if isBGReferencedByIngress(ing, bgKind, bgName) {
+			return true
+		}
+	}
+	return false
+}
+
 func getServiceHTTPBGRefs(ctx context.Context, cli client.Client, svc v1.Service) ([]v1alpha1.HttpBackendGroup, error) {
 	var bgs v1alpha1.HttpBackendGroupList
 	err := cli.List(ctx, &bgs)
@@ -214,11 +289,52 @@ func getManagedIngresses(ctx context.Context, cli client.Client) ([]networking.I
 	return validIngs, nil
 }
 
+func getServiceStreamBGRefs(ctx context.Context, cli client.Client, svc v1.Service) ([]v1alpha1.StreamBackendGroup, error) {
+	var bgs v1alpha1.StreamBackendGroupList
+	err := cli.List(ctx, &bgs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list stream backend groups: %w", err)
+	}
+
+	var refs []v1alpha1.StreamBackendGroup
+	for _, bg := range bgs.Items {
+		if bg.Namespace != svc.Namespace {
+			continue
+		}
+
+		for _, be := range bg.Spec.Backends {
+			if be.Service == nil {
+				continue
+			}
+
+			if be.Service.Name == svc.Name {
+				refs = append(refs, bg)
+			}
+		}
+	}
+
+	return refs, nil
+}
+
 func isBGReferencedByIngress(ing networking.Ingress, bgKind string, bgName types.NamespacedName) bool {
 	if ing.Namespace != bgName.Namespace {
 		return false
 	}
 
+	// stream backend groups are referenced by stream listeners only
+	if bgKind == "StreamBackendGroup" {
+		listeners, err := ParseConfigsFromAnnotationValue(ing.GetAnnotations()[StreamListeners])
+		if err != nil {
+			return false
+		}
+		for _, name := range listeners {
+			if name == bgName.Name {
+				return true
+			}
+		}
+		return false
+	}
+
 	if ing.Spec.DefaultBackend != nil && ing.Spec.DefaultBackend.Resource != nil {
 		res := ing.Spec.DefaultBackend.Resource
 		if res.Kind == bgKind && res.Name == bgName.Name {
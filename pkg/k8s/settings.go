@@ -3,8 +3,10 @@ package k8s
 import (
 	"context"
 	"fmt"
+	"sort"
 
 	"github.com/yandex-cloud/yc-alb-ingress-controller/api/v1alpha1"
+	"github.com/yandex-cloud/yc-alb-ingress-controller/pkg/algo"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -14,17 +16,13 @@ type GroupSettingsLoader struct {
 }
 
 func (l *GroupSettingsLoader) Load(ctx context.Context, g *IngressGroup) (*v1alpha1.IngressGroupSettings, error) {
-	name, err := getSettingsName(g)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get settings name: %w", err)
-	}
-
+	name := GroupSettingsName(g)
 	if name == "" {
 		return nil, nil
 	}
 
 	var settings v1alpha1.IngressGroupSettings
-	err = l.Client.Get(ctx, types.NamespacedName{Name: name}, &settings)
+	err := l.Client.Get(ctx, types.NamespacedName{Name: name}, &settings)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get ingress group settings: %w", err)
 	}
@@ -32,25 +30,46 @@ func (l *GroupSettingsLoader) Load(ctx context.Context, g *IngressGroup) (*v1alp
 	return &settings, nil
 }
 
-func getSettingsName(g *IngressGroup) (string, error) {
-	var res string
-
+// GroupSettingsName returns name of IngressGroupSettings referenced by the first ingress of the group having
+// the annotation. Like other group-wide annotations, different names set by other ingresses are ignored
+// and reported as warnings of those ingresses
+func GroupSettingsName(g *IngressGroup) string {
 	for _, item := range g.Items {
-		name, ok := item.Annotations[GroupSettings]
-		if !ok || (res != "" && name == res) {
-			continue
+		if name := item.Annotations[GroupSettings]; name != "" {
+			return name
 		}
+	}
+	return ""
+}
 
-		if name == "" {
-			return "", fmt.Errorf("empty group settings annotation value in the ingress: %s/%s", item.Namespace, item.Name)
-		}
+// GroupSettingsStatusUpdater lists ingress groups in statuses of IngressGroupSettings they use
+type GroupSettingsStatusUpdater struct {
+	Client client.Client
+}
+
+// Update adds the group to status of the settings with the name and removes it from statuses of other settings,
+// empty name removes the group from all of them
+func (u *GroupSettingsStatusUpdater) Update(ctx context.Context, tag, name string) error {
+	var list v1alpha1.IngressGroupSettingsList
+	if err := u.Client.List(ctx, &list); err != nil {
+		return fmt.Errorf("failed to list ingress group settings: %w", err)
+	}
 
-		if res != "" {
-			return "", fmt.Errorf("more than one settings name specified in the group: %s", g.Tag)
+	for i := range list.Items {
+		settings := &list.Items[i]
+		groups := algo.Filter(settings.Status.IngressGroups, func(g string) bool { return g != tag })
+		if settings.Name == name {
+			groups = append(groups, tag)
+			sort.Strings(groups)
+		}
+		if algo.ContainSameElements(groups, settings.Status.IngressGroups) {
+			continue
 		}
 
-		res = name
+		settings.Status.IngressGroups = groups
+		if err := u.Client.Status().Update(ctx, settings); err != nil {
+			return fmt.Errorf("failed to update status of ingress group settings %s: %w", settings.Name, err)
+		}
 	}
-
-	return res, nil
+	return nil
 }
@@ -79,7 +79,7 @@ func TestGroupSettingsLoader_Load(t *testing.T) {
 			desc:    "with-empty-settings",
 			objects: []client.Object{},
 			g:       EmptySettings,
-			wantErr: true,
+			exp:     nil,
 		},
 		{
 			desc:    "only-one-settings",
@@ -94,10 +94,11 @@ func TestGroupSettingsLoader_Load(t *testing.T) {
 			exp:     &DefaultSettings,
 		},
 		{
-			desc:    "more-than-one-settings-invalid",
+			// the first ingress wins, others are reported on building of the group
+			desc:    "more-than-one-settings-conflicting",
 			objects: []client.Object{&DefaultSettings},
 			g:       MoreThanOneSettingsInvalid,
-			wantErr: true,
+			exp:     &DefaultSettings,
 		},
 	}
 
@@ -132,3 +133,35 @@ func ingWithSettingsAnnotation(name, annotation string) networking.Ingress {
 	ing.Annotations[GroupSettings] = annotation
 	return ing
 }
+
+func TestGroupSettingsStatusUpdater_Update(t *testing.T) {
+	ctx := context.Background()
+	err := albv1alpha1.AddToScheme(scheme.Scheme)
+	assert.NoError(t, err)
+
+	settings := func(name string, groups ...string) *albv1alpha1.IngressGroupSettings {
+		return &albv1alpha1.IngressGroupSettings{
+			ObjectMeta: v1.ObjectMeta{Name: name},
+			Status:     albv1alpha1.IngressGroupSettingsStatus{IngressGroups: groups},
+		}
+	}
+	cli := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(
+		settings("old", "group", "other-group"),
+		settings("new", "other-group"),
+	).Build()
+	updater := GroupSettingsStatusUpdater{Client: cli}
+
+	groups := func(name string) []string {
+		var s albv1alpha1.IngressGroupSettings
+		assert.NoError(t, cli.Get(ctx, client.ObjectKey{Name: name}, &s))
+		return s.Status.IngressGroups
+	}
+
+	assert.NoError(t, updater.Update(ctx, "group", "new"))
+	assert.Equal(t, []string{"other-group"}, groups("old"))
+	assert.Equal(t, []string{"group", "other-group"}, groups("new"))
+
+	assert.NoError(t, updater.Update(ctx, "group", ""))
+	assert.Equal(t, []string{"other-group"}, groups("old"))
+	assert.Equal(t, []string{"other-group"}, groups("new"))
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	networking "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -87,6 +88,28 @@ func (h *GroupStatusManager) SetBalancerResourcesIDs(ctx context.Context, status
 	return h.cli.Patch(ctx, status, client.MergeFrom(oldStatus))
 }
 
+func (h *GroupStatusManager) SetPlannedChanges(ctx context.Context, status *v1alpha1.IngressGroupStatus, changes []v1alpha1.PlannedChange) error {
+	oldStatus := status.DeepCopy()
+	status.PlannedChanges = changes
+
+	return h.cli.Patch(ctx, status, client.MergeFrom(oldStatus))
+}
+
+// SetReconcileResult sets conditions of the group by the reason of the reconciliation outcome together with the rules
+// accepted in its ingresses. The group has no generation of its own, generations are observed for ingresses
+func (h *GroupStatusManager) SetReconcileResult(
+	ctx context.Context, status *v1alpha1.IngressGroupStatus, reason, message string, ingresses []v1alpha1.IngressRulesStatus,
+) error {
+	oldStatus := status.DeepCopy()
+	SetReconcileConditions(&status.Conditions, 0, reason, message)
+	status.Ingresses = ingresses
+	if equality.Semantic.DeepEqual(oldStatus, status) {
+		return nil
+	}
+
+	return h.cli.Patch(ctx, status, client.MergeFrom(oldStatus))
+}
+
 func (h *GroupStatusManager) LoadStatus(ctx context.Context, name string) (*v1alpha1.IngressGroupStatus, error) {
 	var status v1alpha1.IngressGroupStatus
 	err := h.cli.Get(ctx, types.NamespacedName{Name: name}, &status)
@@ -0,0 +1,208 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	core "k8s.io/api/core/v1"
+	networking "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/yandex-cloud/yc-alb-ingress-controller/api/v1alpha1"
+	"github.com/yandex-cloud/yc-alb-ingress-controller/pkg/metadata"
+	"github.com/yandex-cloud/yc-alb-ingress-controller/pkg/yc"
+)
+
+const UnhealthyTargetsReason = "UnhealthyTargets"
+
+// TargetStatesPublisher publishes health of targets into statuses of backend group CRs and as warning events
+// on backend group CRs, services and ingresses having unhealthy targets
+type TargetStatesPublisher struct {
+	Client   client.Client
+	Names    *metadata.Names
+	Recorder record.EventRecorder
+}
+
+// Balancers returns IDs of balancers by names of their ingress groups
+func (p *TargetStatesPublisher) Balancers(ctx context.Context) (map[string]string, error) {
+	var statuses v1alpha1.IngressGroupStatusList
+	err := p.Client.List(ctx, &statuses)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ingress group statuses: %w", err)
+	}
+
+	ret := make(map[string]string)
+	for _, status := range statuses.Items {
+		if status.LoadBalancerID != "" {
+			ret[status.Name] = status.LoadBalancerID
+		}
+	}
+	return ret, nil
+}
+
+// Publish sets health of targets of backend groups in the states. Statuses of backend group CRs missing in the states
+// aren't used by balancers anymore, so they are reset if the states are complete, i.e. all balancers were polled
+func (p *TargetStatesPublisher) Publish(ctx context.Context, states []yc.BackendGroupTargetStates, complete bool) error {
+	byName := make(map[string][]v1alpha1.BackendTargetStates)
+	unhealthyGroups := make(map[string][]string)
+	for _, s := range states {
+		backends := convertBackendTargetStates(s.Backends)
+		byName[s.BackendGroup.Name] = backends
+		if hasUnhealthyTargets(backends) {
+			unhealthyGroups[s.Group] = append(unhealthyGroups[s.Group], s.BackendGroup.Name)
+		}
+	}
+
+	for _, bgs := range []backendGroupCRs{
+		{kind: "http backend group", list: &v1alpha1.HttpBackendGroupList{}, backends: func(o client.Object) *[]v1alpha1.BackendTargetStates {
+			return &o.(*v1alpha1.HttpBackendGroup).Status.Backends
+		}},
+		{kind: "grpc backend group", list: &v1alpha1.GrpcBackendGroupList{}, backends: func(o client.Object) *[]v1alpha1.BackendTargetStates {
+			return &o.(*v1alpha1.GrpcBackendGroup).Status.Backends
+		}},
+		{kind: "stream backend group", list: &v1alpha1.StreamBackendGroupList{}, backends: func(o client.Object) *[]v1alpha1.BackendTargetStates {
+			return &o.(*v1alpha1.StreamBackendGroup).Status.Backends
+		}},
+	} {
+		if err := p.publishBackendGroups(ctx, bgs, byName, complete); err != nil {
+			return err
+		}
+	}
+	if err := p.publishServices(ctx, byName); err != nil {
+		return err
+	}
+	return p.publishIngresses(ctx, unhealthyGroups)
+}
+
+// backendGroupCRs is a kind of backend group CRs, backends returns health of targets in status of the CR
+type backendGroupCRs struct {
+	kind     string
+	list     client.ObjectList
+	backends func(client.Object) *[]v1alpha1.BackendTargetStates
+}
+
+func (p *TargetStatesPublisher) publishBackendGroups(
+	ctx context.Context, bgs backendGroupCRs, byName map[string][]v1alpha1.BackendTargetStates, complete bool,
+) error {
+	err := p.Client.List(ctx, bgs.list)
+	if err != nil {
+		return fmt.Errorf("failed to list %ss: %w", bgs.kind, err)
+	}
+	items, err := meta.ExtractList(bgs.list)
+	if err != nil {
+		return fmt.Errorf("failed to extract %ss: %w", bgs.kind, err)
+	}
+
+	for _, item := range items {
+		bg := item.(client.Object)
+		backends, ok := byName[p.Names.BackendGroupForCR(bg.GetNamespace(), bg.GetName())]
+		if !ok && !complete {
+			continue
+		}
+		p.recordUnhealthy(bg, backends)
+
+		status := bgs.backends(bg)
+		if reflect.DeepEqual(*status, backends) {
+			continue
+		}
+
+		old := bg.DeepCopyObject().(client.Object)
+		*status = backends
+		err = p.Client.Status().Patch(ctx, bg, client.MergeFrom(old))
+		if err != nil {
+			return fmt.Errorf("failed to update status of %s %s/%s: %w", bgs.kind, bg.GetNamespace(), bg.GetName(), err)
+		}
+	}
+	return nil
+}
+
+func (p *TargetStatesPublisher) publishServices(ctx context.Context, byName map[string][]v1alpha1.BackendTargetStates) error {
+	var list core.ServiceList
+	err := p.Client.List(ctx, &list)
+	if err != nil {
+		return fmt.Errorf("failed to list services: %w", err)
+	}
+
+	for i := range list.Items {
+		svc := &list.Items[i]
+		if !hasFinalizer(svc, Finalizer) {
+			continue
+		}
+
+		for _, port := range svc.Spec.Ports {
+			backendPort, err := BackendPort(ctx, p.Client, svc, port)
+			if err != nil {
+				// backend groups can't be built for the service, error is reported by its controller
+				continue
+			}
+			nn := NamespacedNameOf(svc)
+			p.recordUnhealthy(svc, byName[p.Names.BackendGroupForSvcPort(nn, int64(backendPort))])
+			p.recordUnhealthy(svc, byName[p.Names.CanaryBackendGroupForSvcPort(nn, int64(backendPort))])
+		}
+	}
+	return nil
+}
+
+func (p *TargetStatesPublisher) publishIngresses(ctx context.Context, unhealthyGroups map[string][]string) error {
+	if len(unhealthyGroups) == 0 {
+		return nil
+	}
+
+	var list networking.IngressList
+	err := p.Client.List(ctx, &list)
+	if err != nil {
+		return fmt.Errorf("failed to list ingresses: %w", err)
+	}
+
+	for i := range list.Items {
+		ing := &list.Items[i]
+		bgNames, ok := unhealthyGroups[GetBalancerTag(ing)]
+		if !ok {
+			continue
+		}
+		sort.Strings(bgNames)
+		p.Recorder.Eventf(ing, core.EventTypeWarning, UnhealthyTargetsReason,
+			"backend groups %s have unhealthy targets", strings.Join(bgNames, ", "))
+	}
+	return nil
+}
+
+func (p *TargetStatesPublisher) recordUnhealthy(obj client.Object, backends []v1alpha1.BackendTargetStates) {
+	for _, b := range backends {
+		for _, z := range b.Zones {
+			if z.Unhealthy == 0 {
+				continue
+			}
+			p.Recorder.Eventf(obj, core.EventTypeWarning, UnhealthyTargetsReason,
+				"backend %s has %d unhealthy and %d healthy targets in zone %s", b.Name, z.Unhealthy, z.Healthy, z.ZoneID)
+		}
+	}
+}
+
+func hasUnhealthyTargets(backends []v1alpha1.BackendTargetStates) bool {
+	for _, b := range backends {
+		for _, z := range b.Zones {
+			if z.Unhealthy > 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func convertBackendTargetStates(backends []yc.BackendTargetStates) []v1alpha1.BackendTargetStates {
+	var ret []v1alpha1.BackendTargetStates
+	for _, b := range backends {
+		var zones []v1alpha1.ZoneTargetStates
+		for _, z := range b.Zones {
+			zones = append(zones, v1alpha1.ZoneTargetStates{ZoneID: z.ZoneID, Healthy: z.Healthy, Unhealthy: z.Unhealthy})
+		}
+		ret = append(ret, v1alpha1.BackendTargetStates{Name: b.Name, Zones: zones})
+	}
+	return ret
+}
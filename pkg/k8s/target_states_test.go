@@ -0,0 +1,67 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yandex-cloud/go-genproto/yandex/cloud/apploadbalancer/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/yandex-cloud/yc-alb-ingress-controller/api/v1alpha1"
+	"github.com/yandex-cloud/yc-alb-ingress-controller/pkg/metadata"
+	"github.com/yandex-cloud/yc-alb-ingress-controller/pkg/yc"
+)
+
+func TestTargetStatesPublisher_Publish(t *testing.T) {
+	require.NoError(t, v1alpha1.AddToScheme(scheme.Scheme))
+	ctx := context.Background()
+	names := &metadata.Names{ClusterID: "cluster"}
+
+	stale := []v1alpha1.BackendTargetStates{{Name: "stale", Zones: []v1alpha1.ZoneTargetStates{{ZoneID: "ru-central1-a", Healthy: 1}}}}
+	polled := &v1alpha1.HttpBackendGroup{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "polled"}}
+	unused := &v1alpha1.GrpcBackendGroup{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "unused"},
+		Status:     v1alpha1.GrpcBackendGroupStatus{Backends: stale},
+	}
+	states := []yc.BackendGroupTargetStates{{
+		Group:        "tag",
+		BalancerID:   "alb",
+		BackendGroup: &apploadbalancer.BackendGroup{Name: names.BackendGroupForCR("default", "polled")},
+		Backends: []yc.BackendTargetStates{{
+			Name:  "backend",
+			Zones: []yc.ZoneTargetStates{{ZoneID: "ru-central1-a", Healthy: 2, Unhealthy: 1}},
+		}},
+	}}
+
+	for _, tc := range []struct {
+		desc       string
+		complete   bool
+		wantUnused []v1alpha1.BackendTargetStates
+	}{
+		{desc: "complete", complete: true},
+		{desc: "incomplete", complete: false, wantUnused: stale},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			cli := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(polled.DeepCopy(), unused.DeepCopy()).Build()
+			p := &TargetStatesPublisher{Client: cli, Names: names, Recorder: record.NewFakeRecorder(10)}
+			require.NoError(t, p.Publish(ctx, states, tc.complete))
+
+			var actualPolled v1alpha1.HttpBackendGroup
+			require.NoError(t, cli.Get(ctx, types.NamespacedName{Namespace: "default", Name: "polled"}, &actualPolled))
+			assert.Equal(t, []v1alpha1.BackendTargetStates{{
+				Name:  "backend",
+				Zones: []v1alpha1.ZoneTargetStates{{ZoneID: "ru-central1-a", Healthy: 2, Unhealthy: 1}},
+			}}, actualPolled.Status.Backends)
+
+			var actualUnused v1alpha1.GrpcBackendGroup
+			require.NoError(t, cli.Get(ctx, types.NamespacedName{Namespace: "default", Name: "unused"}, &actualUnused))
+			assert.Equal(t, tc.wantUnused, actualUnused.Status.Backends)
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+
+	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ycerrors "github.com/yandex-cloud/yc-alb-ingress-controller/pkg/errors"
+)
+
+const (
+	TargetModeNode  = "node"
+	TargetModePodIP = "pod-ip"
+)
+
+var defaultTargetMode = TargetModeNode
+
+// SetupDefaultTargetMode sets target mode of services without TargetMode annotation
+func SetupDefaultTargetMode(mode string) error {
+	if err := validateTargetMode(mode); err != nil {
+		return err
+	}
+	defaultTargetMode = mode
+	return nil
+}
+
+func validateTargetMode(mode string) error {
+	if mode != TargetModeNode && mode != TargetModePodIP {
+		return fmt.Errorf("unknown target mode %q, must be %q or %q", mode, TargetModeNode, TargetModePodIP)
+	}
+	return nil
+}
+
+// PodIPTargets reports whether pods of the service are used as targets instead of cluster nodes
+func PodIPTargets(svc *core.Service) (bool, error) {
+	mode, ok := svc.GetAnnotations()[TargetMode]
+	if !ok {
+		mode = defaultTargetMode
+	}
+	if err := validateTargetMode(mode); err != nil {
+		return false, fmt.Errorf("invalid annotation %s of service %s/%s: %w", TargetMode, svc.Namespace, svc.Name, err)
+	}
+	return mode == TargetModePodIP, nil
+}
+
+// BackendPort returns port of targets serving the service port: NodePort for node targets and
+// container port for pod IP targets. Targets of a backend are served on one port, so a named target port
+// must be resolved to the same container port in all the pods of the service.
+func BackendPort(ctx context.Context, cli client.Reader, svc *core.Service, port core.ServicePort) (int32, error) {
+	podTargets, err := PodIPTargets(svc)
+	if err != nil {
+		return 0, err
+	}
+	if !podTargets {
+		return port.NodePort, nil
+	}
+
+	switch {
+	case port.TargetPort.Type == intstr.String:
+		return namedTargetPort(ctx, cli, svc, port.TargetPort.StrVal)
+	case port.TargetPort.IntVal != 0:
+		return port.TargetPort.IntVal, nil
+	default:
+		return port.Port, nil
+	}
+}
+
+// namedTargetPort resolves the named target port by container ports of pods selected by the service
+func namedTargetPort(ctx context.Context, cli client.Reader, svc *core.Service, name string) (int32, error) {
+	if len(svc.Spec.Selector) == 0 {
+		return 0, fmt.Errorf("named target port %s of service %s/%s without selector is not supported with pod IP targets",
+			name, svc.Namespace, svc.Name)
+	}
+
+	var pods core.PodList
+	err := cli.List(ctx, &pods, client.InNamespace(svc.Namespace), client.MatchingLabels(svc.Spec.Selector))
+	if err != nil {
+		return 0, fmt.Errorf("failed to list pods of service %s/%s: %w", svc.Namespace, svc.Name, err)
+	}
+
+	var ret int32
+	for _, pod := range pods.Items {
+		for _, c := range pod.Spec.Containers {
+			for _, p := range c.Ports {
+				if p.Name != name {
+					continue
+				}
+				if ret != 0 && ret != p.ContainerPort {
+					return 0, fmt.Errorf("named target port %s of service %s/%s is resolved to different container ports %d and %d",
+						name, svc.Namespace, svc.Name, ret, p.ContainerPort)
+				}
+				ret = p.ContainerPort
+			}
+		}
+	}
+	if ret == 0 {
+		return 0, ycerrors.ResourceNotReadyError{ResourceType: "Pod", Name: fmt.Sprintf("with port %s of service %s/%s", name, svc.Namespace, svc.Name)}
+	}
+	return ret, nil
+}
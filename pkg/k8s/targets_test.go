@@ -0,0 +1,121 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	core "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	ycerrors "github.com/yandex-cloud/yc-alb-ingress-controller/pkg/errors"
+)
+
+func TestBackendPort(t *testing.T) {
+	svcWithMode := func(mode string) *core.Service {
+		svc := &core.Service{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "svc"},
+			Spec:       core.ServiceSpec{Selector: map[string]string{"app": "web"}},
+		}
+		if mode != "" {
+			svc.Annotations = map[string]string{TargetMode: mode}
+		}
+		return svc
+	}
+	podWithPort := func(name string, port int32) client.Object {
+		return &core.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name, Labels: map[string]string{"app": "web"}},
+			Spec: core.PodSpec{Containers: []core.Container{{
+				Name:  "app",
+				Ports: []core.ContainerPort{{Name: "http", ContainerPort: port}},
+			}}},
+		}
+	}
+
+	testData := []struct {
+		desc     string
+		svc      *core.Service
+		pods     []client.Object
+		port     core.ServicePort
+		exp      int32
+		wantErr  bool
+		notReady bool
+	}{
+		{
+			desc: "node by default",
+			svc:  svcWithMode(""),
+			port: core.ServicePort{Port: 80, TargetPort: intstr.FromInt(8080), NodePort: 30080},
+			exp:  30080,
+		},
+		{
+			desc: "pod IP",
+			svc:  svcWithMode(TargetModePodIP),
+			port: core.ServicePort{Port: 80, TargetPort: intstr.FromInt(8080), NodePort: 30080},
+			exp:  8080,
+		},
+		{
+			desc: "pod IP without target port",
+			svc:  svcWithMode(TargetModePodIP),
+			port: core.ServicePort{Port: 80},
+			exp:  80,
+		},
+		{
+			desc: "pod IP with named target port",
+			svc:  svcWithMode(TargetModePodIP),
+			pods: []client.Object{podWithPort("pod-a", 8080), podWithPort("pod-b", 8080)},
+			port: core.ServicePort{Port: 80, TargetPort: intstr.FromString("http")},
+			exp:  8080,
+		},
+		{
+			desc:    "pod IP with named target port resolved differently by pods",
+			svc:     svcWithMode(TargetModePodIP),
+			pods:    []client.Object{podWithPort("pod-a", 8080), podWithPort("pod-b", 9090)},
+			port:    core.ServicePort{Port: 80, TargetPort: intstr.FromString("http")},
+			wantErr: true,
+		},
+		{
+			desc:     "pod IP with named target port without pods",
+			svc:      svcWithMode(TargetModePodIP),
+			port:     core.ServicePort{Port: 80, TargetPort: intstr.FromString("http")},
+			wantErr:  true,
+			notReady: true,
+		},
+		{
+			desc:    "unknown mode",
+			svc:     svcWithMode("instance"),
+			port:    core.ServicePort{Port: 80, NodePort: 30080},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testData {
+		t.Run(tc.desc, func(t *testing.T) {
+			cli := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(tc.pods...).Build()
+			port, err := BackendPort(context.Background(), cli, tc.svc, tc.port)
+			if tc.wantErr {
+				assert.Error(t, err)
+				assert.Equal(t, tc.notReady, errors.As(err, &ycerrors.ResourceNotReadyError{}))
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.exp, port)
+		})
+	}
+}
+
+func TestSetupDefaultTargetMode(t *testing.T) {
+	defer func() { defaultTargetMode = TargetModeNode }()
+
+	require.Error(t, SetupDefaultTargetMode("instance"))
+	require.NoError(t, SetupDefaultTargetMode(TargetModePodIP))
+
+	podTargets, err := PodIPTargets(&core.Service{})
+	require.NoError(t, err)
+	assert.True(t, podTargets)
+}
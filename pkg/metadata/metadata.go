@@ -44,6 +44,14 @@ func (n *Names) ListenerTLS(tag string) string {
 	return fmt.Sprintf("%s-%x", "listenertls", n.sha(tag))
 }
 
+func (n *Names) ListenerStream(tag string, port int64) string {
+	return fmt.Sprintf("%s-%x-%d", "lstream", n.sha(tag), port)
+}
+
+func (n *Names) ListenerCustom(tag string, port int64) string {
+	return fmt.Sprintf("%s-%x-%d", "lcustom", n.sha(tag), port)
+}
+
 func (n *Names) VirtualHostForRule(ns, name, tag string, i int) string {
 	return fmt.Sprintf("%s-%x-%d", "vh", n.sha(fmt.Sprintf("%s-%s-%s", ns, name, tag)), i)
 }
@@ -91,6 +99,10 @@ func (n *Names) BackendGroupForSvcPort(name types.NamespacedName, nodePort int64
 	return fmt.Sprintf("%s-%x", "bg", n.sha(fmt.Sprintf("%s-%s-%s-%d", name.Namespace, name.Name, n.ClusterID, nodePort)))
 }
 
+func (n *Names) CanaryBackendGroupForSvcPort(name types.NamespacedName, nodePort int64) string {
+	return fmt.Sprintf("%s-%x", "bg-canary", n.sha(fmt.Sprintf("%s-%s-%s-%d", name.Namespace, name.Name, n.ClusterID, nodePort)))
+}
+
 func (n *Names) Backend(tag, ns, svcName string, port, nodePort int32) string {
 	return fmt.Sprintf("%s-%x-%d-%d", "backend", n.sha(fmt.Sprintf("%s-%s-%s", ns, svcName, tag)), port, nodePort)
 }
@@ -103,6 +115,11 @@ func (n *Names) Certificate(name types.NamespacedName) string {
 	return fmt.Sprintf("%s-%x", "cert", n.sha(fmt.Sprintf("%s-%s", name.Namespace, name.Name)))
 }
 
+// LockboxCertificate is a name of certificate synced from Lockbox secret, empty versionID stands for its current version
+func (n *Names) LockboxCertificate(secretID, versionID string) string {
+	return fmt.Sprintf("%s-%x", "cert-lockbox", n.sha(fmt.Sprintf("%s-%s", secretID, versionID)))
+}
+
 // TODO:builder
 type Labels struct {
 	ClusterLabelName, ClusterID string
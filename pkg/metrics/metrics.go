@@ -0,0 +1,97 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+	crmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const namespace = "yc_alb"
+
+// Kinds of managed cloud resources counted by ManagedResources
+const (
+	ResourceBalancer     = "balancer"
+	ResourceHTTPRouter   = "http_router"
+	ResourceBackendGroup = "backend_group"
+	ResourceTargetGroup  = "target_group"
+	ResourceCertificate  = "certificate"
+)
+
+var (
+	// ReconcileTotal counts reconcile attempts by their outcome, group is empty for controllers not dealing with ingress groups
+	ReconcileTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "reconcile_total",
+		Help:      "Total number of reconcile attempts per controller, ingress group and result",
+	}, []string{"controller", "group", "result"})
+
+	CloudAPIRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "cloud_api_request_duration_seconds",
+		Help:      "Latency of cloud API requests per method",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method"})
+
+	CloudAPIErrorsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "cloud_api_errors_total",
+		Help:      "Total number of failed cloud API requests per method and gRPC code",
+	}, []string{"method", "code"})
+
+	OperationWaitDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "operation_wait_duration_seconds",
+		Help:      "Time spent waiting for cloud operations to complete",
+		Buckets:   prometheus.ExponentialBuckets(0.5, 2, 10),
+	}, []string{"result"})
+
+	ManagedResources = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "managed_resources",
+		Help:      "Number of cloud resources managed by the controller",
+	}, []string{"resource"})
+
+	// CertificateExpiryDays is negative for expired certificates
+	CertificateExpiryDays = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "certificate_expiry_days",
+		Help:      "Days left until expiration of certificates managed by the controller",
+	}, []string{"certificate"})
+)
+
+func init() {
+	crmetrics.Registry.MustRegister(
+		ReconcileTotal,
+		CloudAPIRequestDuration,
+		CloudAPIErrorsTotal,
+		OperationWaitDuration,
+		ManagedResources,
+		CertificateExpiryDays,
+	)
+}
+
+// UnaryClientInterceptor measures latency and counts errors of every cloud API call made via SDK
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		start := time.Now()
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		CloudAPIRequestDuration.WithLabelValues(method).Observe(time.Since(start).Seconds())
+		if err != nil {
+			CloudAPIErrorsTotal.WithLabelValues(method, status.Code(err).String()).Inc()
+		}
+		return err
+	}
+}
+
+// ObserveOperationWait records time spent waiting for an operation started at start
+func ObserveOperationWait(start time.Time, err error) {
+	result := "done"
+	if err != nil {
+		result = "error"
+	}
+	OperationWaitDuration.WithLabelValues(result).Observe(time.Since(start).Seconds())
+}
@@ -0,0 +1,37 @@
+package metrics
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestUnaryClientInterceptor(t *testing.T) {
+	const method = "/yandex.cloud.apploadbalancer.v1.LoadBalancerService/Get"
+	interceptor := UnaryClientInterceptor()
+
+	ok := func(context.Context, string, interface{}, interface{}, *grpc.ClientConn, ...grpc.CallOption) error {
+		return nil
+	}
+	notFound := func(context.Context, string, interface{}, interface{}, *grpc.ClientConn, ...grpc.CallOption) error {
+		return status.Error(codes.NotFound, "not found")
+	}
+
+	assert.NoError(t, interceptor(context.Background(), method, nil, nil, nil, ok))
+	assert.Error(t, interceptor(context.Background(), method, nil, nil, nil, notFound))
+
+	var duration dto.Metric
+	require.NoError(t, CloudAPIRequestDuration.WithLabelValues(method).(prometheus.Histogram).Write(&duration))
+	assert.Equal(t, uint64(2), duration.GetHistogram().GetSampleCount())
+
+	var errs dto.Metric
+	require.NoError(t, CloudAPIErrorsTotal.WithLabelValues(method, codes.NotFound.String()).Write(&errs))
+	assert.Equal(t, float64(1), errs.GetCounter().GetValue())
+}
@@ -11,3 +11,9 @@ import (
 func Equal(x, y proto.Message) bool {
 	return cmp.Equal(x, y, protocmp.Transform(), protocmp.IgnoreUnknown())
 }
+
+// Diff reports differences between x and y in a human-readable form with the same
+// options as Equal, empty string means messages are equal.
+func Diff(x, y proto.Message) string {
+	return cmp.Diff(x, y, protocmp.Transform(), protocmp.IgnoreUnknown())
+}
@@ -44,3 +44,16 @@ func TestEqual_DetectsRealDiff(t *testing.T) {
 	}
 	assert.False(t, Equal(a, b), "Equal must still detect real differences in known fields")
 }
+
+func TestDiff(t *testing.T) {
+	a := &apploadbalancer.Target{
+		AddressType: &apploadbalancer.Target_IpAddress{IpAddress: "10.0.0.1"},
+		SubnetId:    "subnet-1",
+	}
+	b := &apploadbalancer.Target{
+		AddressType: &apploadbalancer.Target_IpAddress{IpAddress: "10.0.0.2"},
+		SubnetId:    "subnet-1",
+	}
+	assert.Empty(t, Diff(a, withUnknownField(a)), "Diff must ignore unknown fields")
+	assert.Contains(t, Diff(a, b), "10.0.0.2")
+}
@@ -0,0 +1,81 @@
+package reconcile
+
+import (
+	"fmt"
+	"strings"
+
+	networking "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/yandex-cloud/yc-alb-ingress-controller/pkg/algo"
+	"github.com/yandex-cloud/yc-alb-ingress-controller/pkg/k8s"
+)
+
+// groupConflicts resolves values of group-wide annotations. Values set by IngressGroupSettings take precedence over
+// annotations, otherwise the first ingress of the group having the annotation wins. Ignored values of other ingresses
+// are collected as warnings instead of failing the whole group
+type groupConflicts struct {
+	warnings []k8s.GroupWarning
+}
+
+func (c *groupConflicts) warn(ing networking.Ingress, reason, format string, args ...interface{}) {
+	if c == nil {
+		return
+	}
+	c.warnings = append(c.warnings, k8s.GroupWarning{
+		Ingress: types.NamespacedName{Namespace: ing.Namespace, Name: ing.Name},
+		Reason:  reason,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// annotation returns the settings value if it's not empty, otherwise the value of the first ingress having the annotation
+func (c *groupConflicts) annotation(g *k8s.IngressGroup, annotation, settingsValue string) string {
+	if settingsValue != "" {
+		c.overridden(g, annotation, settingsValue)
+		return settingsValue
+	}
+
+	var value string
+	var owner networking.Ingress
+	for _, ing := range g.Items {
+		v := ing.GetAnnotations()[annotation]
+		switch {
+		case v == "" || v == value:
+		case value == "":
+			value, owner = v, ing
+		default:
+			c.warn(ing, k8s.WarningConflictingAnnotation, "value %q of annotation %s is ignored, value %q of ingress %s/%s is used",
+				v, annotation, value, owner.Namespace, owner.Name)
+		}
+	}
+	return value
+}
+
+// overridden warns about ingresses having the annotation with value other than the one set by settings
+func (c *groupConflicts) overridden(g *k8s.IngressGroup, annotation, settingsValue string) {
+	for _, ing := range g.Items {
+		if v := ing.GetAnnotations()[annotation]; v != "" && v != settingsValue {
+			c.warn(ing, k8s.WarningOverriddenBySettings, "value %q of annotation %s is overridden by ingress group settings with %q",
+				v, annotation, settingsValue)
+		}
+	}
+}
+
+// overriddenList is like overridden for annotations with comma separated lists, the order of elements doesn't matter
+func (c *groupConflicts) overriddenList(g *k8s.IngressGroup, annotation string, settingsValues []string) {
+	for _, ing := range g.Items {
+		v := ing.GetAnnotations()[annotation]
+		if v != "" && !algo.ContainSameElements(strings.Split(v, ","), settingsValues) {
+			c.warn(ing, k8s.WarningOverriddenBySettings, "value %q of annotation %s is overridden by ingress group settings with %q",
+				v, annotation, strings.Join(settingsValues, ","))
+		}
+	}
+}
+
+func (c *groupConflicts) result() []k8s.GroupWarning {
+	if c == nil {
+		return nil
+	}
+	return c.warnings
+}
@@ -55,7 +55,7 @@ func (r *IngressGroupEngine) ReconcileTLSRouter(ctx context.Context, router *app
 }
 
 func (r *IngressGroupEngine) ReconcileBalancer(ctx context.Context, balancer *apploadbalancer.LoadBalancer) (*deploy.ReconciledBalancer, error) {
-	if r.Data == nil || (r.HTTPRouter == nil || len(r.HTTPRouter.Router.VirtualHosts) == 0) && (r.TLSRouter == nil || len(r.TLSRouter.Router.VirtualHosts) == 0) { // assume no routes means no ingresses -> delete
+	if r.Data == nil || !r.HTTPRouter.HasVirtualHosts() && !r.TLSRouter.HasVirtualHosts() && !hasStreamListeners(r.Balancer) { // assume no routes and no stream listeners means no ingresses -> delete
 		if balancer.GetStatus() == apploadbalancer.LoadBalancer_DELETING {
 			return nil, ycerrors.YCResourceNotReadyError{
 				ResourceType: "ALB",
@@ -66,7 +66,7 @@ func (r *IngressGroupEngine) ReconcileBalancer(ctx context.Context, balancer *ap
 	}
 
 	if balancer == nil { // create
-		op, err := r.Repo.CreateLoadBalancer(context.Background(), r.Data.Balancer)
+		op, err := r.Repo.CreateLoadBalancer(ctx, r.Data.Balancer)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create load balancer: %w", err)
 		}
@@ -93,7 +93,7 @@ func (r *IngressGroupEngine) ReconcileBalancer(ctx context.Context, balancer *ap
 
 	r.Data.Balancer.Id = balancer.Id
 	if r.Predicates.BalancerNeedsUpdate(balancer, r.Data.Balancer) {
-		op, err := r.Repo.UpdateLoadBalancer(context.Background(), r.Data.Balancer)
+		op, err := r.Repo.UpdateLoadBalancer(ctx, r.Data.Balancer)
 		if err != nil {
 			return nil, fmt.Errorf("failed to update load balancer: %w", err)
 		}
@@ -102,6 +102,15 @@ func (r *IngressGroupEngine) ReconcileBalancer(ctx context.Context, balancer *ap
 	return &deploy.ReconciledBalancer{Active: balancer}, nil
 }
 
+func hasStreamListeners(balancer *apploadbalancer.LoadBalancer) bool {
+	for _, l := range balancer.GetListeners() {
+		if l.GetStream() != nil {
+			return true
+		}
+	}
+	return false
+}
+
 func reconcileHTTPRouter(ctx context.Context, repo Repository, currentRouter *apploadbalancer.HttpRouter, d *builders.HTTPRouterData, predicates UpdatePredicates) (*deploy.ReconciledHTTPRouter, error) {
 	if d == nil || d.Router == nil || len(d.Router.VirtualHosts) == 0 {
 		return &deploy.ReconciledHTTPRouter{Garbage: currentRouter}, nil
@@ -126,7 +135,7 @@ func reconcileHTTPRouter(ctx context.Context, repo Repository, currentRouter *ap
 
 	d.Router.Id = currentRouter.Id
 	if predicates.RouterNeedsUpdate(currentRouter, d.Router) {
-		_, err := repo.UpdateHTTPRouter(context.Background(), d.Router)
+		_, err := repo.UpdateHTTPRouter(ctx, d.Router)
 		if err != nil {
 			return nil, fmt.Errorf("failed to update http router: %w", err)
 		}
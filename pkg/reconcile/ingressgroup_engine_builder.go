@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/yandex-cloud/go-genproto/yandex/cloud/apploadbalancer/v1"
+	"github.com/yandex-cloud/go-genproto/yandex/cloud/certificatemanager/v1"
 	"google.golang.org/genproto/googleapis/rpc/code"
 	"google.golang.org/protobuf/types/known/wrapperspb"
 	v1 "k8s.io/api/core/v1"
@@ -15,6 +16,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/yandex-cloud/yc-alb-ingress-controller/api/v1alpha1"
+	ycerrors "github.com/yandex-cloud/yc-alb-ingress-controller/pkg/errors"
 	"github.com/yandex-cloud/yc-alb-ingress-controller/pkg/metadata"
 
 	"github.com/yandex-cloud/yc-alb-ingress-controller/pkg/builders"
@@ -61,23 +63,38 @@ func (d *DefaultEngineBuilder) Build(ctx context.Context, g *k8s.IngressGroup, s
 	if len(g.Items) == 0 {
 		return d.newIngressGroupEngine(nil), nil
 	}
-	networkID, locations, err := d.locations(g)
+	conflicts := &groupConflicts{}
+	// settings are loaded by the name of the first ingress having it, names of others are only reported
+	conflicts.annotation(g, k8s.GroupSettings, "")
+	networkID, locations, err := d.locations(g, settings, conflicts)
 	if err != nil {
 		return nil, fmt.Errorf("failed to build locations: %w", err)
 	}
 	addressParams := builders.AddressParams{DefaultSubnetID: locations[0].SubnetId}
-	addresses, err := d.addresses(g, addressParams)
+	addresses, err := d.addresses(g, settings, addressParams, conflicts)
 	if err != nil {
 		return nil, fmt.Errorf("failed to build addresses: %w", err)
 	}
-	securityGroupIDs := d.securityGroupIDs(g)
-	autoScalePolicy, err := d.autoScalePolicy(g)
+	securityGroupIDs := d.securityGroupIDs(g, settings, conflicts)
+	autoScalePolicy, err := d.autoScalePolicy(g, settings, conflicts)
 	if err != nil {
 		return nil, fmt.Errorf("failed to build auto scale policy: %w", err)
 	}
-	allowHTTP10, err := d.allowHTTP10(g)
+	handlerOpts, err := d.handlerOptions(g, settings, conflicts)
 	if err != nil {
-		return nil, fmt.Errorf("failed to build allow http10: %w", err)
+		return nil, fmt.Errorf("failed to build handler options: %w", err)
+	}
+	streamListeners, err := d.streamListeners(ctx, g)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build stream listeners: %w", err)
+	}
+	redirectHTTPToHTTPS, err := d.redirectHTTPToHTTPS(g, settings, conflicts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build redirect of http to https: %w", err)
+	}
+	customListeners, err := d.customListeners(settings, streamListeners)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build custom listeners: %w", err)
 	}
 
 	opts := builders.Options{
@@ -88,47 +105,76 @@ func (d *DefaultEngineBuilder) Build(ctx context.Context, g *k8s.IngressGroup, s
 			AutoScalePolicy:  autoScalePolicy,
 		},
 		ListenerOptions: builders.ListenerOptions{
-			Addresses: addresses,
-		},
-		HandlerOptions: builders.HandlerOptions{
-			AllowHTTP10: allowHTTP10,
+			Addresses:           addresses,
+			StreamListeners:     streamListeners,
+			CustomListeners:     customListeners,
+			RedirectHTTPToHTTPS: redirectHTTPToHTTPS,
 		},
+		HandlerOptions: handlerOpts,
 	}
 
 	b := builders.Data{}
-	b.HTTPRouter, b.TLSRouter, err = d.buildVirtualHosts(g)
+	defaultRBAC, err := d.buildDefaultRBAC(settings)
 	if err != nil {
-		return nil, fmt.Errorf("failed to build virtual hosts: %w", err)
+		return nil, fmt.Errorf("failed to build rbac from group settings: %w", err)
 	}
-	b.Handler = builders.BuildHTTPHandler(opts.HandlerOptions)
-	b.SNIMatches, err = d.buildSNIMatches(ctx, g, opts.HandlerOptions)
+	securityProfileID := d.securityProfileID(g, settings, conflicts)
+	b.HTTPRouter, b.TLSRouter, err = d.buildVirtualHosts(g, defaultRBAC, securityProfileID, redirectHTTPToHTTPS)
 	if err != nil {
-		return nil, fmt.Errorf("failed to build sni matches: %w", err)
+		return nil, fmt.Errorf("failed to build virtual hosts: %w", err)
+	}
+	// groups consisting of stream listeners only have no http handlers
+	if b.HTTPRouter.HasVirtualHosts() || b.TLSRouter.HasVirtualHosts() {
+		if !redirectHTTPToHTTPS {
+			b.Handler = builders.BuildHTTPHandler(opts.HandlerOptions)
+		}
+		b.SNIMatches, err = d.buildSNIMatches(ctx, g, opts.HandlerOptions)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build sni matches: %w", err)
+		}
+	}
+	// groups consisting of stream listeners have no tls hosts either
+	if redirectHTTPToHTTPS && len(b.SNIMatches) == 0 {
+		return nil, fmt.Errorf("redirect of http to https requires tls hosts in ingress group %s", g.Tag)
 	}
 	b.LogOptions = d.buildLogOptions(settings)
 
 	b.Balancer = d.buildBalancer(b.Handler, b.SNIMatches, b.LogOptions, g.Tag, opts)
+	b.Warnings = conflicts.result()
 
 	return d.newIngressGroupEngine(&b), nil
 }
 
-func (d *DefaultEngineBuilder) addresses(g *k8s.IngressGroup, p builders.AddressParams) ([]*apploadbalancer.Address, error) {
-	resolver := d.resolvers.Addresses(p)
-	for _, ing := range g.Items {
-		annotations := ing.GetAnnotations()
-		data := builders.AddressData{
-			ExternalIPv4: annotations[k8s.ExternalIPv4Address],
-			ExternalIPv6: annotations[k8s.ExternalIPv6Address],
-			InternalIPv4: annotations[k8s.InternalIPv4Address],
-			SubnetID:     annotations[k8s.InternalALBSubnet],
-		}
-		resolver.Resolve(data)
+func (d *DefaultEngineBuilder) addresses(
+	g *k8s.IngressGroup, settings *v1alpha1.IngressGroupSettings, p builders.AddressParams, conflicts *groupConflicts,
+) ([]*apploadbalancer.Address, error) {
+	var s v1alpha1.Addresses
+	if settings != nil && settings.Addresses != nil {
+		s = *settings.Addresses
 	}
+	resolver := d.resolvers.Addresses(p)
+	resolver.Resolve(builders.AddressData{
+		ExternalIPv4: conflicts.annotation(g, k8s.ExternalIPv4Address, s.ExternalIPv4),
+		ExternalIPv6: conflicts.annotation(g, k8s.ExternalIPv6Address, s.ExternalIPv6),
+		InternalIPv4: conflicts.annotation(g, k8s.InternalIPv4Address, s.InternalIPv4),
+		SubnetID:     conflicts.annotation(g, k8s.InternalALBSubnet, s.InternalSubnetID),
+	})
 	return resolver.Result()
 }
 
-func (d *DefaultEngineBuilder) locations(g *k8s.IngressGroup) (string, []*apploadbalancer.Location, error) {
+// locations uses subnets of IngressGroupSettings if set, otherwise subnets of all ingresses of the group
+func (d *DefaultEngineBuilder) locations(
+	g *k8s.IngressGroup, settings *v1alpha1.IngressGroupSettings, conflicts *groupConflicts,
+) (string, []*apploadbalancer.Location, error) {
 	resolver := d.resolvers.Location()
+	if settings != nil && len(settings.Subnets) > 0 {
+		conflicts.overriddenList(g, k8s.Subnets, settings.Subnets)
+		if err := resolver.Resolve(strings.Join(settings.Subnets, ",")); err != nil {
+			return "", nil, fmt.Errorf("failed to resolve location: %w", err)
+		}
+		return resolver.Result()
+	}
+
 	for _, ing := range g.Items {
 		if err := resolver.Resolve(ing.GetAnnotations()[k8s.Subnets]); err != nil {
 			return "", nil, fmt.Errorf("failed to resolve location: %w", err)
@@ -137,54 +183,231 @@ func (d *DefaultEngineBuilder) locations(g *k8s.IngressGroup) (string, []*apploa
 	return resolver.Result()
 }
 
-func (d *DefaultEngineBuilder) securityGroupIDs(g *k8s.IngressGroup) []string {
+// securityGroupIDs uses security groups of IngressGroupSettings if set, otherwise security groups of all ingresses
+// of the group
+func (d *DefaultEngineBuilder) securityGroupIDs(g *k8s.IngressGroup, settings *v1alpha1.IngressGroupSettings, conflicts *groupConflicts) []string {
 	resolver := d.resolvers.SecurityGroups()
+	if settings != nil && len(settings.SecurityGroups) > 0 {
+		conflicts.overriddenList(g, k8s.SecurityGroups, settings.SecurityGroups)
+		resolver.Resolve(strings.Join(settings.SecurityGroups, ","))
+		return resolver.Result()
+	}
+
 	for _, ing := range g.Items {
 		resolver.Resolve(ing.GetAnnotations()[k8s.SecurityGroups])
 	}
 	return resolver.Result()
 }
 
-func (d *DefaultEngineBuilder) autoScalePolicy(g *k8s.IngressGroup) (*apploadbalancer.AutoScalePolicy, error) {
+func (d *DefaultEngineBuilder) autoScalePolicy(
+	g *k8s.IngressGroup, settings *v1alpha1.IngressGroupSettings, conflicts *groupConflicts,
+) (*apploadbalancer.AutoScalePolicy, error) {
+	var minZoneSize, maxSize string
+	if settings != nil && settings.AutoScale != nil {
+		minZoneSize, maxSize = formatOptionalInt(settings.AutoScale.MinZoneSize), formatOptionalInt(settings.AutoScale.MaxSize)
+	}
+
 	resolver := d.resolvers.AutoScalePolicy()
-	for _, ing := range g.Items {
-		err := resolver.Resolve(ing.GetAnnotations()[k8s.AutoscalingMinZoneSize], ing.GetAnnotations()[k8s.AutoscalingMaxSize])
+	err := resolver.Resolve(
+		conflicts.annotation(g, k8s.AutoscalingMinZoneSize, minZoneSize),
+		conflicts.annotation(g, k8s.AutoscalingMaxSize, maxSize),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve auto scale policy: %w", err)
+	}
+	return resolver.Result(), nil
+}
+
+func formatOptionalInt(v *int64) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.FormatInt(*v, 10)
+}
+
+// handlerOptions returns protocol settings of IngressGroupSettings if set, otherwise the ones of ingress annotations
+func (d *DefaultEngineBuilder) handlerOptions(
+	g *k8s.IngressGroup, settings *v1alpha1.IngressGroupSettings, conflicts *groupConflicts,
+) (builders.HandlerOptions, error) {
+	var ret builders.HandlerOptions
+	if settings != nil && settings.ProtocolSettings != nil {
+		ret.AllowHTTP10 = settings.ProtocolSettings.AllowHTTP10
+		if http2 := settings.ProtocolSettings.HTTP2; http2 != nil {
+			ret.HTTP2, ret.HTTP2MaxConcurrentStreams = true, http2.MaxConcurrentStreams
+		}
+
+		conflicts.overridden(g, k8s.AllowHTTP10, strconv.FormatBool(ret.AllowHTTP10))
+		conflicts.overridden(g, k8s.HTTP2, strconv.FormatBool(ret.HTTP2))
+		if ret.HTTP2 {
+			conflicts.overridden(g, k8s.HTTP2MaxConcurrentStreams, strconv.FormatInt(ret.HTTP2MaxConcurrentStreams, 10))
+		} else {
+			conflicts.overridden(g, k8s.HTTP2MaxConcurrentStreams, "")
+		}
+	} else {
+		allowHTTP10, err := d.allowHTTP10(g, conflicts)
 		if err != nil {
-			return nil, fmt.Errorf("failed to resolve auto scale policy: %w", err)
+			return builders.HandlerOptions{}, fmt.Errorf("failed to build allow http10: %w", err)
+		}
+		ret.AllowHTTP10 = allowHTTP10
+
+		ret.HTTP2, ret.HTTP2MaxConcurrentStreams, err = d.http2(g, conflicts)
+		if err != nil {
+			return builders.HandlerOptions{}, fmt.Errorf("failed to build http2 options: %w", err)
 		}
 	}
-	return resolver.Result(), nil
+
+	if ret.AllowHTTP10 && ret.HTTP2 {
+		return builders.HandlerOptions{}, fmt.Errorf("http2 can't be enabled together with allow http10 in ingress group %s", g.Tag)
+	}
+	return ret, nil
 }
 
-func (d *DefaultEngineBuilder) allowHTTP10(g *k8s.IngressGroup) (bool, error) {
+func (d *DefaultEngineBuilder) http2(g *k8s.IngressGroup, conflicts *groupConflicts) (bool, int64, error) {
+	resolver := d.resolvers.HTTP2()
+	err := resolver.Resolve(
+		conflicts.annotation(g, k8s.HTTP2, ""),
+		conflicts.annotation(g, k8s.HTTP2MaxConcurrentStreams, ""),
+	)
+	if err != nil {
+		return false, 0, fmt.Errorf("failed to resolve http2: %w", err)
+	}
+	enabled, streams := resolver.Result()
+	return enabled, streams, nil
+}
+
+func (d *DefaultEngineBuilder) allowHTTP10(g *k8s.IngressGroup, conflicts *groupConflicts) (bool, error) {
 	resolver := d.resolvers.AllowHTTP10()
+	err := resolver.Resolve(conflicts.annotation(g, k8s.AllowHTTP10, ""))
+	if err != nil {
+		return false, fmt.Errorf("failed to resolve allow http10: %w", err)
+	}
+	return resolver.Result(), nil
+}
+
+func (d *DefaultEngineBuilder) streamListeners(ctx context.Context, g *k8s.IngressGroup) ([]builders.StreamListener, error) {
+	listeners, err := d.resolveStreamListeners(g)
+	if err != nil {
+		return nil, err
+	}
+
+	var ret []builders.StreamListener
+	for _, l := range listeners {
+		bgName := d.names.BackendGroupForCR(l.BackendGroup.Namespace, l.BackendGroup.Name)
+		bg, err := d.bgFinder.FindBackendGroup(ctx, bgName)
+		if err != nil {
+			return nil, fmt.Errorf("error finding backend group: %w", err)
+		}
+		if bg == nil {
+			return nil, ycerrors.ResourceNotReadyError{ResourceType: "BackendGroup", Name: bgName}
+		}
+		ret = append(ret, builders.StreamListener{Port: l.Port, BackendGroupID: bg.Id})
+	}
+	return ret, nil
+}
+
+func (d *DefaultEngineBuilder) resolveStreamListeners(g *k8s.IngressGroup) ([]builders.StreamListenerData, error) {
+	resolver := d.resolvers.StreamListeners()
 	for _, ing := range g.Items {
-		err := resolver.Resolve(ing.GetAnnotations()[k8s.AllowHTTP10])
+		err := resolver.Resolve(ing.Namespace, ing.GetAnnotations()[k8s.StreamListeners])
 		if err != nil {
-			return false, fmt.Errorf("failed to resolve allow http10: %w", err)
+			return nil, fmt.Errorf("failed to resolve stream listeners for ingress %s/%s: %w", ing.Namespace, ing.Name, err)
 		}
 	}
 	return resolver.Result(), nil
 }
 
+func (d *DefaultEngineBuilder) redirectHTTPToHTTPS(
+	g *k8s.IngressGroup, settings *v1alpha1.IngressGroupSettings, conflicts *groupConflicts,
+) (bool, error) {
+	if settings != nil && settings.RedirectHTTPToHTTPS != nil {
+		value := *settings.RedirectHTTPToHTTPS
+		conflicts.overridden(g, k8s.RedirectHTTPToHTTPS, strconv.FormatBool(value))
+		return value, nil
+	}
+	return parseBoolValue(conflicts.annotation(g, k8s.RedirectHTTPToHTTPS, ""))
+}
+
+// securityProfileID returns security profile of IngressGroupSettings overriding the ones of virtual hosts set by
+// ingress annotations, empty if not set
+func (d *DefaultEngineBuilder) securityProfileID(g *k8s.IngressGroup, settings *v1alpha1.IngressGroupSettings, conflicts *groupConflicts) string {
+	if settings == nil || settings.SecurityProfileID == "" {
+		return ""
+	}
+	conflicts.overridden(g, k8s.SecurityProfileID, settings.SecurityProfileID)
+	return settings.SecurityProfileID
+}
+
+func (d *DefaultEngineBuilder) customListeners(settings *v1alpha1.IngressGroupSettings, streamListeners []builders.StreamListener) ([]builders.CustomListener, error) {
+	if settings == nil {
+		return nil, nil
+	}
+
+	usedPorts := map[int64]string{80: "http listener", 443: "tls listener"}
+	for _, sl := range streamListeners {
+		usedPorts[sl.Port] = "stream listener"
+	}
+
+	var ret []builders.CustomListener
+	for _, l := range settings.Listeners {
+		if used, ok := usedPorts[l.Port]; ok {
+			return nil, fmt.Errorf("port %d of listener is already used by %s", l.Port, used)
+		}
+		usedPorts[l.Port] = "another listener"
+
+		cl := builders.CustomListener{Port: l.Port}
+		switch l.Protocol {
+		case "HTTP":
+		case "HTTPS":
+			cl.TLS = true
+		default:
+			return nil, fmt.Errorf("unsupported protocol %q of listener on port %d", l.Protocol, l.Port)
+		}
+		switch l.Router {
+		case "", "HTTP":
+		case "TLS":
+			cl.TLSRouter = true
+		default:
+			return nil, fmt.Errorf("unsupported router %q of listener on port %d", l.Router, l.Port)
+		}
+		ret = append(ret, cl)
+	}
+	return ret, nil
+}
+
 func (d *DefaultEngineBuilder) routeOpts(ing networking.Ingress) (builders.RouteResolveOpts, error) {
 	r := d.resolvers.RouteOpts()
 	annotations := ing.GetAnnotations()
-	return r.Resolve(
+	opts, err := r.Resolve(
 		annotations[k8s.RequestTimeout],
 		annotations[k8s.IdleTimeout],
 		annotations[k8s.PrefixRewrite],
+		annotations[k8s.HostRewrite],
+		annotations[k8s.AutoHostRewrite],
 		annotations[k8s.UpgradeTypes],
 		annotations[k8s.Protocol],
 		annotations[k8s.UseRegex],
 		annotations[k8s.AllowedMethods],
+		annotations[k8s.RouteSecurityProfileID],
 	)
+	if err != nil {
+		return builders.RouteResolveOpts{}, err
+	}
+
+	opts.RBAC, err = d.resolvers.RBAC().Resolve(
+		annotations[k8s.RouteRBACAction],
+		annotations[k8s.RouteRBACRemoteIPs],
+		annotations[k8s.RouteRBACHeaders],
+	)
+	if err != nil {
+		return builders.RouteResolveOpts{}, fmt.Errorf("failed to resolve route rbac for ingress %s/%s: %w", ing.Namespace, ing.Name, err)
+	}
+	return opts, nil
 }
 
 func (d *DefaultEngineBuilder) vhOpts(ing networking.Ingress) (builders.VirtualHostResolveOpts, error) {
 	r := d.resolvers.VirtualHostOpts()
 	annotations := ing.GetAnnotations()
-	return r.Resolve(
+	opts, err := r.Resolve(
 		annotations[k8s.ModifyResponseHeaderRemove],
 		annotations[k8s.ModifyResponseHeaderRename],
 		annotations[k8s.ModifyResponseHeaderAppend],
@@ -195,6 +418,19 @@ func (d *DefaultEngineBuilder) vhOpts(ing networking.Ingress) (builders.VirtualH
 		annotations[k8s.ModifyRequestHeaderReplace],
 		annotations[k8s.SecurityProfileID],
 	)
+	if err != nil {
+		return builders.VirtualHostResolveOpts{}, err
+	}
+
+	opts.RBAC, err = d.resolvers.RBAC().Resolve(
+		annotations[k8s.RBACAction],
+		annotations[k8s.RBACRemoteIPs],
+		annotations[k8s.RBACHeaders],
+	)
+	if err != nil {
+		return builders.VirtualHostResolveOpts{}, fmt.Errorf("failed to resolve rbac for ingress %s/%s: %w", ing.Namespace, ing.Name, err)
+	}
+	return opts, nil
 }
 
 func (d *DefaultEngineBuilder) directResponses(ing networking.Ingress) (map[string]*apploadbalancer.DirectResponseAction, error) {
@@ -208,12 +444,12 @@ func (d *DefaultEngineBuilder) directResponses(ing networking.Ingress) (map[stri
 
 		configs, err := k8s.ParseConfigsFromAnnotationValue(value)
 		if err != nil {
-			return nil, fmt.Errorf("failed to parse config from annotation value: %w", err)
+			return nil, fmt.Errorf("failed to parse config from annotation %s: %w", key, err)
 		}
 
 		statusCode, err := strconv.Atoi(configs["status"])
 		if err != nil {
-			return nil, fmt.Errorf("failed to parse status code: %w", err)
+			return nil, fmt.Errorf("failed to parse status code of annotation %s: %w", key, err)
 		}
 
 		result[strings.TrimPrefix(key, k8s.DirectResponsePrefix)] = &apploadbalancer.DirectResponseAction{
@@ -225,6 +461,33 @@ func (d *DefaultEngineBuilder) directResponses(ing networking.Ingress) (map[stri
 	return result, nil
 }
 
+func (d *DefaultEngineBuilder) grpcStatusResponses(ing networking.Ingress) (map[string]*apploadbalancer.GrpcStatusResponseAction, error) {
+	result := make(map[string]*apploadbalancer.GrpcStatusResponseAction)
+	annotations := ing.GetAnnotations()
+
+	for key, value := range annotations {
+		if !strings.HasPrefix(key, k8s.GRPCStatusResponsePrefix) {
+			continue
+		}
+
+		configs, err := k8s.ParseConfigsFromAnnotationValue(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse config from annotation %s: %w", key, err)
+		}
+
+		status, ok := apploadbalancer.GrpcStatusResponseAction_Status_value[strings.ToUpper(configs["status"])]
+		if !ok {
+			return nil, fmt.Errorf("unsupported grpc status %q in annotation %s", configs["status"], key)
+		}
+
+		result[strings.TrimPrefix(key, k8s.GRPCStatusResponsePrefix)] = &apploadbalancer.GrpcStatusResponseAction{
+			Status: apploadbalancer.GrpcStatusResponseAction_Status(status),
+		}
+	}
+
+	return result, nil
+}
+
 func parseIntValue(s string) (int, error) {
 	if s == "" {
 		return 0, nil
@@ -252,7 +515,7 @@ func (d *DefaultEngineBuilder) redirects(ing networking.Ingress) (map[string]*ap
 
 		configs, err := k8s.ParseConfigsFromAnnotationValue(value)
 		if err != nil {
-			return nil, fmt.Errorf("error parsing annotation %s value %s for ingress %s/%s: %w", ing.Name, ing.Namespace, key, value, err)
+			return nil, fmt.Errorf("error parsing annotation %s value %s for ingress %s/%s: %w", key, value, ing.Namespace, ing.Name, err)
 		}
 
 		replacePort, err := parseIntValue(configs["replace_port"])
@@ -295,10 +558,16 @@ func (d *DefaultEngineBuilder) redirects(ing networking.Ingress) (map[string]*ap
 	return result, nil
 }
 
-func (d *DefaultEngineBuilder) buildVirtualHosts(g *k8s.IngressGroup) (*builders.HTTPRouterData, *builders.HTTPRouterData, error) {
+// buildVirtualHosts returns no HTTP router if HTTP listener redirects requests to HTTPS, all the routes are served by
+// TLS router then
+func (d *DefaultEngineBuilder) buildVirtualHosts(
+	g *k8s.IngressGroup, defaultRBAC *apploadbalancer.RBAC, securityProfileID string, redirectHTTPToHTTPS bool,
+) (*builders.HTTPRouterData, *builders.HTTPRouterData, error) {
 	d.factory.RestartVirtualHostIDGenerator()
 	httpVHBuilder := d.factory.HTTPRouterBuilder(g.Tag, d.bgFinder)
 	tlsVHBuilder := d.factory.TLSHTTPRouterBuilder(g.Tag, d.bgFinder)
+	httpVHBuilder.SetDefaultRBAC(defaultRBAC)
+	tlsVHBuilder.SetDefaultRBAC(defaultRBAC)
 
 	handleBackend := func(
 		ns string,
@@ -308,7 +577,11 @@ func (d *DefaultEngineBuilder) buildVirtualHosts(g *k8s.IngressGroup) (*builders
 		backendType builders.BackendType,
 		directResponseActions map[string]*apploadbalancer.DirectResponseAction,
 		redirectActions map[string]*apploadbalancer.RedirectAction,
+		grpcStatusResponseActions map[string]*apploadbalancer.GrpcStatusResponseAction,
+		canary *canaryBackend,
 	) error {
+		isTlS = isTlS || redirectHTTPToHTTPS
+
 		if backend.Resource != nil && backend.Resource.Kind == "DirectResponse" {
 			directResponse, found := directResponseActions[backend.Resource.Name]
 			if !found {
@@ -322,6 +595,19 @@ func (d *DefaultEngineBuilder) buildVirtualHosts(g *k8s.IngressGroup) (*builders
 			return tlsVHBuilder.AddHTTPDirectResponse(hp, directResponse)
 		}
 
+		if backend.Resource != nil && backend.Resource.Kind == "GrpcStatusResponse" {
+			statusResponse, found := grpcStatusResponseActions[backend.Resource.Name]
+			if !found {
+				return fmt.Errorf("grpc status response action for host %s and path %s not found", hp.Host, hp.Path)
+			}
+
+			err := httpVHBuilder.AddGRPCStatusResponse(hp, statusResponse)
+			if err != nil {
+				return fmt.Errorf("failed to add grpc status response: %w", err)
+			}
+			return tlsVHBuilder.AddGRPCStatusResponse(hp, statusResponse)
+		}
+
 		if backend.Resource != nil && backend.Resource.Kind == "Redirect" {
 			redirect, found := redirectActions[backend.Resource.Name]
 			if !found {
@@ -349,29 +635,27 @@ func (d *DefaultEngineBuilder) buildVirtualHosts(g *k8s.IngressGroup) (*builders
 			return tlsVHBuilder.AddRouteToResource(hp, backend.Resource.Name)
 		}
 
+		if canary != nil && backend.Service == nil {
+			return fmt.Errorf("canary for host %s and path %s is supported only for service backends", hp.Host, hp.Path)
+		}
+
 		if backend.Service != nil {
-			var svc v1.Service
-			err := d.k8scli.Get(context.Background(), types.NamespacedName{
-				Name:      backend.Service.Name,
-				Namespace: ns,
-			}, &svc)
+			port, err := d.serviceBackendPort(ns, *backend.Service)
 			if err != nil {
-				return fmt.Errorf("failed to get service: %w", err)
+				return err
 			}
 
-			var port int32
-			for _, p := range svc.Spec.Ports {
-				if p.Name == backend.Service.Port.Name || p.Port == backend.Service.Port.Number {
-					port = p.NodePort
-					break
-				}
+			addRoute := func(b *builders.HTTPRouterBuilder) error {
+				return b.AddRoute(hp, backend.Service.Name, int64(port))
 			}
-			if port == 0 {
-				return fmt.Errorf("failed to find port for service: %s", backend.Service.Name)
+			if canary != nil {
+				addRoute = func(b *builders.HTTPRouterBuilder) error {
+					return b.AddCanaryRoute(hp, canary.svcName, int64(canary.port))
+				}
 			}
 
 			if !isTlS {
-				return httpVHBuilder.AddRoute(hp, backend.Service.Name, int64(port))
+				return addRoute(httpVHBuilder)
 			}
 
 			if backendType != builders.GRPC {
@@ -381,15 +665,26 @@ func (d *DefaultEngineBuilder) buildVirtualHosts(g *k8s.IngressGroup) (*builders
 				}
 			}
 
-			return tlsVHBuilder.AddRoute(hp, backend.Service.Name, int64(port))
+			return addRoute(tlsVHBuilder)
 		}
 
 		return nil
 	}
 
+	canaries, err := d.canaryBackends(g)
+	if err != nil {
+		return nil, nil, err
+	}
+	usedCanaries := make(map[builders.HostAndPath]struct{})
+
 	var ingWithDefaultBackend *networking.Ingress
 
 	for _, ing := range g.Items {
+		if k8s.IsCanary(&ing) {
+			// routes of canary ingresses are merged into the primary ones
+			continue
+		}
+
 		if ing.Spec.DefaultBackend != nil && ingWithDefaultBackend != nil {
 			return nil, nil, fmt.Errorf("default backend can be specified only once, ingress-group: %s", g.Tag)
 		}
@@ -406,6 +701,9 @@ func (d *DefaultEngineBuilder) buildVirtualHosts(g *k8s.IngressGroup) (*builders
 		if err != nil {
 			return nil, nil, fmt.Errorf("error getting vhOpts: %w", err)
 		}
+		if securityProfileID != "" {
+			vhOpts.SecurityProfileID = securityProfileID
+		}
 
 		tlsVHBuilder.SetOpts(vhOpts, routeOpts, ing.Namespace)
 		httpVHBuilder.SetOpts(vhOpts, routeOpts, ing.Namespace)
@@ -420,6 +718,11 @@ func (d *DefaultEngineBuilder) buildVirtualHosts(g *k8s.IngressGroup) (*builders
 			return nil, nil, fmt.Errorf("error getting redirectActions: %w", err)
 		}
 
+		grpcStatusResponseActions, err := d.grpcStatusResponses(ing)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error getting grpcStatusResponseActions: %w", err)
+		}
+
 		for _, rule := range ing.Spec.Rules {
 			if rule.HTTP == nil {
 				continue
@@ -431,7 +734,16 @@ func (d *DefaultEngineBuilder) buildVirtualHosts(g *k8s.IngressGroup) (*builders
 					return nil, nil, fmt.Errorf("error getting host and path: %w", err)
 				}
 
-				err = handleBackend(ing.Namespace, path.Backend, hp, k8s.IsTLS(hp.Host, ing.Spec.TLS), routeOpts.BackendType, directResponseActions, redirectActions)
+				var canary *canaryBackend
+				if c, ok := canaries[hp]; ok {
+					if c.ns != ing.Namespace {
+						return nil, nil, fmt.Errorf("canary ingress for host %s and path %s must be in namespace %s", hp.Host, hp.Path, ing.Namespace)
+					}
+					canary = &c
+					usedCanaries[hp] = struct{}{}
+				}
+
+				err = handleBackend(ing.Namespace, path.Backend, hp, k8s.IsTLS(hp.Host, ing.Spec.TLS), routeOpts.BackendType, directResponseActions, redirectActions, grpcStatusResponseActions, canary)
 				if err != nil {
 					return nil, nil, fmt.Errorf("error handling backend: %w", err)
 				}
@@ -439,6 +751,12 @@ func (d *DefaultEngineBuilder) buildVirtualHosts(g *k8s.IngressGroup) (*builders
 		}
 	}
 
+	for hp := range canaries {
+		if _, ok := usedCanaries[hp]; !ok {
+			return nil, nil, fmt.Errorf("primary ingress for canary with host %s and path %s not found", hp.Host, hp.Path)
+		}
+	}
+
 	if ingWithDefaultBackend != nil {
 		ing := *ingWithDefaultBackend
 
@@ -450,6 +768,9 @@ func (d *DefaultEngineBuilder) buildVirtualHosts(g *k8s.IngressGroup) (*builders
 		if err != nil {
 			return nil, nil, fmt.Errorf("error getting vhOpts: %w", err)
 		}
+		if securityProfileID != "" {
+			vhOpts.SecurityProfileID = securityProfileID
+		}
 
 		tlsVHBuilder.SetOpts(vhOpts, routeOpts, ing.Namespace)
 		httpVHBuilder.SetOpts(vhOpts, routeOpts, ing.Namespace)
@@ -464,6 +785,11 @@ func (d *DefaultEngineBuilder) buildVirtualHosts(g *k8s.IngressGroup) (*builders
 			return nil, nil, fmt.Errorf("error getting redirectActions: %w", err)
 		}
 
+		grpcStatusResponseActions, err := d.grpcStatusResponses(ing)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error getting grpcStatusResponseActions: %w", err)
+		}
+
 		// handlers with pats, matching all the requests, with hosts specified in ingresses
 		for host := range httpVHBuilder.GetHosts() {
 			hp := builders.HostAndPath{
@@ -472,7 +798,7 @@ func (d *DefaultEngineBuilder) buildVirtualHosts(g *k8s.IngressGroup) (*builders
 				PathType: string(networking.PathTypePrefix),
 			}
 
-			err = handleBackend(ing.Namespace, *ing.Spec.DefaultBackend, hp, k8s.IsTLS(host, ing.Spec.TLS), routeOpts.BackendType, directResponseActions, redirectActions)
+			err = handleBackend(ing.Namespace, *ing.Spec.DefaultBackend, hp, k8s.IsTLS(host, ing.Spec.TLS), routeOpts.BackendType, directResponseActions, redirectActions, grpcStatusResponseActions, nil)
 			if err != nil {
 				return nil, nil, fmt.Errorf("error handling backend: %w", err)
 			}
@@ -484,24 +810,148 @@ func (d *DefaultEngineBuilder) buildVirtualHosts(g *k8s.IngressGroup) (*builders
 			Path:     "/",
 			PathType: string(networking.PathTypePrefix),
 		}
-		err = handleBackend(ing.Namespace, *ing.Spec.DefaultBackend, hp, k8s.IsTLS("*", ing.Spec.TLS), routeOpts.BackendType, directResponseActions, redirectActions)
+		err = handleBackend(ing.Namespace, *ing.Spec.DefaultBackend, hp, k8s.IsTLS("*", ing.Spec.TLS), routeOpts.BackendType, directResponseActions, redirectActions, grpcStatusResponseActions, nil)
 		if err != nil {
 			return nil, nil, fmt.Errorf("error handling backend: %w", err)
 		}
 	}
 
+	if redirectHTTPToHTTPS {
+		// HTTP router is still filled in to collect hosts for default backend, but it's never deployed
+		return nil, tlsVHBuilder.Build(), nil
+	}
 	return httpVHBuilder.Build(), tlsVHBuilder.Build(), nil
 }
 
+// canaryBackend is a service port of canary ingress receiving a part of traffic of the primary route
+type canaryBackend struct {
+	ns, svcName string
+	port        int32
+}
+
+func (d *DefaultEngineBuilder) canaryBackends(g *k8s.IngressGroup) (map[builders.HostAndPath]canaryBackend, error) {
+	ret := make(map[builders.HostAndPath]canaryBackend)
+	for _, ing := range g.Items {
+		annotations := ing.GetAnnotations()
+		opts, err := d.resolvers.Canary().Resolve(annotations[k8s.Canary], annotations[k8s.CanaryWeight])
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve canary for ingress %s/%s: %w", ing.Namespace, ing.Name, err)
+		}
+		if !opts.Enabled {
+			continue
+		}
+		if ing.Spec.DefaultBackend != nil {
+			return nil, fmt.Errorf("default backend is not supported for canary ingress %s/%s", ing.Namespace, ing.Name)
+		}
+		if opts.Weight == 0 {
+			continue
+		}
+
+		backends, err := builders.ServiceBackendsByHostAndPath(ing)
+		if err != nil {
+			return nil, fmt.Errorf("error getting host and path: %w", err)
+		}
+		for hp, backend := range backends {
+			if _, ok := ret[hp]; ok {
+				return nil, fmt.Errorf("several canary ingresses for host %s and path %s", hp.Host, hp.Path)
+			}
+			port, err := d.serviceBackendPort(ing.Namespace, backend)
+			if err != nil {
+				return nil, err
+			}
+			ret[hp] = canaryBackend{ns: ing.Namespace, svcName: backend.Name, port: port}
+		}
+	}
+	return ret, nil
+}
+
+// serviceBackendPort returns port of targets of the service port, which backend groups of the service are named by
+func (d *DefaultEngineBuilder) serviceBackendPort(ns string, backend networking.IngressServiceBackend) (int32, error) {
+	if d.k8scli == nil {
+		// builder of Validator, services may be created after ingresses and are checked on reconciliation
+		return 0, nil
+	}
+
+	var svc v1.Service
+	err := d.k8scli.Get(context.Background(), types.NamespacedName{
+		Name:      backend.Name,
+		Namespace: ns,
+	}, &svc)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get service: %w", err)
+	}
+
+	for _, p := range svc.Spec.Ports {
+		if p.Name == backend.Port.Name || p.Port == backend.Port.Number {
+			return k8s.BackendPort(context.Background(), d.k8scli, &svc, p)
+		}
+	}
+	return 0, fmt.Errorf("failed to find port for service: %s", backend.Name)
+}
+
 func (d *DefaultEngineBuilder) buildSNIMatches(ctx context.Context, g *k8s.IngressGroup, opts builders.HandlerOptions) ([]*apploadbalancer.SniMatch, error) {
 	b := d.factory.HandlerBuilder(g.Tag)
 	b.AddHandlerOptions(opts)
 
+	checkedCertIDs := make(map[string]struct{})
+	addExistingCertificate := func(hosts []string, certID string) error {
+		if _, ok := checkedCertIDs[certID]; !ok {
+			if err := d.checkExistingCertificate(ctx, certID); err != nil {
+				return err
+			}
+			checkedCertIDs[certID] = struct{}{}
+		}
+		b.AddCertificate(hosts, certID)
+		return nil
+	}
+
 	for _, ing := range g.Items {
+		lockboxVersions, err := k8s.PinnedLockboxVersions(ing)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get lockbox secret versions of ingress %s/%s: %w", ing.Namespace, ing.Name, err)
+		}
+
+		hostCertIDs, err := k8s.HostCertificateIDs(ing)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get certificate ids of ingress %s/%s: %w", ing.Namespace, ing.Name, err)
+		}
 		for _, tls := range ing.Spec.TLS {
+			for _, host := range tls.Hosts {
+				if certID, ok := hostCertIDs[host]; ok {
+					if err = addExistingCertificate([]string{host}, certID); err != nil {
+						return nil, err
+					}
+				}
+			}
+		}
+
+		for _, tls := range ing.Spec.TLS {
+			if tls.SecretName == "" {
+				// hosts are served over TLS with certificates specified by other items of the group
+				continue
+			}
+
 			if strings.HasPrefix(tls.SecretName, k8s.CertIDPrefix) {
 				certID := strings.TrimPrefix(tls.SecretName, k8s.CertIDPrefix)
-				b.AddCertificate(tls.Hosts, certID)
+				if err = addExistingCertificate(tls.Hosts, certID); err != nil {
+					return nil, err
+				}
+				continue
+			}
+
+			if k8s.IsLockboxSecret(tls.SecretName) {
+				// certificate is synced from lockbox by lockbox controller
+				secretID := strings.TrimPrefix(tls.SecretName, k8s.LockboxSecretIDPrefix)
+				certName := d.names.LockboxCertificate(secretID, lockboxVersions[secretID])
+				cert, err := d.certRepo.LoadCertificate(ctx, certName)
+				if err != nil {
+					return nil, fmt.Errorf("error loading certificate: %w", err)
+				}
+				if cert == nil {
+					return nil, fmt.Errorf("there is no (yet?) certificate for lockbox secret %s in cloud with name: %s", secretID, certName)
+				}
+
+				b.AddCertificate(tls.Hosts, cert.Id)
 				continue
 			}
 
@@ -526,6 +976,23 @@ func (d *DefaultEngineBuilder) buildSNIMatches(ctx context.Context, g *k8s.Ingre
 	return b.Build(), nil
 }
 
+// checkExistingCertificate checks that certificate not managed by the controller can be served by balancer,
+// managed certificates being validated are waited for
+func (d *DefaultEngineBuilder) checkExistingCertificate(ctx context.Context, certID string) error {
+	cert, err := d.certRepo.GetCertificate(ctx, certID)
+	if err != nil {
+		return fmt.Errorf("error loading certificate: %w", err)
+	}
+
+	switch cert.Status {
+	case certificatemanager.Certificate_VALIDATING:
+		return ycerrors.ResourceNotReadyError{ResourceType: "Certificate", Name: certID}
+	case certificatemanager.Certificate_INVALID, certificatemanager.Certificate_REVOKED:
+		return fmt.Errorf("certificate %s can't be used, its status is %s", certID, cert.Status)
+	}
+	return nil
+}
+
 func (d *DefaultEngineBuilder) buildBalancer(handler *apploadbalancer.HttpHandler, matches []*apploadbalancer.SniMatch, logOpts *apploadbalancer.LogOptions,
 	tag string, opts builders.Options,
 ) *apploadbalancer.LoadBalancer {
@@ -533,8 +1000,16 @@ func (d *DefaultEngineBuilder) buildBalancer(handler *apploadbalancer.HttpHandle
 	return b.Build(handler, matches, logOpts, opts)
 }
 
-func (d *DefaultEngineBuilder) buildLogOptions(settings *v1alpha1.IngressGroupSettings) *apploadbalancer.LogOptions {
+func (d *DefaultEngineBuilder) buildDefaultRBAC(settings *v1alpha1.IngressGroupSettings) (*apploadbalancer.RBAC, error) {
 	if settings == nil {
+		return nil, nil
+	}
+
+	return builders.BuildRBAC(settings.RBAC)
+}
+
+func (d *DefaultEngineBuilder) buildLogOptions(settings *v1alpha1.IngressGroupSettings) *apploadbalancer.LogOptions {
+	if settings == nil || settings.LogOptions == nil {
 		return nil
 	}
 
@@ -0,0 +1,362 @@
+package reconcile
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yandex-cloud/go-genproto/yandex/cloud/certificatemanager/v1"
+	v1 "k8s.io/api/core/v1"
+	networking "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/yandex-cloud/yc-alb-ingress-controller/api/v1alpha1"
+	"github.com/yandex-cloud/yc-alb-ingress-controller/pkg/builders"
+	ycerrors "github.com/yandex-cloud/yc-alb-ingress-controller/pkg/errors"
+	"github.com/yandex-cloud/yc-alb-ingress-controller/pkg/k8s"
+	"github.com/yandex-cloud/yc-alb-ingress-controller/pkg/metadata"
+	"github.com/yandex-cloud/yc-alb-ingress-controller/pkg/yc"
+)
+
+func TestDefaultEngineBuilder_CustomListeners(t *testing.T) {
+	streamListeners := []builders.StreamListener{{Port: 5432, BackendGroupID: "bg"}}
+	for _, tc := range []struct {
+		desc      string
+		listeners []v1alpha1.Listener
+		exp       []builders.CustomListener
+		wantErr   bool
+	}{
+		{
+			desc: "OK",
+			listeners: []v1alpha1.Listener{
+				{Port: 8080, Protocol: "HTTP"},
+				{Port: 8081, Protocol: "HTTP", Router: "TLS"},
+				{Port: 8443, Protocol: "HTTPS"},
+			},
+			exp: []builders.CustomListener{
+				{Port: 8080},
+				{Port: 8081, TLSRouter: true},
+				{Port: 8443, TLS: true},
+			},
+		},
+		{
+			desc:      "default port",
+			listeners: []v1alpha1.Listener{{Port: 443, Protocol: "HTTPS"}},
+			wantErr:   true,
+		},
+		{
+			desc:      "stream listener port",
+			listeners: []v1alpha1.Listener{{Port: 5432, Protocol: "HTTP"}},
+			wantErr:   true,
+		},
+		{
+			desc:      "duplicate port",
+			listeners: []v1alpha1.Listener{{Port: 8080, Protocol: "HTTP"}, {Port: 8080, Protocol: "HTTPS"}},
+			wantErr:   true,
+		},
+		{
+			desc:      "unknown protocol",
+			listeners: []v1alpha1.Listener{{Port: 8080, Protocol: "TCP"}},
+			wantErr:   true,
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			d := &DefaultEngineBuilder{}
+			ret, err := d.customListeners(&v1alpha1.IngressGroupSettings{Listeners: tc.listeners}, streamListeners)
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.exp, ret)
+		})
+	}
+}
+
+func TestDefaultEngineBuilder_BuildVirtualHosts_RedirectHTTPToHTTPS(t *testing.T) {
+	pathType := networking.PathTypePrefix
+	rule := func(host string) networking.IngressRule {
+		return networking.IngressRule{
+			Host: host,
+			IngressRuleValue: networking.IngressRuleValue{HTTP: &networking.HTTPIngressRuleValue{
+				Paths: []networking.HTTPIngressPath{{
+					Path:     "/",
+					PathType: &pathType,
+					Backend: networking.IngressBackend{
+						Resource: &v1.TypedLocalObjectReference{Kind: "DirectResponse", Name: "ok"},
+					},
+				}},
+			}},
+		}
+	}
+	g := &k8s.IngressGroup{
+		Tag: "tag",
+		Items: []networking.Ingress{{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "default",
+				Name:      "ingress",
+				Annotations: map[string]string{
+					k8s.RedirectHTTPToHTTPS:         "true",
+					k8s.DirectResponsePrefix + "ok": "status=200,body=ok",
+				},
+			},
+			Spec: networking.IngressSpec{
+				TLS:   []networking.IngressTLS{{Hosts: []string{"secure.example.com"}, SecretName: "secret"}},
+				Rules: []networking.IngressRule{rule("secure.example.com"), rule("plain.example.com")},
+			},
+		}},
+	}
+
+	names := &metadata.Names{ClusterID: "my-cluster"}
+	d := &DefaultEngineBuilder{
+		factory:   builders.NewFactory("my-folder", "", names, &metadata.Labels{ClusterID: "my-cluster"}, nil, nil),
+		resolvers: builders.NewResolvers(nil),
+		names:     names,
+	}
+
+	redirect, err := d.redirectHTTPToHTTPS(g, nil, nil)
+	require.NoError(t, err)
+	require.True(t, redirect)
+
+	httpRouter, tlsRouter, err := d.buildVirtualHosts(g, nil, "", redirect)
+	require.NoError(t, err)
+	assert.False(t, httpRouter.HasVirtualHosts())
+
+	var hosts []string
+	for _, vh := range tlsRouter.Router.VirtualHosts {
+		hosts = append(hosts, vh.Authority...)
+	}
+	assert.ElementsMatch(t, []string{"secure.example.com", "plain.example.com"}, hosts)
+}
+
+func TestDefaultEngineBuilder_HandlerOptions(t *testing.T) {
+	group := func(annotations ...map[string]string) *k8s.IngressGroup {
+		g := &k8s.IngressGroup{Tag: "tag"}
+		for _, a := range annotations {
+			g.Items = append(g.Items, networking.Ingress{ObjectMeta: metav1.ObjectMeta{Annotations: a}})
+		}
+		return g
+	}
+	for _, tc := range []struct {
+		desc     string
+		g        *k8s.IngressGroup
+		settings *v1alpha1.IngressGroupSettings
+		exp      builders.HandlerOptions
+		warnings []string
+		wantErr  bool
+	}{
+		{
+			desc: "annotations",
+			g: group(
+				map[string]string{k8s.HTTP2: "true", k8s.HTTP2MaxConcurrentStreams: "100"},
+				map[string]string{k8s.HTTP2: "true"},
+			),
+			exp: builders.HandlerOptions{HTTP2: true, HTTP2MaxConcurrentStreams: 100},
+		},
+		{
+			desc: "conflicting annotations",
+			g: group(
+				map[string]string{k8s.HTTP2MaxConcurrentStreams: "100", k8s.HTTP2: "true"},
+				map[string]string{k8s.HTTP2MaxConcurrentStreams: "200"},
+			),
+			exp:      builders.HandlerOptions{HTTP2: true, HTTP2MaxConcurrentStreams: 100},
+			warnings: []string{k8s.WarningConflictingAnnotation},
+		},
+		{
+			desc:    "http2 with allow http10",
+			g:       group(map[string]string{k8s.HTTP2: "true", k8s.AllowHTTP10: "true"}),
+			wantErr: true,
+		},
+		{
+			desc: "settings override annotations",
+			g:    group(map[string]string{k8s.AllowHTTP10: "true"}),
+			settings: &v1alpha1.IngressGroupSettings{ProtocolSettings: &v1alpha1.ProtocolSettings{
+				HTTP2: &v1alpha1.HTTP2Options{MaxConcurrentStreams: 10},
+			}},
+			exp:      builders.HandlerOptions{HTTP2: true, HTTP2MaxConcurrentStreams: 10},
+			warnings: []string{k8s.WarningOverriddenBySettings},
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			d := &DefaultEngineBuilder{resolvers: builders.NewResolvers(nil)}
+			conflicts := &groupConflicts{}
+			ret, err := d.handlerOptions(tc.g, tc.settings, conflicts)
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.exp, ret)
+
+			var reasons []string
+			for _, w := range conflicts.result() {
+				reasons = append(reasons, w.Reason)
+			}
+			assert.Equal(t, tc.warnings, reasons)
+		})
+	}
+}
+
+func TestDefaultEngineBuilder_GroupSettingsPrecedence(t *testing.T) {
+	g := &k8s.IngressGroup{Tag: "tag", Items: []networking.Ingress{
+		{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "a", Annotations: map[string]string{
+			k8s.ExternalIPv4Address:    "1.2.3.4",
+			k8s.AutoscalingMaxSize:     "10",
+			k8s.RedirectHTTPToHTTPS:    "false",
+			k8s.SecurityProfileID:      "profile-a",
+			k8s.AutoscalingMinZoneSize: "2",
+		}}},
+		{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "b", Annotations: map[string]string{
+			k8s.ExternalIPv4Address: "5.6.7.8",
+		}}},
+	}}
+	d := &DefaultEngineBuilder{resolvers: builders.NewResolvers(nil)}
+
+	t.Run("annotations", func(t *testing.T) {
+		conflicts := &groupConflicts{}
+		addrs, err := d.addresses(g, nil, builders.AddressParams{}, conflicts)
+		require.NoError(t, err)
+		require.Len(t, addrs, 1)
+		assert.Equal(t, "1.2.3.4", addrs[0].GetExternalIpv4Address().GetAddress())
+		assert.Equal(t, "", d.securityProfileID(g, nil, conflicts))
+
+		require.Len(t, conflicts.result(), 1)
+		w := conflicts.result()[0]
+		assert.Equal(t, k8s.WarningConflictingAnnotation, w.Reason)
+		assert.Equal(t, "b", w.Ingress.Name)
+	})
+
+	t.Run("settings", func(t *testing.T) {
+		maxSize, redirect := int64(20), true
+		settings := &v1alpha1.IngressGroupSettings{
+			Addresses:           &v1alpha1.Addresses{ExternalIPv4: "auto"},
+			AutoScale:           &v1alpha1.AutoScale{MaxSize: &maxSize},
+			RedirectHTTPToHTTPS: &redirect,
+			SecurityProfileID:   "profile",
+		}
+		conflicts := &groupConflicts{}
+
+		addrs, err := d.addresses(g, settings, builders.AddressParams{}, conflicts)
+		require.NoError(t, err)
+		require.Len(t, addrs, 1)
+		assert.Equal(t, "", addrs[0].GetExternalIpv4Address().GetAddress())
+
+		policy, err := d.autoScalePolicy(g, settings, conflicts)
+		require.NoError(t, err)
+		assert.Equal(t, int64(20), policy.MaxSize)
+		assert.Equal(t, int64(2), policy.MinZoneSize)
+
+		redirectHTTP, err := d.redirectHTTPToHTTPS(g, settings, conflicts)
+		require.NoError(t, err)
+		assert.True(t, redirectHTTP)
+		assert.Equal(t, "profile", d.securityProfileID(g, settings, conflicts))
+
+		var overridden []string
+		for _, w := range conflicts.result() {
+			assert.Equal(t, k8s.WarningOverriddenBySettings, w.Reason)
+			overridden = append(overridden, w.Ingress.Name)
+		}
+		assert.Equal(t, []string{"a", "b", "a", "a", "a"}, overridden)
+	})
+}
+
+type fakeCertRepo struct {
+	yc.CertRepo
+	certs map[string]*certificatemanager.Certificate
+}
+
+func (r *fakeCertRepo) GetCertificate(_ context.Context, id string) (*certificatemanager.Certificate, error) {
+	return r.certs[id], nil
+}
+
+func TestDefaultEngineBuilder_BuildSNIMatches_ExistingCertificates(t *testing.T) {
+	ingress := func(annotations map[string]string, tls ...networking.IngressTLS) networking.Ingress {
+		return networking.Ingress{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "ingress", Annotations: annotations},
+			Spec:       networking.IngressSpec{TLS: tls},
+		}
+	}
+	certRepo := &fakeCertRepo{certs: map[string]*certificatemanager.Certificate{
+		"cert-issued":     {Id: "cert-issued", Status: certificatemanager.Certificate_ISSUED},
+		"cert-managed":    {Id: "cert-managed", Status: certificatemanager.Certificate_ISSUED, Type: certificatemanager.CertificateType_MANAGED},
+		"cert-validating": {Id: "cert-validating", Status: certificatemanager.Certificate_VALIDATING},
+		"cert-revoked":    {Id: "cert-revoked", Status: certificatemanager.Certificate_REVOKED},
+	}}
+
+	names := &metadata.Names{ClusterID: "my-cluster"}
+	d := &DefaultEngineBuilder{
+		factory:  builders.NewFactory("my-folder", "", names, &metadata.Labels{ClusterID: "my-cluster"}, nil, nil),
+		names:    names,
+		certRepo: certRepo,
+	}
+
+	t.Run("OK", func(t *testing.T) {
+		g := &k8s.IngressGroup{Tag: "tag", Items: []networking.Ingress{ingress(
+			map[string]string{k8s.CertificateIDs: "managed.example.com=cert-managed"},
+			networking.IngressTLS{Hosts: []string{"managed.example.com"}},
+			networking.IngressTLS{Hosts: []string{"example.com"}, SecretName: k8s.CertIDPrefix + "cert-issued"},
+		)}}
+		matches, err := d.buildSNIMatches(context.Background(), g, builders.HandlerOptions{})
+		require.NoError(t, err)
+		require.Len(t, matches, 2)
+		assert.Equal(t, []string{"managed.example.com"}, matches[0].ServerNames)
+		assert.Equal(t, []string{"cert-managed"}, matches[0].Handler.CertificateIds)
+		assert.Equal(t, []string{"example.com"}, matches[1].ServerNames)
+		assert.Equal(t, []string{"cert-issued"}, matches[1].Handler.CertificateIds)
+	})
+
+	t.Run("certificate being validated", func(t *testing.T) {
+		g := &k8s.IngressGroup{Tag: "tag", Items: []networking.Ingress{ingress(
+			map[string]string{k8s.CertificateIDs: "example.com=cert-validating"},
+			networking.IngressTLS{Hosts: []string{"example.com"}},
+		)}}
+		_, err := d.buildSNIMatches(context.Background(), g, builders.HandlerOptions{})
+		assert.ErrorAs(t, err, &ycerrors.ResourceNotReadyError{})
+	})
+
+	t.Run("revoked certificate", func(t *testing.T) {
+		g := &k8s.IngressGroup{Tag: "tag", Items: []networking.Ingress{ingress(
+			nil,
+			networking.IngressTLS{Hosts: []string{"example.com"}, SecretName: k8s.CertIDPrefix + "cert-revoked"},
+		)}}
+		_, err := d.buildSNIMatches(context.Background(), g, builders.HandlerOptions{})
+		assert.Error(t, err)
+	})
+
+	t.Run("host not listed in tls", func(t *testing.T) {
+		g := &k8s.IngressGroup{Tag: "tag", Items: []networking.Ingress{ingress(
+			map[string]string{k8s.CertificateIDs: "other.example.com=cert-issued"},
+			networking.IngressTLS{Hosts: []string{"example.com"}},
+		)}}
+		_, err := d.buildSNIMatches(context.Background(), g, builders.HandlerOptions{})
+		assert.Error(t, err)
+	})
+}
+
+func TestDefaultEngineBuilder_StructuredActionAnnotations(t *testing.T) {
+	ing := networking.Ingress{ObjectMeta: metav1.ObjectMeta{
+		Namespace: "default",
+		Name:      "ingress",
+		Annotations: map[string]string{
+			k8s.RedirectPrefix + "legacy":   "path=replace_prefix,replace_prefix=/v2,response_code=FOUND",
+			k8s.RedirectPrefix + "query":    `{"path": "replace_path", "replace_path": "/search?q=a,b", "response_code": "FOUND"}`,
+			k8s.DirectResponsePrefix + "ok": "status: 200\nbody: \"key=value, other=value\"\n",
+		},
+	}}
+	d := &DefaultEngineBuilder{resolvers: builders.NewResolvers(nil)}
+
+	redirects, err := d.redirects(ing)
+	require.NoError(t, err)
+	assert.Equal(t, "/v2", redirects["legacy"].GetReplacePrefix())
+	assert.Equal(t, "/search?q=a,b", redirects["query"].GetReplacePath())
+
+	responses, err := d.directResponses(ing)
+	require.NoError(t, err)
+	assert.Equal(t, int64(200), responses["ok"].Status)
+	assert.Equal(t, "key=value, other=value", responses["ok"].Body.GetText())
+
+	ing.Annotations[k8s.DirectResponsePrefix+"ok"] = `{"status": {"code": 200}}`
+	_, err = d.directResponses(ing)
+	assert.ErrorContains(t, err, `"status"`)
+}
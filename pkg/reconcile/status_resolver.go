@@ -1,6 +1,8 @@
 package reconcile
 
 import (
+	"sort"
+
 	"github.com/yandex-cloud/go-genproto/yandex/cloud/apploadbalancer/v1"
 	v1 "k8s.io/api/core/v1"
 	networking "k8s.io/api/networking/v1"
@@ -29,14 +31,26 @@ func (r *IngressStatusResolver) Resolve(alb *apploadbalancer.LoadBalancer) netwo
 			}
 		}
 	}
-	for ip, portSet := range statusMap {
-		var ports []networking.IngressPortStatus
+	// listeners may be on arbitrary ports, sort them so that the status doesn't change from one reconcile to another
+	ips := make([]string, 0, len(statusMap))
+	for ip := range statusMap {
+		ips = append(ips, ip)
+	}
+	sort.Strings(ips)
+	for _, ip := range ips {
+		portSet := statusMap[ip]
+		ports := make([]int32, 0, len(portSet))
 		for port := range portSet {
-			ports = append(ports, networking.IngressPortStatus{Port: port, Protocol: v1.ProtocolTCP})
+			ports = append(ports, port)
+		}
+		sort.Slice(ports, func(i, j int) bool { return ports[i] < ports[j] })
+		var portStatuses []networking.IngressPortStatus
+		for _, port := range ports {
+			portStatuses = append(portStatuses, networking.IngressPortStatus{Port: port, Protocol: v1.ProtocolTCP})
 		}
 		statusIngresses = append(statusIngresses, networking.IngressLoadBalancerIngress{
 			IP:    ip,
-			Ports: ports,
+			Ports: portStatuses,
 		})
 	}
 	return networking.IngressStatus{LoadBalancer: networking.IngressLoadBalancerStatus{Ingress: statusIngresses}}
@@ -0,0 +1,49 @@
+package reconcile
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yandex-cloud/go-genproto/yandex/cloud/apploadbalancer/v1"
+	v1 "k8s.io/api/core/v1"
+	networking "k8s.io/api/networking/v1"
+)
+
+func TestIngressStatusResolver_Resolve(t *testing.T) {
+	address := func(ip string) *apploadbalancer.Address {
+		return &apploadbalancer.Address{Address: &apploadbalancer.Address_ExternalIpv4Address{
+			ExternalIpv4Address: &apploadbalancer.ExternalIpv4Address{Address: ip},
+		}}
+	}
+	listener := func(port int64, ips ...string) *apploadbalancer.Listener {
+		var addresses []*apploadbalancer.Address
+		for _, ip := range ips {
+			addresses = append(addresses, address(ip))
+		}
+		return &apploadbalancer.Listener{Endpoints: []*apploadbalancer.Endpoint{{Addresses: addresses, Ports: []int64{port}}}}
+	}
+
+	alb := &apploadbalancer.LoadBalancer{Listeners: []*apploadbalancer.Listener{
+		listener(443, "10.0.0.2", "10.0.0.1"),
+		listener(80, "10.0.0.2", "10.0.0.1"),
+		listener(8443, "10.0.0.1"),
+		listener(8080, "10.0.0.1"),
+	}}
+
+	ports := func(ports ...int32) []networking.IngressPortStatus {
+		var ret []networking.IngressPortStatus
+		for _, p := range ports {
+			ret = append(ret, networking.IngressPortStatus{Port: p, Protocol: v1.ProtocolTCP})
+		}
+		return ret
+	}
+	exp := networking.IngressStatus{LoadBalancer: networking.IngressLoadBalancerStatus{Ingress: []networking.IngressLoadBalancerIngress{
+		{IP: "10.0.0.1", Ports: ports(80, 443, 8080, 8443)},
+		{IP: "10.0.0.2", Ports: ports(80, 443)},
+	}}}
+
+	r := &IngressStatusResolver{}
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, exp, r.Resolve(alb))
+	}
+}
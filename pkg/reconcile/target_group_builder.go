@@ -7,13 +7,16 @@ import (
 
 	"github.com/yandex-cloud/go-genproto/yandex/cloud/apploadbalancer/v1"
 	"github.com/yandex-cloud/go-genproto/yandex/cloud/compute/v1"
+	"github.com/yandex-cloud/go-genproto/yandex/cloud/vpc/v1"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	v1 "k8s.io/api/core/v1"
 	discovery "k8s.io/api/discovery/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/yandex-cloud/yc-alb-ingress-controller/pkg/algo"
 	"github.com/yandex-cloud/yc-alb-ingress-controller/pkg/k8s"
 	"github.com/yandex-cloud/yc-alb-ingress-controller/pkg/metadata"
 	"github.com/yandex-cloud/yc-alb-ingress-controller/pkg/yc"
@@ -41,23 +44,55 @@ func NewTargetGroupBuilder(folderID string, cli client.Client, names *metadata.N
 	}
 }
 
-func (t *TargetGroupBuilder) Build(ctx context.Context, svc types.NamespacedName, suitableSubnets []string) (*apploadbalancer.TargetGroup, error) {
-	nodeNames, err := t.getServiceNodeNames(ctx, svc)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get service node names: %w", err)
-	}
-
+// Build builds target group of the service from nodes or pods serving it. Targets are placed in the subnets
+// listed by the service annotation, or in the suitable subnets of the network otherwise.
+func (t *TargetGroupBuilder) Build(ctx context.Context, svc types.NamespacedName, suitableSubnets []*vpc.Subnet) (*apploadbalancer.TargetGroup, error) {
 	var k8ssvc v1.Service
-	err = t.cli.Get(ctx, svc, &k8ssvc)
+	err := t.cli.Get(ctx, svc, &k8ssvc)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get service: %w", err)
 	}
-	preferIPv6 := k8ssvc.Annotations[k8s.PreferIPv6Targets] == "true"
 
+	subnetIDs := algo.Map(suitableSubnets, (*vpc.Subnet).GetId)
 	subnetsAnn := k8ssvc.Annotations[k8s.Subnets]
 	if subnetsAnn != "" {
-		suitableSubnets = strings.Split(subnetsAnn, ",")
+		subnetIDs = strings.Split(subnetsAnn, ",")
+	}
+
+	podTargets, err := k8s.PodIPTargets(&k8ssvc)
+	if err != nil {
+		return nil, err
 	}
+	if podTargets {
+		annotated := make(map[string]struct{})
+		for _, id := range subnetIDs {
+			annotated[id] = struct{}{}
+		}
+		subnets := algo.Filter(suitableSubnets, func(s *vpc.Subnet) bool {
+			_, ok := annotated[s.GetId()]
+			return ok
+		})
+
+		targets, err := t.buildPodTargets(ctx, svc, subnets)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build pod targets: %w", err)
+		}
+
+		return &apploadbalancer.TargetGroup{
+			Name:        t.names.TargetGroup(svc),
+			Description: "target group from K8S pods",
+			FolderId:    t.folderID,
+			Labels:      t.labels.Default(),
+			Targets:     targets,
+		}, nil
+	}
+
+	nodeNames, err := t.getServiceNodeNames(ctx, svc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get service node names: %w", err)
+	}
+
+	preferIPv6 := k8ssvc.Annotations[k8s.PreferIPv6Targets] == "true"
 
 	var ret []*apploadbalancer.Target
 	for _, nodeName := range nodeNames {
@@ -88,7 +123,7 @@ func (t *TargetGroupBuilder) Build(ctx context.Context, svc types.NamespacedName
 			return nil, fmt.Errorf("failed to get node internal IPs: %w", err)
 		}
 
-		subnetID, ip, err := yc.SubnetIDForProviderID(instance, suitableIPs, suitableSubnets, preferIPv6)
+		subnetID, ip, err := yc.SubnetIDForProviderID(instance, suitableIPs, subnetIDs, preferIPv6)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get subnet ID for provider ID %s of node %s: %w", node.Spec.ProviderID, node.Name, err)
 		}
@@ -108,6 +143,80 @@ func (t *TargetGroupBuilder) Build(ctx context.Context, svc types.NamespacedName
 	}, nil
 }
 
+// buildPodTargets builds targets from addresses of ready pods found in endpoint slices of the service regardless
+// of useEndpointSlices. Pods with IPs from CIDR blocks of the subnets (e.g. with VPC-native pod networking) are
+// targets in their subnets. Other pod IPs don't belong to any subnet of the network, so their targets are private
+// addresses routed within the network.
+func (t *TargetGroupBuilder) buildPodTargets(ctx context.Context, svc types.NamespacedName, subnets []*vpc.Subnet) ([]*apploadbalancer.Target, error) {
+	var slList discovery.EndpointSliceList
+	err := t.cli.List(ctx, &slList,
+		client.InNamespace(svc.Namespace),
+		client.MatchingLabels{
+			"kubernetes.io/service-name": svc.Name,
+		})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list endpoint slices: %w", err)
+	}
+
+	var ret []*apploadbalancer.Target
+	seen := make(map[string]struct{})
+	for _, sl := range slList.Items {
+		if sl.AddressType != discovery.AddressTypeIPv4 {
+			continue
+		}
+
+		for _, ep := range sl.Endpoints {
+			// nil readiness must be interpreted as ready
+			if ep.Conditions.Ready != nil && !*ep.Conditions.Ready {
+				waits, err := t.waitsForTargetHealth(ctx, svc, ep)
+				if err != nil {
+					return nil, err
+				}
+				if !waits {
+					continue
+				}
+			}
+
+			for _, addr := range ep.Addresses {
+				if _, ok := seen[addr]; ok {
+					continue
+				}
+				seen[addr] = struct{}{}
+
+				subnetID, err := yc.SubnetIDForIP(addr, subnets)
+				if err != nil {
+					return nil, fmt.Errorf("failed to get subnet of pod IP: %w", err)
+				}
+				ret = append(ret, &apploadbalancer.Target{
+					AddressType:        &apploadbalancer.Target_IpAddress{IpAddress: addr},
+					SubnetId:           subnetID,
+					PrivateIpv4Address: subnetID == "",
+				})
+			}
+		}
+	}
+
+	return ret, nil
+}
+
+// waitsForTargetHealth reports whether not ready endpoint is a pod waiting for its readiness gate, which requires
+// the pod to be a target
+func (t *TargetGroupBuilder) waitsForTargetHealth(ctx context.Context, svc types.NamespacedName, ep discovery.Endpoint) (bool, error) {
+	if ep.TargetRef == nil || ep.TargetRef.Kind != "Pod" {
+		return false, nil
+	}
+
+	var pod v1.Pod
+	err := t.cli.Get(ctx, types.NamespacedName{Namespace: ep.TargetRef.Namespace, Name: ep.TargetRef.Name}, &pod)
+	if errors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to get pod: %w", err)
+	}
+	return k8s.WaitsForTargetHealth(&pod, svc.Name), nil
+}
+
 func (t *TargetGroupBuilder) getServiceNodeNames(ctx context.Context, svc types.NamespacedName) ([]string, error) {
 	if t.useEndpointSlices {
 		return t.getServiceNodeNamesFromEndpointsSlice(ctx, svc)
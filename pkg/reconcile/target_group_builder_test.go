@@ -10,11 +10,13 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
+	"github.com/yandex-cloud/yc-alb-ingress-controller/pkg/algo"
 	"github.com/yandex-cloud/yc-alb-ingress-controller/pkg/k8s"
 	"github.com/yandex-cloud/yc-alb-ingress-controller/pkg/metadata"
 
 	"github.com/yandex-cloud/go-genproto/yandex/cloud/apploadbalancer/v1"
 	"github.com/yandex-cloud/go-genproto/yandex/cloud/compute/v1"
+	"github.com/yandex-cloud/go-genproto/yandex/cloud/vpc/v1"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	v1 "k8s.io/api/core/v1"
@@ -72,17 +74,145 @@ func TestServiceTargetGroupBuilder_Build(t *testing.T) {
 		return
 	}
 
+	subnets := func(ids ...string) []*vpc.Subnet {
+		return algo.Map(ids, func(id string) *vpc.Subnet { return &vpc.Subnet{Id: id} })
+	}
+
 	testData := []struct {
 		desc string
 
 		objects         []client.Object
-		suitableSubnets []string
+		suitableSubnets []*vpc.Subnet
 
 		expTargets []*apploadbalancer.Target
 		wantErr    bool
 
 		useEndpointSlices bool
 	}{
+		{
+			desc: "OK, pod IP targets",
+			objects: []client.Object{
+				&v1.Service{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        "svc",
+						Namespace:   "default",
+						Annotations: map[string]string{k8s.TargetMode: k8s.TargetModePodIP},
+					},
+				},
+				&discovery.EndpointSlice{
+					ObjectMeta: metav1.ObjectMeta{
+						Labels: map[string]string{
+							"kubernetes.io/service-name": "svc",
+						},
+						Namespace: "default",
+						Name:      "svc1",
+					},
+					AddressType: discovery.AddressTypeIPv4,
+					Endpoints: []discovery.Endpoint{
+						{
+							Addresses: []string{"10.112.0.10"},
+							NodeName:  ptr.To("cl1mkq03gu56o26iia82-inod"),
+						},
+						{
+							Addresses:  []string{"10.112.0.11"},
+							Conditions: discovery.EndpointConditions{Ready: ptr.To(true)},
+						},
+						{
+							Addresses:  []string{"10.112.0.12"},
+							Conditions: discovery.EndpointConditions{Ready: ptr.To(false)},
+						},
+						{
+							Addresses:  []string{"10.112.0.13"},
+							Conditions: discovery.EndpointConditions{Ready: ptr.To(false)},
+							TargetRef:  &v1.ObjectReference{Kind: "Pod", Namespace: "default", Name: "gated"},
+						},
+					},
+				},
+				&v1.Pod{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "gated"},
+					Spec: v1.PodSpec{
+						ReadinessGates: []v1.PodReadinessGate{{ConditionType: k8s.ReadinessGateConditionType("svc")}},
+					},
+					Status: v1.PodStatus{
+						Conditions: []v1.PodCondition{{Type: v1.ContainersReady, Status: v1.ConditionTrue}},
+					},
+				},
+				&discovery.EndpointSlice{
+					ObjectMeta: metav1.ObjectMeta{
+						Labels: map[string]string{
+							"kubernetes.io/service-name": "svc",
+						},
+						Namespace: "default",
+						Name:      "svc2",
+					},
+					AddressType: discovery.AddressTypeIPv6,
+					Endpoints: []discovery.Endpoint{
+						{
+							Addresses: []string{"fd00::10"},
+						},
+					},
+				},
+			},
+			expTargets: []*apploadbalancer.Target{
+				{
+					AddressType:        &apploadbalancer.Target_IpAddress{IpAddress: "10.112.0.10"},
+					PrivateIpv4Address: true,
+				},
+				{
+					AddressType:        &apploadbalancer.Target_IpAddress{IpAddress: "10.112.0.11"},
+					PrivateIpv4Address: true,
+				},
+				{
+					AddressType:        &apploadbalancer.Target_IpAddress{IpAddress: "10.112.0.13"},
+					PrivateIpv4Address: true,
+				},
+			},
+		},
+		{
+			desc: "OK, pod IP targets in subnets",
+			objects: []client.Object{
+				&v1.Service{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        "svc",
+						Namespace:   "default",
+						Annotations: map[string]string{k8s.TargetMode: k8s.TargetModePodIP},
+					},
+				},
+				&discovery.EndpointSlice{
+					ObjectMeta: metav1.ObjectMeta{
+						Labels: map[string]string{
+							"kubernetes.io/service-name": "svc",
+						},
+						Namespace: "default",
+						Name:      "svc1",
+					},
+					AddressType: discovery.AddressTypeIPv4,
+					Endpoints: []discovery.Endpoint{
+						{Addresses: []string{"10.1.0.5"}},
+						{Addresses: []string{"10.2.0.6"}},
+						{Addresses: []string{"10.112.0.10"}},
+					},
+				},
+			},
+			suitableSubnets: []*vpc.Subnet{
+				{Id: "subnet_1", V4CidrBlocks: []string{"10.1.0.0/16"}},
+				{Id: "subnet_2", V4CidrBlocks: []string{"10.2.0.0/16"}},
+			},
+			expTargets: []*apploadbalancer.Target{
+				{
+					AddressType: &apploadbalancer.Target_IpAddress{IpAddress: "10.1.0.5"},
+					SubnetId:    "subnet_1",
+				},
+				{
+					AddressType: &apploadbalancer.Target_IpAddress{IpAddress: "10.2.0.6"},
+					SubnetId:    "subnet_2",
+				},
+				{
+					AddressType:        &apploadbalancer.Target_IpAddress{IpAddress: "10.112.0.10"},
+					PrivateIpv4Address: true,
+				},
+			},
+		},
 		{
 			desc:              "OK, slices",
 			useEndpointSlices: true,
@@ -150,7 +280,7 @@ func TestServiceTargetGroupBuilder_Build(t *testing.T) {
 					},
 				},
 			},
-			suitableSubnets: []string{"subnet_1", "subnet_2"},
+			suitableSubnets: subnets("subnet_1", "subnet_2"),
 			expTargets: []*apploadbalancer.Target{{
 				AddressType: &apploadbalancer.Target_IpAddress{IpAddress: "192.168.10.30"},
 				SubnetId:    "subnet_1",
@@ -258,7 +388,7 @@ func TestServiceTargetGroupBuilder_Build(t *testing.T) {
 					},
 				},
 			},
-			suitableSubnets: []string{"subnet_2"},
+			suitableSubnets: subnets("subnet_2"),
 			expTargets: []*apploadbalancer.Target{{
 				AddressType: &apploadbalancer.Target_IpAddress{IpAddress: "192.168.10.28"},
 				SubnetId:    "subnet_2",
@@ -321,7 +451,7 @@ func TestServiceTargetGroupBuilder_Build(t *testing.T) {
 					},
 				},
 			},
-			suitableSubnets: []string{"subnet_1", "subnet_2"},
+			suitableSubnets: subnets("subnet_1", "subnet_2"),
 			expTargets: []*apploadbalancer.Target{{
 				AddressType: &apploadbalancer.Target_IpAddress{IpAddress: "192.168.10.30"},
 				SubnetId:    "subnet_1",
@@ -421,7 +551,7 @@ func TestServiceTargetGroupBuilder_Build(t *testing.T) {
 				},
 			},
 
-			suitableSubnets: []string{"subnet_2"},
+			suitableSubnets: subnets("subnet_2"),
 			expTargets: []*apploadbalancer.Target{{
 				AddressType: &apploadbalancer.Target_IpAddress{IpAddress: "192.168.10.28"},
 				SubnetId:    "subnet_2",
@@ -469,7 +599,7 @@ func TestServiceTargetGroupBuilder_Build(t *testing.T) {
 					},
 				},
 			},
-			suitableSubnets: []string{"subnet_2"},
+			suitableSubnets: subnets("subnet_2"),
 			expTargets: []*apploadbalancer.Target{
 				{
 					AddressType: &apploadbalancer.Target_IpAddress{IpAddress: "2001:0db8:0001:0000:0000:0ab9:C0A8:0102"},
@@ -518,7 +648,7 @@ func TestServiceTargetGroupBuilder_Build(t *testing.T) {
 					},
 				},
 			},
-			suitableSubnets: []string{"subnet_2"},
+			suitableSubnets: subnets("subnet_2"),
 			expTargets: []*apploadbalancer.Target{
 				{
 					AddressType: &apploadbalancer.Target_IpAddress{IpAddress: "192.168.10.28"},
@@ -570,7 +700,7 @@ func TestServiceTargetGroupBuilder_Build(t *testing.T) {
 					},
 				},
 			},
-			suitableSubnets: []string{"subnet_2"},
+			suitableSubnets: subnets("subnet_2"),
 			expTargets: []*apploadbalancer.Target{
 				{
 					AddressType: &apploadbalancer.Target_IpAddress{IpAddress: "192.168.10.29"},
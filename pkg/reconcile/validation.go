@@ -0,0 +1,296 @@
+package reconcile
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/yandex-cloud/go-genproto/yandex/cloud/apploadbalancer/v1"
+	networking "k8s.io/api/networking/v1"
+
+	"github.com/yandex-cloud/yc-alb-ingress-controller/api/v1alpha1"
+	"github.com/yandex-cloud/yc-alb-ingress-controller/pkg/builders"
+	"github.com/yandex-cloud/yc-alb-ingress-controller/pkg/k8s"
+	"github.com/yandex-cloud/yc-alb-ingress-controller/pkg/metadata"
+)
+
+// Validator checks ingress groups with the same resolvers and parsers DefaultEngineBuilder uses. Checks requiring
+// services or cloud resources are skipped, so it's able to reject invalid annotations and settings on admission
+// instead of blocking reconciliation of the whole group.
+type Validator struct {
+	d *DefaultEngineBuilder
+}
+
+func NewValidator() *Validator {
+	return &Validator{d: &DefaultEngineBuilder{resolvers: builders.NewResolvers(nil)}}
+}
+
+// ValidateIngressGroup checks the group settings together with valid ingresses of the group. Invalid ingresses are
+// skipped, they are reported on their own admission and reconciliation. Conflicting values of group-wide annotations
+// aren't errors, they are reported as warnings on reconciliation
+func (v *Validator) ValidateIngressGroup(g *k8s.IngressGroup, settings *v1alpha1.IngressGroupSettings) error {
+	return v.validateIngressGroup(v.validItems(g, nil), settings, nil)
+}
+
+// ValidateIngressInGroup checks the ingress and the group with the ingress added or replaced. Only errors introduced by
+// the ingress are reported, i.e. the ingress is rejected if it's invalid, if the group is valid without it only, or if
+// its values of group-wide annotations conflict with the ones of other ingresses. Values overridden by settings are
+// allowed
+func (v *Validator) ValidateIngressInGroup(g *k8s.IngressGroup, ing networking.Ingress, settings *v1alpha1.IngressGroupSettings) error {
+	if err := v.ValidateIngress(ing); err != nil {
+		return fmt.Errorf("invalid ingress %s/%s: %w", ing.Namespace, ing.Name, err)
+	}
+
+	others := v.validItems(g, &ing)
+	group := &k8s.IngressGroup{Tag: g.Tag, Items: append([]networking.Ingress{}, others.Items...)}
+	// the ingress goes last, so values of ingresses admitted before win
+	group.Items = append(group.Items, ing)
+
+	conflicts := &groupConflicts{}
+	if err := v.validateIngressGroup(group, settings, conflicts); err != nil {
+		if v.validateIngressGroup(others, settings, nil) == nil {
+			return err
+		}
+		// the group is already broken by other ingresses or settings, this ingress isn't blamed for it
+		return nil
+	}
+	for _, w := range conflicts.result() {
+		if w.Reason == k8s.WarningConflictingAnnotation && w.Ingress.Namespace == ing.Namespace && w.Ingress.Name == ing.Name {
+			return fmt.Errorf("conflict with ingress group %s: %s", g.Tag, w.Message)
+		}
+	}
+	return nil
+}
+
+// validItems returns the group without invalid ingresses and without the excluded one
+func (v *Validator) validItems(g *k8s.IngressGroup, exclude *networking.Ingress) *k8s.IngressGroup {
+	ret := &k8s.IngressGroup{Tag: g.Tag}
+	for _, item := range g.Items {
+		if exclude != nil && item.Namespace == exclude.Namespace && item.Name == exclude.Name {
+			continue
+		}
+		if v.ValidateIngress(item) == nil {
+			ret.Items = append(ret.Items, item)
+		}
+	}
+	return ret
+}
+
+func (v *Validator) validateIngressGroup(g *k8s.IngressGroup, settings *v1alpha1.IngressGroupSettings, conflicts *groupConflicts) error {
+	var withDefaultBackend int
+	for _, ing := range g.Items {
+		if ing.Spec.DefaultBackend != nil && !k8s.IsCanary(&ing) {
+			withDefaultBackend++
+		}
+	}
+	if withDefaultBackend > 1 {
+		return fmt.Errorf("default backend can be specified only once, ingress-group: %s", g.Tag)
+	}
+
+	conflicts.annotation(g, k8s.GroupSettings, "")
+	// groups having no address yet are not rejected, so their ingresses may be applied one by one
+	if hasAddresses(g, settings) {
+		if _, err := v.d.addresses(g, settings, builders.AddressParams{}, conflicts); err != nil {
+			return fmt.Errorf("failed to build addresses: %w", err)
+		}
+	}
+	if _, err := v.d.autoScalePolicy(g, settings, conflicts); err != nil {
+		return fmt.Errorf("failed to build auto scale policy: %w", err)
+	}
+	if _, err := v.d.handlerOptions(g, settings, conflicts); err != nil {
+		return fmt.Errorf("failed to build handler options: %w", err)
+	}
+	if _, err := v.d.redirectHTTPToHTTPS(g, settings, conflicts); err != nil {
+		return fmt.Errorf("failed to build redirect of http to https: %w", err)
+	}
+
+	streamListeners, err := v.d.resolveStreamListeners(g)
+	if err != nil {
+		return fmt.Errorf("failed to build stream listeners: %w", err)
+	}
+	var listeners []builders.StreamListener
+	for _, l := range streamListeners {
+		listeners = append(listeners, builders.StreamListener{Port: l.Port})
+	}
+	return v.validateSettings(settings, listeners)
+}
+
+// ValidateSettings checks the settings on their own, regardless of ingress groups using them
+func (v *Validator) ValidateSettings(settings *v1alpha1.IngressGroupSettings) error {
+	return v.validateSettings(settings, nil)
+}
+
+func (v *Validator) validateSettings(settings *v1alpha1.IngressGroupSettings, streamListeners []builders.StreamListener) error {
+	if _, err := v.d.customListeners(settings, streamListeners); err != nil {
+		return fmt.Errorf("failed to build custom listeners: %w", err)
+	}
+	if _, err := v.d.buildDefaultRBAC(settings); err != nil {
+		return fmt.Errorf("failed to build rbac from group settings: %w", err)
+	}
+	if settings == nil {
+		return nil
+	}
+	if settings.ProtocolSettings != nil && settings.ProtocolSettings.AllowHTTP10 && settings.ProtocolSettings.HTTP2 != nil {
+		return fmt.Errorf("http2 can't be enabled together with allow http10")
+	}
+	if settings.Addresses != nil && settings.Addresses.InternalSubnetID != "" && settings.Addresses.InternalIPv4 == "" {
+		return fmt.Errorf("subnet provided without internal address")
+	}
+	return nil
+}
+
+// ValidateIngress checks annotations and routes of the ingress on its own
+func (v *Validator) ValidateIngress(ing networking.Ingress) error {
+	canary, err := v.checkAnnotations(ing)
+	if err != nil {
+		return err
+	}
+	if canary {
+		if ing.Spec.DefaultBackend != nil {
+			return fmt.Errorf("default backend is not supported for canary ingress")
+		}
+		for _, rule := range ing.Spec.Rules {
+			if rule.HTTP == nil {
+				continue
+			}
+			for _, path := range rule.HTTP.Paths {
+				if err = checkCanaryBackend(rule.Host, path); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return v.checkRoutes(ing)
+}
+
+// RulesStatus summarizes rules of ingresses of the group by the reason of the group reconciliation outcome. All rules
+// of reconciled groups are accepted, otherwise invalid rules are reported with their own errors and the other ones with
+// the reason of the group
+func (v *Validator) RulesStatus(g *k8s.IngressGroup, reason string) []v1alpha1.IngressRulesStatus {
+	ret := make([]v1alpha1.IngressRulesStatus, 0, len(g.Items))
+	for _, ing := range g.Items {
+		status := v1alpha1.IngressRulesStatus{Namespace: ing.Namespace, Name: ing.Name, ObservedGeneration: ing.Generation}
+		canary, ingErr := v.checkAnnotations(ing)
+		addRule := func(host, path string, check func() error) {
+			rule := v1alpha1.IngressRuleStatus{Host: host, Path: path, Reason: reason}
+			err := ingErr
+			if err == nil {
+				err = check()
+			}
+			switch {
+			case reason == k8s.ReasonReconciliationComplete:
+				rule.Accepted, rule.Reason = true, k8s.ReasonRuleAccepted
+			case err != nil:
+				rule.Reason, rule.Message = k8s.ReasonRuleInvalid, err.Error()
+			}
+			status.Rules = append(status.Rules, rule)
+		}
+
+		if ing.Spec.DefaultBackend != nil {
+			addRule("", "", func() error {
+				if canary {
+					return fmt.Errorf("default backend is not supported for canary ingress")
+				}
+				return v.checkRoutes(withDefaultBackendOnly(ing))
+			})
+		}
+		for _, rule := range ing.Spec.Rules {
+			if rule.HTTP == nil {
+				continue
+			}
+			for _, path := range rule.HTTP.Paths {
+				addRule(rule.Host, path.Path, func() error {
+					if canary {
+						if err := checkCanaryBackend(rule.Host, path); err != nil {
+							return err
+						}
+					}
+					return v.checkRoutes(withPathOnly(ing, rule.Host, path))
+				})
+			}
+		}
+		ret = append(ret, status)
+	}
+	return ret
+}
+
+// checkAnnotations checks annotations which aren't used to build routes and reports whether the ingress is canary
+func (v *Validator) checkAnnotations(ing networking.Ingress) (bool, error) {
+	if err := builders.ValidateBackendOpts(ing.GetAnnotations()); err != nil {
+		return false, fmt.Errorf("error getting backend opts: %w", err)
+	}
+	if _, err := k8s.HostCertificateIDs(ing); err != nil {
+		return false, err
+	}
+	if _, err := k8s.LockboxReferences(ing); err != nil {
+		return false, err
+	}
+
+	annotations := ing.GetAnnotations()
+	canary, err := v.d.resolvers.Canary().Resolve(annotations[k8s.Canary], annotations[k8s.CanaryWeight])
+	if err != nil {
+		return false, fmt.Errorf("failed to resolve canary: %w", err)
+	}
+	return canary.Enabled, nil
+}
+
+// checkRoutes builds virtual hosts of the ingress alone the same way DefaultEngineBuilder does. Services and backend
+// groups aren't looked up, so the check doesn't depend on the order resources are created in. Routes of canary
+// ingresses are checked as if they were primary ones, since they are merged into the routes of other ingresses
+func (v *Validator) checkRoutes(ing networking.Ingress) error {
+	item := ing.DeepCopy()
+	delete(item.Annotations, k8s.Canary)
+
+	names := &metadata.Names{}
+	d := &DefaultEngineBuilder{
+		factory:   builders.NewFactory("", "", names, &metadata.Labels{}, nil, nil),
+		resolvers: v.d.resolvers,
+		names:     names,
+		bgFinder:  anyBackendGroup{},
+	}
+	_, _, err := d.buildVirtualHosts(&k8s.IngressGroup{Tag: "validation", Items: []networking.Ingress{*item}}, nil, "", false)
+	return err
+}
+
+func checkCanaryBackend(host string, path networking.HTTPIngressPath) error {
+	if path.Backend.Service == nil {
+		return fmt.Errorf("canary is supported only for service backends, host %s and path %s", host, path.Path)
+	}
+	return nil
+}
+
+func withDefaultBackendOnly(ing networking.Ingress) networking.Ingress {
+	ret := ing.DeepCopy()
+	ret.Spec.Rules = nil
+	return *ret
+}
+
+func withPathOnly(ing networking.Ingress, host string, path networking.HTTPIngressPath) networking.Ingress {
+	ret := ing.DeepCopy()
+	ret.Spec.DefaultBackend = nil
+	ret.Spec.Rules = []networking.IngressRule{{
+		Host:             host,
+		IngressRuleValue: networking.IngressRuleValue{HTTP: &networking.HTTPIngressRuleValue{Paths: []networking.HTTPIngressPath{path}}},
+	}}
+	return *ret
+}
+
+// anyBackendGroup finds a backend group of any name, backend group resources are checked on their own admission
+type anyBackendGroup struct{}
+
+func (anyBackendGroup) FindBackendGroup(_ context.Context, name string) (*apploadbalancer.BackendGroup, error) {
+	return &apploadbalancer.BackendGroup{Name: name}, nil
+}
+
+func hasAddresses(g *k8s.IngressGroup, settings *v1alpha1.IngressGroupSettings) bool {
+	if settings != nil && settings.Addresses != nil {
+		return true
+	}
+	for _, ing := range g.Items {
+		for _, ann := range []string{k8s.ExternalIPv4Address, k8s.ExternalIPv6Address, k8s.InternalIPv4Address, k8s.InternalALBSubnet} {
+			if ing.GetAnnotations()[ann] != "" {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,294 @@
+package reconcile
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	networking "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/yandex-cloud/yc-alb-ingress-controller/api/v1alpha1"
+	"github.com/yandex-cloud/yc-alb-ingress-controller/pkg/k8s"
+)
+
+func TestValidator_ValidateIngress(t *testing.T) {
+	ingress := func(annotations map[string]string, backend networking.IngressBackend) networking.Ingress {
+		return networking.Ingress{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "ingress", Annotations: annotations},
+			Spec: networking.IngressSpec{
+				Rules: []networking.IngressRule{{
+					Host: "example.com",
+					IngressRuleValue: networking.IngressRuleValue{HTTP: &networking.HTTPIngressRuleValue{
+						Paths: []networking.HTTPIngressPath{{Path: "/", Backend: backend}},
+					}},
+				}},
+			},
+		}
+	}
+	serviceBackend := networking.IngressBackend{Service: &networking.IngressServiceBackend{
+		Name: "svc", Port: networking.ServiceBackendPort{Number: 80},
+	}}
+	resourceBackend := func(kind, name string) networking.IngressBackend {
+		return networking.IngressBackend{Resource: &v1.TypedLocalObjectReference{Kind: kind, Name: name}}
+	}
+
+	for _, tc := range []struct {
+		desc    string
+		ing     networking.Ingress
+		wantErr bool
+	}{
+		{
+			desc: "OK",
+			ing: ingress(map[string]string{
+				k8s.RequestTimeout:                  "10s",
+				k8s.HealthChecks:                    "port=8080,http-path=/healthz",
+				k8s.DirectResponsePrefix + "teapot": "status=418,body=teapot",
+			}, resourceBackend("DirectResponse", "teapot")),
+		},
+		{
+			desc:    "bad timeout",
+			ing:     ingress(map[string]string{k8s.RequestTimeout: "ten seconds"}, serviceBackend),
+			wantErr: true,
+		},
+		{
+			desc:    "bad redirect",
+			ing:     ingress(map[string]string{k8s.RedirectPrefix + "moved": "response_code=MOVED"}, serviceBackend),
+			wantErr: true,
+		},
+		{
+			desc:    "health checks without port",
+			ing:     ingress(map[string]string{k8s.HealthChecks: "http-path=/healthz"}, serviceBackend),
+			wantErr: true,
+		},
+		{
+			desc:    "unknown balancing mode",
+			ing:     ingress(map[string]string{k8s.BalancingMode: "FASTEST"}, serviceBackend),
+			wantErr: true,
+		},
+		{
+			desc:    "direct response not declared",
+			ing:     ingress(nil, resourceBackend("DirectResponse", "teapot")),
+			wantErr: true,
+		},
+		{
+			desc:    "canary of resource backend",
+			ing:     ingress(map[string]string{k8s.Canary: "true"}, resourceBackend("HttpBackendGroup", "bg")),
+			wantErr: true,
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			err := NewValidator().ValidateIngress(tc.ing)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestValidator_ValidateIngressGroup(t *testing.T) {
+	ingress := func(name string, annotations map[string]string) networking.Ingress {
+		return networking.Ingress{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name, Annotations: annotations}}
+	}
+
+	for _, tc := range []struct {
+		desc     string
+		items    []networking.Ingress
+		settings *v1alpha1.IngressGroupSettings
+		wantErr  bool
+	}{
+		{
+			desc: "OK",
+			items: []networking.Ingress{
+				ingress("a", map[string]string{k8s.HTTP2: "true"}),
+				ingress("b", map[string]string{k8s.HTTP2: "true", k8s.StreamListeners: "5432=postgres"}),
+			},
+			settings: &v1alpha1.IngressGroupSettings{Listeners: []v1alpha1.Listener{{Port: 8080, Protocol: "HTTP"}}},
+		},
+		{
+			desc: "conflicting group annotation",
+			items: []networking.Ingress{
+				ingress("a", map[string]string{k8s.RedirectHTTPToHTTPS: "true"}),
+				ingress("b", map[string]string{k8s.RedirectHTTPToHTTPS: "false"}),
+			},
+		},
+		{
+			desc: "different settings",
+			items: []networking.Ingress{
+				ingress("a", map[string]string{k8s.GroupSettings: "settings-a"}),
+				ingress("b", map[string]string{k8s.GroupSettings: "settings-b"}),
+			},
+		},
+		{
+			desc:     "listener on port of stream listener",
+			items:    []networking.Ingress{ingress("a", map[string]string{k8s.StreamListeners: "5432=postgres"})},
+			settings: &v1alpha1.IngressGroupSettings{Listeners: []v1alpha1.Listener{{Port: 5432, Protocol: "HTTP"}}},
+			wantErr:  true,
+		},
+		{
+			desc:  "http2 with allow http10 in settings",
+			items: []networking.Ingress{ingress("a", nil)},
+			settings: &v1alpha1.IngressGroupSettings{ProtocolSettings: &v1alpha1.ProtocolSettings{
+				AllowHTTP10: true,
+				HTTP2:       &v1alpha1.HTTP2Options{},
+			}},
+			wantErr: true,
+		},
+		{
+			desc:     "internal subnet without address in settings",
+			items:    []networking.Ingress{ingress("a", nil)},
+			settings: &v1alpha1.IngressGroupSettings{Addresses: &v1alpha1.Addresses{InternalSubnetID: "subnet"}},
+			wantErr:  true,
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			err := NewValidator().ValidateIngressGroup(&k8s.IngressGroup{Tag: "tag", Items: tc.items}, tc.settings)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestValidator_ValidateIngressInGroup(t *testing.T) {
+	ingress := func(name string, annotations map[string]string) networking.Ingress {
+		return networking.Ingress{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name, Annotations: annotations}}
+	}
+	g := &k8s.IngressGroup{Tag: "tag", Items: []networking.Ingress{
+		ingress("a", map[string]string{k8s.ExternalIPv4Address: "auto", k8s.RedirectHTTPToHTTPS: "true", k8s.GroupSettings: "settings"}),
+		ingress("b", map[string]string{k8s.ExternalIPv4Address: "1.2.3.4"}),
+	}}
+	redirect := true
+
+	for _, tc := range []struct {
+		desc     string
+		ing      networking.Ingress
+		settings *v1alpha1.IngressGroupSettings
+		wantErr  bool
+	}{
+		{
+			desc: "same values",
+			ing:  ingress("c", map[string]string{k8s.ExternalIPv4Address: "auto", k8s.RedirectHTTPToHTTPS: "true"}),
+		},
+		{
+			desc:    "conflicting address",
+			ing:     ingress("c", map[string]string{k8s.ExternalIPv4Address: "5.6.7.8"}),
+			wantErr: true,
+		},
+		{
+			desc:    "conflicting group annotation",
+			ing:     ingress("c", map[string]string{k8s.RedirectHTTPToHTTPS: "false"}),
+			wantErr: true,
+		},
+		{
+			desc:    "conflicting settings name",
+			ing:     ingress("c", map[string]string{k8s.GroupSettings: "other-settings"}),
+			wantErr: true,
+		},
+		{
+			desc:     "conflicting values overridden by settings",
+			ing:      ingress("c", map[string]string{k8s.ExternalIPv4Address: "5.6.7.8", k8s.RedirectHTTPToHTTPS: "false"}),
+			settings: &v1alpha1.IngressGroupSettings{Addresses: &v1alpha1.Addresses{ExternalIPv4: "auto"}, RedirectHTTPToHTTPS: &redirect},
+		},
+		{
+			desc: "conflict of other ingresses",
+			ing:  ingress("b", map[string]string{k8s.RedirectHTTPToHTTPS: "true"}),
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			err := NewValidator().ValidateIngressInGroup(g, tc.ing, tc.settings)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestValidator_ValidateIngressInGroup_InvalidIngressOfGroup(t *testing.T) {
+	serviceBackend := &networking.IngressBackend{Service: &networking.IngressServiceBackend{
+		Name: "svc", Port: networking.ServiceBackendPort{Number: 80},
+	}}
+	g := &k8s.IngressGroup{Tag: "tag", Items: []networking.Ingress{
+		{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "broken", Annotations: map[string]string{k8s.RequestTimeout: "ten seconds"}}},
+		{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "default-backend"},
+			Spec:       networking.IngressSpec{DefaultBackend: serviceBackend},
+		},
+	}}
+
+	err := NewValidator().ValidateIngressInGroup(g, networking.Ingress{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "ing", Annotations: map[string]string{k8s.RequestTimeout: "10s"}},
+	}, nil)
+	assert.NoError(t, err, "invalid ingress of the group mustn't block other ingresses")
+
+	err = NewValidator().ValidateIngressInGroup(g, networking.Ingress{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "ing"},
+		Spec:       networking.IngressSpec{DefaultBackend: serviceBackend},
+	}, nil)
+	assert.Error(t, err, "second default backend is introduced by the ingress")
+
+	err = NewValidator().ValidateIngressInGroup(g, networking.Ingress{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "broken"},
+	}, nil)
+	assert.NoError(t, err, "fix of invalid ingress is admitted")
+}
+
+func TestValidator_RulesStatus(t *testing.T) {
+	path := func(p string, backend networking.IngressBackend) networking.HTTPIngressPath {
+		pathType := networking.PathTypePrefix
+		return networking.HTTPIngressPath{Path: p, PathType: &pathType, Backend: backend}
+	}
+	serviceBackend := networking.IngressBackend{Service: &networking.IngressServiceBackend{
+		Name: "svc", Port: networking.ServiceBackendPort{Number: 80},
+	}}
+	g := &k8s.IngressGroup{Tag: "tag", Items: []networking.Ingress{
+		{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "a", Generation: 2},
+			Spec: networking.IngressSpec{
+				DefaultBackend: &serviceBackend,
+				Rules: []networking.IngressRule{{
+					Host: "example.com",
+					IngressRuleValue: networking.IngressRuleValue{HTTP: &networking.HTTPIngressRuleValue{Paths: []networking.HTTPIngressPath{
+						path("/", serviceBackend),
+						path("/teapot", networking.IngressBackend{Resource: &v1.TypedLocalObjectReference{Kind: "DirectResponse", Name: "teapot"}}),
+					}}},
+				}},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "b", Annotations: map[string]string{k8s.RequestTimeout: "ten seconds"}},
+			Spec: networking.IngressSpec{Rules: []networking.IngressRule{{
+				Host:             "b.example.com",
+				IngressRuleValue: networking.IngressRuleValue{HTTP: &networking.HTTPIngressRuleValue{Paths: []networking.HTTPIngressPath{path("/", serviceBackend)}}},
+			}}},
+		},
+	}}
+
+	ret := NewValidator().RulesStatus(g, k8s.ReasonReconciliationFailed)
+	assert.Len(t, ret, 2)
+
+	assert.Equal(t, "a", ret[0].Name)
+	assert.Equal(t, int64(2), ret[0].ObservedGeneration)
+	assert.Len(t, ret[0].Rules, 3)
+	assert.Equal(t, v1alpha1.IngressRuleStatus{Reason: k8s.ReasonReconciliationFailed}, ret[0].Rules[0])
+	assert.Equal(t, v1alpha1.IngressRuleStatus{Host: "example.com", Path: "/", Reason: k8s.ReasonReconciliationFailed}, ret[0].Rules[1])
+	assert.Equal(t, k8s.ReasonRuleInvalid, ret[0].Rules[2].Reason)
+	assert.Contains(t, ret[0].Rules[2].Message, "direct response action for host example.com and path /teapot not found")
+
+	assert.Len(t, ret[1].Rules, 1)
+	assert.Equal(t, k8s.ReasonRuleInvalid, ret[1].Rules[0].Reason)
+	assert.False(t, ret[1].Rules[0].Accepted)
+
+	g.Items = g.Items[:1]
+	ret = NewValidator().RulesStatus(g, k8s.ReasonReconciliationComplete)
+	for _, rule := range ret[0].Rules {
+		assert.True(t, rule.Accepted)
+		assert.Equal(t, k8s.ReasonRuleAccepted, rule.Reason)
+	}
+}
@@ -16,12 +16,16 @@ type Certificate struct {
 	Key   string
 	ID    string
 	Name  string
+	// Labels are set in addition to the label of certificates managed by the controller
+	Labels map[string]string
 }
 
 type CertRepo interface {
 	LoadCertificate(ctx context.Context, name string) (*certificatemanager.Certificate, error)
 	LoadCertificates(ctx context.Context) (map[string]*certificatemanager.Certificate, error)
 	LoadCertificateData(context.Context, string) (*certificatemanager.GetCertificateContentResponse, error)
+	// GetCertificate gets certificate by ID regardless of whether it's managed by the controller
+	GetCertificate(ctx context.Context, id string) (*certificatemanager.Certificate, error)
 
 	CreateCertificate(context.Context, Certificate) error
 	UpdateCertificate(context.Context, Certificate) error
@@ -85,15 +89,24 @@ func (r *certRepo) LoadCertificateData(ctx context.Context, id string) (*certifi
 	return data, nil
 }
 
+func (r *certRepo) GetCertificate(ctx context.Context, id string) (*certificatemanager.Certificate, error) {
+	cert, err := r.sdk.Certificates().Certificate().Get(ctx, &certificatemanager.GetCertificateRequest{
+		CertificateId: id,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get certificate %s: %w", id, err)
+	}
+
+	return cert, nil
+}
+
 func (r *certRepo) CreateCertificate(ctx context.Context, cert Certificate) error {
 	_, err := r.sdk.Certificates().Certificate().Create(ctx, &certificatemanager.CreateCertificateRequest{
 		Chain:      cert.Chain,
 		PrivateKey: cert.Key,
 		FolderId:   r.folderID,
 		Name:       cert.Name,
-		Labels: map[string]string{
-			"yc-alb-ingress-controller": CertLabel,
-		},
+		Labels:     certLabels(cert),
 	})
 	return err
 }
@@ -103,9 +116,7 @@ func (r *certRepo) UpdateCertificate(ctx context.Context, cert Certificate) erro
 		Chain:         cert.Chain,
 		CertificateId: cert.ID,
 		PrivateKey:    cert.Key,
-		Labels: map[string]string{
-			"yc-alb-ingress-controller": CertLabel,
-		},
+		Labels:        certLabels(cert),
 	})
 	return err
 }
@@ -117,3 +128,13 @@ func (r *certRepo) DeleteCertificate(ctx context.Context, id string) error {
 
 	return err
 }
+
+func certLabels(cert Certificate) map[string]string {
+	labels := map[string]string{
+		"yc-alb-ingress-controller": CertLabel,
+	}
+	for k, v := range cert.Labels {
+		labels[k] = v
+	}
+	return labels
+}
@@ -0,0 +1,145 @@
+package yc
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/yandex-cloud/go-genproto/yandex/cloud/operation"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/yandex-cloud/yc-alb-ingress-controller/pkg/protoeq"
+)
+
+// DryRunOperationID is ID of operations returned instead of mutating cloud resources in dry-run mode.
+// Such operations never complete, so callers requeue as if the change was in progress.
+const DryRunOperationID = "dry-run"
+
+const (
+	ActionCreate = "create"
+	ActionUpdate = "update"
+	ActionRename = "rename"
+	ActionAdopt  = "adopt"
+	ActionDelete = "delete"
+)
+
+// PlannedChange is a mutation of cloud resource which would be applied outside of dry-run mode
+type PlannedChange struct {
+	Action       string
+	ResourceType string
+	Name         string
+	Diff         string
+}
+
+// Plan collects changes planned during reconciliation of ingress group
+type Plan struct {
+	mu      sync.Mutex
+	changes []PlannedChange
+}
+
+func (p *Plan) add(change PlannedChange) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.changes = append(p.changes, change)
+}
+
+func (p *Plan) Changes() []PlannedChange {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]PlannedChange(nil), p.changes...)
+}
+
+type planKey struct{}
+
+// WithPlan returns context in which changes skipped by repositories in dry-run mode are collected into plan
+func WithPlan(ctx context.Context, plan *Plan) context.Context {
+	return context.WithValue(ctx, planKey{}, plan)
+}
+
+func planFromContext(ctx context.Context) *Plan {
+	plan, _ := ctx.Value(planKey{}).(*Plan)
+	return plan
+}
+
+// skipMutation is called by Repository in dry-run mode instead of mutating cloud resource, it returns operation
+// with the provided metadata which never completes
+func skipMutation(ctx context.Context, action, resourceType, name string, actual, expected proto.Message, metadata proto.Message) (*operation.Operation, error) {
+	record(ctx, action, resourceType, name, actual, expected)
+	return dryRunOperation(metadata)
+}
+
+// record logs skipped mutation and adds it to the plan. For updates only fields which are set in the expected
+// resource are compared, so output-only fields of the actual one like status don't show up in the diff.
+func record(ctx context.Context, action, resourceType, name string, actual, expected proto.Message) {
+	var diff string
+	if expected != nil {
+		var x proto.Message
+		if actual != nil {
+			x = onlyFieldsOf(actual, expected)
+		}
+		diff = protoeq.Diff(x, expected)
+	}
+
+	change := PlannedChange{Action: action, ResourceType: resourceType, Name: name, Diff: diff}
+	log.FromContext(ctx).Info("dry run, cloud resource is not changed",
+		"action", action, "resourceType", resourceType, "resourceName", name, "diff", diff)
+	if plan := planFromContext(ctx); plan != nil {
+		plan.add(change)
+	}
+}
+
+func onlyFieldsOf(actual, expected proto.Message) proto.Message {
+	ret := proto.Clone(actual)
+	retRef, expRef := ret.ProtoReflect(), expected.ProtoReflect()
+	fields := retRef.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		if !expRef.Has(fields.Get(i)) {
+			retRef.Clear(fields.Get(i))
+		}
+	}
+	return ret
+}
+
+func dryRunOperation(metadata proto.Message) (*operation.Operation, error) {
+	md, err := anypb.New(metadata)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build dry run operation metadata: %w", err)
+	}
+	return &operation.Operation{Id: DryRunOperationID, Metadata: md}, nil
+}
+
+type dryRunCertRepo struct {
+	CertRepo
+}
+
+// NewDryRunCertRepo returns CertRepo which loads certificates from the wrapped one, but only logs
+// their changes and records them into the plan found in context
+func NewDryRunCertRepo(repo CertRepo) CertRepo {
+	return &dryRunCertRepo{CertRepo: repo}
+}
+
+func (r *dryRunCertRepo) CreateCertificate(ctx context.Context, cert Certificate) error {
+	recordCertificate(ctx, ActionCreate, cert.Name)
+	return nil
+}
+
+func (r *dryRunCertRepo) UpdateCertificate(ctx context.Context, cert Certificate) error {
+	recordCertificate(ctx, ActionUpdate, cert.Name)
+	return nil
+}
+
+func (r *dryRunCertRepo) DeleteCertificate(ctx context.Context, id string) error {
+	recordCertificate(ctx, ActionDelete, id)
+	return nil
+}
+
+// recordCertificate records change of certificate without diff, so its content doesn't get into logs and statuses
+func recordCertificate(ctx context.Context, action, name string) {
+	log.FromContext(ctx).Info("dry run, cloud resource is not changed",
+		"action", action, "resourceType", "certificate", "resourceName", name)
+	if plan := planFromContext(ctx); plan != nil {
+		plan.add(PlannedChange{Action: action, ResourceType: "certificate", Name: name})
+	}
+}
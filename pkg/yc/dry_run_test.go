@@ -0,0 +1,62 @@
+package yc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yandex-cloud/go-genproto/yandex/cloud/apploadbalancer/v1"
+	"github.com/yandex-cloud/go-sdk/operation"
+)
+
+func TestDryRunRepository(t *testing.T) {
+	repo := &Repository{dryRun: true}
+	plan := &Plan{}
+	ctx := WithPlan(context.Background(), plan)
+
+	router := &apploadbalancer.HttpRouter{
+		Name:         "router",
+		VirtualHosts: []*apploadbalancer.VirtualHost{{Name: "vh", Authority: []string{"example.com"}}},
+	}
+	op, err := repo.CreateHTTPRouter(ctx, router)
+	require.NoError(t, err)
+	assert.Equal(t, DryRunOperationID, op.Id)
+	md, err := operation.UnmarshalAny(op.Metadata)
+	require.NoError(t, err)
+	assert.IsType(t, &apploadbalancer.CreateHttpRouterMetadata{}, md)
+
+	require.NoError(t, repo.DeleteRouters(ctx, []*apploadbalancer.HttpRouter{nil, {Id: "router-id", Name: "old-router"}}))
+	require.NoError(t, repo.DeleteTargetGroup(ctx, &apploadbalancer.TargetGroup{Id: "tg-id", Name: "tg"}))
+
+	changes := plan.Changes()
+	require.Len(t, changes, 3)
+	assert.Equal(t, ActionCreate, changes[0].Action)
+	assert.Equal(t, "router", changes[0].Name)
+	assert.Contains(t, changes[0].Diff, "example.com")
+	assert.Equal(t, PlannedChange{Action: ActionDelete, ResourceType: "http router", Name: "old-router"}, changes[1])
+	assert.Equal(t, PlannedChange{Action: ActionDelete, ResourceType: "target group", Name: "tg"}, changes[2])
+}
+
+func TestRecord_OnlyExpectedFieldsAreCompared(t *testing.T) {
+	plan := &Plan{}
+	ctx := WithPlan(context.Background(), plan)
+
+	actual := &apploadbalancer.LoadBalancer{
+		Id:               "alb-id",
+		Name:             "alb",
+		Status:           apploadbalancer.LoadBalancer_ACTIVE,
+		SecurityGroupIds: []string{"sg-1"},
+	}
+	expected := &apploadbalancer.LoadBalancer{
+		Id:               "alb-id",
+		Name:             "alb",
+		SecurityGroupIds: []string{"sg-2"},
+	}
+	record(ctx, ActionUpdate, "load balancer", "alb", actual, expected)
+
+	changes := plan.Changes()
+	require.Len(t, changes, 1)
+	assert.Contains(t, changes[0].Diff, "sg-2")
+	assert.NotContains(t, changes[0].Diff, "ACTIVE")
+}
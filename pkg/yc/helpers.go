@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/yandex-cloud/go-genproto/yandex/cloud/apploadbalancer/v1"
 	"github.com/yandex-cloud/go-genproto/yandex/cloud/operation"
@@ -12,6 +13,7 @@ import (
 	"google.golang.org/protobuf/proto"
 	"k8s.io/apimachinery/pkg/util/sets"
 
+	"github.com/yandex-cloud/yc-alb-ingress-controller/pkg/metrics"
 	"github.com/yandex-cloud/yc-alb-ingress-controller/pkg/protoeq"
 )
 
@@ -30,7 +32,9 @@ func (sdk OperationWaiter) Result(op *operation.Operation, err error) (proto.Mes
 	if e != nil {
 		return nil, e
 	}
+	start := time.Now()
 	e = o.Wait(context.Background())
+	metrics.ObserveOperationWait(start, e)
 	if e != nil {
 		return nil, err
 	}
@@ -132,14 +136,18 @@ func listenerNeedsUpdate(listener, spec *apploadbalancer.Listener) bool {
 		return true
 	}
 
+	// listeners on custom ports are named by port, so protocol of the listener with the same name may change
 	switch l1 := listener.Listener.(type) {
 	case *apploadbalancer.Listener_Http:
 		l2, ok := spec.Listener.(*apploadbalancer.Listener_Http)
-		return ok && !protoeq.Equal(l1.Http, l2.Http)
+		return !ok || !protoeq.Equal(l1.Http, l2.Http)
 	// TODO: TLS listeners comparison is probably incorrect. implement proper TLS listener update confirmation
 	case *apploadbalancer.Listener_Tls:
 		l2, ok := spec.Listener.(*apploadbalancer.Listener_Tls)
-		return ok && !protoeq.Equal(l1.Tls, l2.Tls)
+		return !ok || !protoeq.Equal(l1.Tls, l2.Tls)
+	case *apploadbalancer.Listener_Stream:
+		l2, ok := spec.Listener.(*apploadbalancer.Listener_Stream)
+		return !ok || !protoeq.Equal(l1.Stream, l2.Stream)
 	default:
 		return false
 	}
@@ -197,6 +205,9 @@ func (*UpdatePredicates) BackendGroupNeedsUpdate(g1, g2 *apploadbalancer.Backend
 	case *apploadbalancer.BackendGroup_Grpc:
 		t2, ok := b2.(*apploadbalancer.BackendGroup_Grpc)
 		return !ok || ok && !protoeq.Equal(t1.Grpc, t2.Grpc)
+	case *apploadbalancer.BackendGroup_Stream:
+		t2, ok := b2.(*apploadbalancer.BackendGroup_Stream)
+		return !ok || ok && !protoeq.Equal(t1.Stream, t2.Stream)
 	}
 	return false
 }
@@ -516,6 +516,34 @@ func TestBalancerNeedsUpdate(t *testing.T) {
 			},
 		}
 
+		listenerDiffProtocol = &apploadbalancer.Listener{
+			Name:      "listener-1",
+			Endpoints: listener.Endpoints,
+			Listener: &apploadbalancer.Listener_Tls{
+				Tls: &apploadbalancer.TlsListener{
+					DefaultHandler: &apploadbalancer.TlsHandler{
+						Handler: &apploadbalancer.TlsHandler_HttpHandler{
+							HttpHandler: &apploadbalancer.HttpHandler{HttpRouterId: "router-1"},
+						},
+					},
+				},
+			},
+		}
+
+		differentProtocol = apploadbalancer.LoadBalancer{
+			AllocationPolicy: &apploadbalancer.AllocationPolicy{
+				Locations: []*apploadbalancer.Location{
+					location1,
+				},
+			},
+			SecurityGroupIds: []string{
+				"sg1", "sg2",
+			},
+			Listeners: []*apploadbalancer.Listener{
+				listenerDiffProtocol,
+			},
+		}
+
 		balancerAutoEndpoints = apploadbalancer.LoadBalancer{
 			AllocationPolicy: &apploadbalancer.AllocationPolicy{
 				Locations: []*apploadbalancer.Location{
@@ -548,6 +576,12 @@ func TestBalancerNeedsUpdate(t *testing.T) {
 			rhs:  &differentListener2,
 			res:  true,
 		},
+		{
+			desc: "different protocol",
+			lhs:  &balancer,
+			rhs:  &differentProtocol,
+			res:  true,
+		},
 		{
 			desc: "different endpoints",
 			lhs:  &balancer,
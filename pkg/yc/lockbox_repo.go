@@ -0,0 +1,39 @@
+package yc
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/yandex-cloud/go-genproto/yandex/cloud/lockbox/v1"
+	ycsdk "github.com/yandex-cloud/go-sdk"
+)
+
+// Labels of certificates synced from Lockbox secrets
+const (
+	LockboxSecretLabel  = "yc-alb-lockbox-secret-id"
+	LockboxVersionLabel = "yc-alb-lockbox-version-id"
+)
+
+type LockboxRepo interface {
+	// GetPayload returns payload of the secret version, the current version is used if versionID is empty
+	GetPayload(ctx context.Context, secretID, versionID string) (*lockbox.Payload, error)
+}
+
+type lockboxRepo struct {
+	sdk *ycsdk.SDK
+}
+
+func NewLockboxRepo(sdk *ycsdk.SDK) LockboxRepo {
+	return &lockboxRepo{sdk: sdk}
+}
+
+func (r *lockboxRepo) GetPayload(ctx context.Context, secretID, versionID string) (*lockbox.Payload, error) {
+	payload, err := r.sdk.LockboxPayload().Payload().Get(ctx, &lockbox.GetPayloadRequest{
+		SecretId:  secretID,
+		VersionId: versionID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get payload of lockbox secret %s: %w", secretID, err)
+	}
+	return payload, nil
+}
@@ -22,6 +22,9 @@ type Repository struct {
 	sdk      *ycsdk.SDK
 	names    *metadata.Names
 	folderID string
+
+	// dryRun makes repository only read cloud resources, mutations are logged and collected into plan instead
+	dryRun bool
 }
 
 func (r *Repository) FindSubnetByID(ctx context.Context, id string) (*vpc.Subnet, error) {
@@ -48,7 +51,17 @@ func NewRepository(sdk *ycsdk.SDK, names *metadata.Names, folderID string) *Repo
 	}
 }
 
+// NewDryRunRepository returns repository which doesn't mutate cloud resources, see WithPlan
+func NewDryRunRepository(sdk *ycsdk.SDK, names *metadata.Names, folderID string) *Repository {
+	ret := NewRepository(sdk, names, folderID)
+	ret.dryRun = true
+	return ret
+}
+
 func (r *Repository) CreateBackendGroup(ctx context.Context, group *apploadbalancer.BackendGroup) (*operation.Operation, error) {
+	if r.dryRun {
+		return skipMutation(ctx, ActionCreate, "backend group", group.Name, nil, group, &apploadbalancer.CreateBackendGroupMetadata{})
+	}
 	var b apploadbalancer.CreateBackendGroupRequest_Backend
 	switch {
 	case group.GetHttp() != nil:
@@ -59,6 +72,10 @@ func (r *Repository) CreateBackendGroup(ctx context.Context, group *apploadbalan
 		b = &apploadbalancer.CreateBackendGroupRequest_Grpc{
 			Grpc: group.GetGrpc(),
 		}
+	case group.GetStream() != nil:
+		b = &apploadbalancer.CreateBackendGroupRequest_Stream{
+			Stream: group.GetStream(),
+		}
 	default:
 		return nil, fmt.Errorf("unsupported type of backend group %s", group.GetName())
 	}
@@ -72,6 +89,14 @@ func (r *Repository) CreateBackendGroup(ctx context.Context, group *apploadbalan
 }
 
 func (r *Repository) UpdateBackendGroup(ctx context.Context, group *apploadbalancer.BackendGroup) (*operation.Operation, error) {
+	if r.dryRun {
+		actual, err := r.FindBackendGroup(ctx, group.Name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find backend group: %w", err)
+		}
+		return skipMutation(ctx, ActionUpdate, "backend group", group.Name, actual, group,
+			&apploadbalancer.UpdateBackendGroupMetadata{BackendGroupId: group.Id})
+	}
 	var updateMask fieldmaskpb.FieldMask
 	var b apploadbalancer.UpdateBackendGroupRequest_Backend
 	switch {
@@ -85,6 +110,11 @@ func (r *Repository) UpdateBackendGroup(ctx context.Context, group *apploadbalan
 			Grpc: group.GetGrpc(),
 		}
 		updateMask.Paths = append(updateMask.Paths, "grpc")
+	case group.GetStream() != nil:
+		b = &apploadbalancer.UpdateBackendGroupRequest_Stream{
+			Stream: group.GetStream(),
+		}
+		updateMask.Paths = append(updateMask.Paths, "stream")
 	default:
 		return nil, fmt.Errorf("unsupported type of backend group %s", group.GetName())
 	}
@@ -96,6 +126,10 @@ func (r *Repository) UpdateBackendGroup(ctx context.Context, group *apploadbalan
 }
 
 func (r *Repository) RenameBackendGroup(ctx context.Context, groupID string, newName string) (*operation.Operation, error) {
+	if r.dryRun {
+		return skipMutation(ctx, ActionRename, "backend group", newName, nil, nil,
+			&apploadbalancer.UpdateBackendGroupMetadata{BackendGroupId: groupID})
+	}
 	return r.sdk.ApplicationLoadBalancer().BackendGroup().Update(ctx, &apploadbalancer.UpdateBackendGroupRequest{
 		BackendGroupId: groupID,
 		Name:           newName,
@@ -115,12 +149,22 @@ func (r *Repository) ListBackendGroupOperations(ctx context.Context, group *appl
 }
 
 func (r *Repository) DeleteBackendGroup(ctx context.Context, group *apploadbalancer.BackendGroup) (*operation.Operation, error) {
+	if r.dryRun {
+		return skipMutation(ctx, ActionDelete, "backend group", group.Name, nil, nil,
+			&apploadbalancer.DeleteBackendGroupMetadata{BackendGroupId: group.Id})
+	}
 	return r.sdk.ApplicationLoadBalancer().BackendGroup().Delete(ctx, &apploadbalancer.DeleteBackendGroupRequest{
 		BackendGroupId: group.Id,
 	})
 }
 
 func (r *Repository) DeleteBackendGroups(ctx context.Context, groups []*apploadbalancer.BackendGroup) error {
+	if r.dryRun {
+		for _, bg := range groups {
+			record(ctx, ActionDelete, "backend group", bg.Name, nil, nil)
+		}
+		return nil
+	}
 	var lastOp *operation.Operation
 	var lastErr error
 	for _, bg := range groups {
@@ -142,6 +186,9 @@ func (r *Repository) DeleteBackendGroups(ctx context.Context, groups []*apploadb
 }
 
 func (r *Repository) CreateHTTPRouter(ctx context.Context, router *apploadbalancer.HttpRouter) (*operation.Operation, error) {
+	if r.dryRun {
+		return skipMutation(ctx, ActionCreate, "http router", router.Name, nil, router, &apploadbalancer.CreateHttpRouterMetadata{})
+	}
 	return r.sdk.ApplicationLoadBalancer().HttpRouter().Create(ctx, &apploadbalancer.CreateHttpRouterRequest{
 		FolderId:     router.FolderId,
 		Name:         router.Name,
@@ -152,6 +199,14 @@ func (r *Repository) CreateHTTPRouter(ctx context.Context, router *apploadbalanc
 }
 
 func (r *Repository) UpdateHTTPRouter(ctx context.Context, router *apploadbalancer.HttpRouter) (*operation.Operation, error) {
+	if r.dryRun {
+		actual, err := r.GetHTTPRouter(ctx, router.Id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get http router: %w", err)
+		}
+		return skipMutation(ctx, ActionUpdate, "http router", router.Name, actual, router,
+			&apploadbalancer.UpdateHttpRouterMetadata{HttpRouterId: router.Id})
+	}
 	return r.sdk.ApplicationLoadBalancer().HttpRouter().Update(ctx, &apploadbalancer.UpdateHttpRouterRequest{
 		HttpRouterId: router.Id,
 		VirtualHosts: router.VirtualHosts,
@@ -161,6 +216,20 @@ func (r *Repository) UpdateHTTPRouter(ctx context.Context, router *apploadbalanc
 	})
 }
 
+// RelabelHTTPRouter sets name and labels of the http router, so that it's found by the controller
+func (r *Repository) RelabelHTTPRouter(ctx context.Context, router *apploadbalancer.HttpRouter) (*operation.Operation, error) {
+	if r.dryRun {
+		return skipMutation(ctx, ActionAdopt, "http router", router.Name, nil, nil,
+			&apploadbalancer.UpdateHttpRouterMetadata{HttpRouterId: router.Id})
+	}
+	return r.sdk.ApplicationLoadBalancer().HttpRouter().Update(ctx, &apploadbalancer.UpdateHttpRouterRequest{
+		HttpRouterId: router.Id,
+		Name:         router.Name,
+		Labels:       router.Labels,
+		UpdateMask:   &fieldmaskpb.FieldMask{Paths: []string{"name", "labels"}},
+	})
+}
+
 func (r *Repository) ListHTTPRouterIncompleteOperations(ctx context.Context, router *apploadbalancer.HttpRouter) ([]*operation.Operation, error) {
 	resp, err := r.sdk.ApplicationLoadBalancer().HttpRouter().ListOperations(ctx, &apploadbalancer.ListHttpRouterOperationsRequest{
 		HttpRouterId: router.Id,
@@ -173,12 +242,19 @@ func (r *Repository) ListHTTPRouterIncompleteOperations(ctx context.Context, rou
 }
 
 func (r *Repository) DeleteHTTPRouter(ctx context.Context, router *apploadbalancer.HttpRouter) (*operation.Operation, error) {
+	if r.dryRun {
+		return skipMutation(ctx, ActionDelete, "http router", router.Name, nil, nil,
+			&apploadbalancer.DeleteHttpRouterMetadata{HttpRouterId: router.Id})
+	}
 	return r.sdk.ApplicationLoadBalancer().HttpRouter().Delete(ctx, &apploadbalancer.DeleteHttpRouterRequest{
 		HttpRouterId: router.Id,
 	})
 }
 
 func (r *Repository) CreateLoadBalancer(ctx context.Context, balancer *apploadbalancer.LoadBalancer) (*operation.Operation, error) {
+	if r.dryRun {
+		return skipMutation(ctx, ActionCreate, "load balancer", balancer.Name, nil, balancer, &apploadbalancer.CreateLoadBalancerMetadata{})
+	}
 	return r.sdk.ApplicationLoadBalancer().LoadBalancer().Create(ctx, &apploadbalancer.CreateLoadBalancerRequest{
 		FolderId:         balancer.FolderId,
 		Name:             balancer.Name,
@@ -237,6 +313,8 @@ func listenerSpecListener(listener apploadbalancer.Listener_Listener) apploadbal
 		ret = &apploadbalancer.ListenerSpec_Http{Http: l.Http}
 	case *apploadbalancer.Listener_Tls:
 		ret = &apploadbalancer.ListenerSpec_Tls{Tls: l.Tls}
+	case *apploadbalancer.Listener_Stream:
+		ret = &apploadbalancer.ListenerSpec_Stream{Stream: l.Stream}
 	}
 	return ret
 }
@@ -253,6 +331,14 @@ func endpointSpecs(endpoints []*apploadbalancer.Endpoint) []*apploadbalancer.End
 }
 
 func (r *Repository) UpdateLoadBalancer(ctx context.Context, balancer *apploadbalancer.LoadBalancer) (*operation.Operation, error) {
+	if r.dryRun {
+		actual, err := r.GetLoadBalancer(ctx, balancer.Id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get load balancer: %w", err)
+		}
+		return skipMutation(ctx, ActionUpdate, "load balancer", balancer.Name, actual, balancer,
+			&apploadbalancer.UpdateLoadBalancerMetadata{LoadBalancerId: balancer.Id})
+	}
 	return r.sdk.ApplicationLoadBalancer().LoadBalancer().Update(ctx, &apploadbalancer.UpdateLoadBalancerRequest{
 		LoadBalancerId: balancer.Id,
 
@@ -280,11 +366,44 @@ func (r *Repository) ListLoadBalancerIncompleteOperations(ctx context.Context, b
 }
 
 func (r *Repository) DeleteLoadBalancer(ctx context.Context, balancer *apploadbalancer.LoadBalancer) (*operation.Operation, error) {
+	if r.dryRun {
+		return skipMutation(ctx, ActionDelete, "load balancer", balancer.Name, nil, nil,
+			&apploadbalancer.DeleteLoadBalancerMetadata{LoadBalancerId: balancer.Id})
+	}
 	return r.sdk.ApplicationLoadBalancer().LoadBalancer().Delete(ctx, &apploadbalancer.DeleteLoadBalancerRequest{
 		LoadBalancerId: balancer.Id,
 	})
 }
 
+// RelabelLoadBalancer sets name and labels of the load balancer, so that it's found by the controller
+func (r *Repository) RelabelLoadBalancer(ctx context.Context, balancer *apploadbalancer.LoadBalancer) (*operation.Operation, error) {
+	if r.dryRun {
+		return skipMutation(ctx, ActionAdopt, "load balancer", balancer.Name, nil, nil,
+			&apploadbalancer.UpdateLoadBalancerMetadata{LoadBalancerId: balancer.Id})
+	}
+	return r.sdk.ApplicationLoadBalancer().LoadBalancer().Update(ctx, &apploadbalancer.UpdateLoadBalancerRequest{
+		LoadBalancerId: balancer.Id,
+		Name:           balancer.Name,
+		Labels:         balancer.Labels,
+		UpdateMask:     &fieldmaskpb.FieldMask{Paths: []string{"name", "labels"}},
+	})
+}
+
+// ListLoadBalancers lists all load balancers of the folder
+func (r *Repository) ListLoadBalancers(ctx context.Context) ([]*apploadbalancer.LoadBalancer, error) {
+	var ret []*apploadbalancer.LoadBalancer
+	it := r.sdk.ApplicationLoadBalancer().LoadBalancer().LoadBalancerIterator(ctx, &apploadbalancer.ListLoadBalancersRequest{
+		FolderId: r.folderID,
+	})
+	for it.Next() {
+		ret = append(ret, it.Value())
+	}
+	if err := it.Error(); err != nil {
+		return nil, fmt.Errorf("failed to list load balancers: %w", err)
+	}
+	return ret, nil
+}
+
 func (r *Repository) findBalancer(ctx context.Context, tag string) (*apploadbalancer.LoadBalancer, error) {
 	resp, err := r.sdk.ApplicationLoadBalancer().LoadBalancer().List(ctx, &apploadbalancer.ListLoadBalancersRequest{
 		FolderId: r.folderID,
@@ -398,6 +517,10 @@ func (r *Repository) DeleteBalancer(ctx context.Context, balancer *apploadbalanc
 	if balancer == nil {
 		return nil
 	}
+	if r.dryRun {
+		record(ctx, ActionDelete, "load balancer", balancer.Name, nil, nil)
+		return nil
+	}
 	op, err := r.sdk.ApplicationLoadBalancer().LoadBalancer().Delete(ctx, &apploadbalancer.DeleteLoadBalancerRequest{LoadBalancerId: balancer.Id})
 	if err != nil {
 		return errors.Wrapf(err, "failed to delete balancer %s", balancer.Id)
@@ -412,6 +535,10 @@ func (r *Repository) DeleteRouters(ctx context.Context, routers []*apploadbalanc
 		if router == nil {
 			continue
 		}
+		if r.dryRun {
+			record(ctx, ActionDelete, "http router", router.Name, nil, nil)
+			continue
+		}
 		op, err := r.sdk.ApplicationLoadBalancer().HttpRouter().Delete(ctx, &apploadbalancer.DeleteHttpRouterRequest{HttpRouterId: router.Id})
 		if err != nil {
 			// TODO:handle
@@ -430,6 +557,10 @@ func (r *Repository) DeleteRouters(ctx context.Context, routers []*apploadbalanc
 }
 
 func (r *Repository) DeleteTargetGroup(ctx context.Context, group *apploadbalancer.TargetGroup) error {
+	if r.dryRun {
+		record(ctx, ActionDelete, "target group", group.Name, nil, nil)
+		return nil
+	}
 	op, err := r.sdk.ApplicationLoadBalancer().TargetGroup().Delete(ctx, &apploadbalancer.DeleteTargetGroupRequest{
 		TargetGroupId: group.Id,
 	})
@@ -440,6 +571,9 @@ func (r *Repository) DeleteTargetGroup(ctx context.Context, group *apploadbalanc
 }
 
 func (r *Repository) CreateTargetGroup(ctx context.Context, group *apploadbalancer.TargetGroup) (*operation.Operation, error) {
+	if r.dryRun {
+		return skipMutation(ctx, ActionCreate, "target group", group.Name, nil, group, &apploadbalancer.CreateTargetGroupMetadata{})
+	}
 	return r.sdk.ApplicationLoadBalancer().TargetGroup().Create(ctx, &apploadbalancer.CreateTargetGroupRequest{
 		FolderId:    r.folderID,
 		Name:        group.Name,
@@ -450,6 +584,14 @@ func (r *Repository) CreateTargetGroup(ctx context.Context, group *apploadbalanc
 }
 
 func (r *Repository) UpdateTargetGroup(ctx context.Context, group *apploadbalancer.TargetGroup) (*operation.Operation, error) {
+	if r.dryRun {
+		actual, err := r.FindTargetGroup(ctx, group.Name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find target group: %w", err)
+		}
+		return skipMutation(ctx, ActionUpdate, "target group", group.Name, actual, group,
+			&apploadbalancer.UpdateTargetGroupMetadata{TargetGroupId: group.Id})
+	}
 	return r.sdk.ApplicationLoadBalancer().TargetGroup().Update(ctx, &apploadbalancer.UpdateTargetGroupRequest{
 		TargetGroupId: group.Id,
 		Name:          group.Name,
@@ -489,6 +631,36 @@ func (r *Repository) FindBackendGroupByCR(ctx context.Context, ns, name string)
 	return r.FindBackendGroup(ctx, r.names.BackendGroupForCR(ns, name))
 }
 
+func (r *Repository) GetLoadBalancer(ctx context.Context, id string) (*apploadbalancer.LoadBalancer, error) {
+	return r.sdk.ApplicationLoadBalancer().LoadBalancer().Get(ctx, &apploadbalancer.GetLoadBalancerRequest{
+		LoadBalancerId: id,
+	})
+}
+
+func (r *Repository) GetHTTPRouter(ctx context.Context, id string) (*apploadbalancer.HttpRouter, error) {
+	return r.sdk.ApplicationLoadBalancer().HttpRouter().Get(ctx, &apploadbalancer.GetHttpRouterRequest{
+		HttpRouterId: id,
+	})
+}
+
+func (r *Repository) GetBackendGroup(ctx context.Context, id string) (*apploadbalancer.BackendGroup, error) {
+	return r.sdk.ApplicationLoadBalancer().BackendGroup().Get(ctx, &apploadbalancer.GetBackendGroupRequest{
+		BackendGroupId: id,
+	})
+}
+
+func (r *Repository) GetTargetStates(ctx context.Context, balancerID, bgID, tgID string) ([]*apploadbalancer.TargetState, error) {
+	resp, err := r.sdk.ApplicationLoadBalancer().LoadBalancer().GetTargetStates(ctx, &apploadbalancer.GetTargetStatesRequest{
+		LoadBalancerId: balancerID,
+		BackendGroupId: bgID,
+		TargetGroupId:  tgID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.TargetStates, nil
+}
+
 func (r *Repository) FindInstanceByID(ctx context.Context, id string) (*compute.Instance, error) {
 	return r.sdk.Compute().Instance().Get(ctx, &compute.GetInstanceRequest{
 		InstanceId: id,
@@ -2,8 +2,10 @@ package yc
 
 import (
 	"fmt"
+	"net/netip"
 
 	"github.com/yandex-cloud/go-genproto/yandex/cloud/compute/v1"
+	"github.com/yandex-cloud/go-genproto/yandex/cloud/vpc/v1"
 )
 
 func SubnetIDForProviderID(instance *compute.Instance, suitableIPs []string, suitableSubnets []string, preferIPv6 bool) (string, string, error) {
@@ -42,3 +44,24 @@ func SubnetIDForProviderID(instance *compute.Instance, suitableIPs []string, sui
 	}
 	return "", "", fmt.Errorf("internal: mismatch between node's address and instance network interfaces: interfaces:%v, ips:%v, subnets:%v", instance.NetworkInterfaces, suitableIPs, suitableSubnets)
 }
+
+// SubnetIDForIP returns ID of the subnet which IPv4 CIDR blocks contain the address, empty if there is no such subnet
+func SubnetIDForIP(ip string, subnets []*vpc.Subnet) (string, error) {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse IP %s: %w", ip, err)
+	}
+
+	for _, subnet := range subnets {
+		for _, block := range subnet.GetV4CidrBlocks() {
+			prefix, err := netip.ParsePrefix(block)
+			if err != nil {
+				return "", fmt.Errorf("failed to parse CIDR block %s of subnet %s: %w", block, subnet.GetId(), err)
+			}
+			if prefix.Contains(addr) {
+				return subnet.GetId(), nil
+			}
+		}
+	}
+	return "", nil
+}
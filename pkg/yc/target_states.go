@@ -0,0 +1,242 @@
+package yc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/yandex-cloud/go-genproto/yandex/cloud/apploadbalancer/v1"
+)
+
+// BalancerRepository gets balancers and routers of their listeners
+type BalancerRepository interface {
+	GetLoadBalancer(ctx context.Context, id string) (*apploadbalancer.LoadBalancer, error)
+	GetHTTPRouter(ctx context.Context, id string) (*apploadbalancer.HttpRouter, error)
+}
+
+type TargetStatesRepository interface {
+	BalancerRepository
+	GetBackendGroup(ctx context.Context, id string) (*apploadbalancer.BackendGroup, error)
+	GetTargetStates(ctx context.Context, balancerID, bgID, tgID string) ([]*apploadbalancer.TargetState, error)
+}
+
+// ZoneTargetStates counts targets of backend by their health in availability zone
+type ZoneTargetStates struct {
+	ZoneID    string
+	Healthy   int64
+	Unhealthy int64
+}
+
+type BackendTargetStates struct {
+	Name  string
+	Zones []ZoneTargetStates
+}
+
+// BackendGroupTargetStates is health of targets of backend group used by balancer of ingress group
+type BackendGroupTargetStates struct {
+	Group        string
+	BalancerID   string
+	BackendGroup *apploadbalancer.BackendGroup
+	Backends     []BackendTargetStates
+}
+
+// TargetStatesPoller periodically collects health of targets of all backend groups used by balancers
+// and passes it to the publisher
+type TargetStatesPoller struct {
+	Repo     TargetStatesRepository
+	Interval time.Duration
+	Log      logr.Logger
+
+	// Balancers returns IDs of balancers to poll by names of their ingress groups
+	Balancers func(ctx context.Context) (map[string]string, error)
+	// Publish receives states of all polled backend groups, complete is false if some of them failed to be polled
+	Publish func(ctx context.Context, states []BackendGroupTargetStates, complete bool) error
+}
+
+// Start implements manager.Runnable
+func (p *TargetStatesPoller) Start(ctx context.Context) error {
+	ticker := time.NewTicker(p.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := p.Poll(ctx); err != nil {
+				p.Log.Error(err, "failed to poll target states")
+			}
+		}
+	}
+}
+
+func (p *TargetStatesPoller) Poll(ctx context.Context) error {
+	balancers, err := p.Balancers(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list balancers: %w", err)
+	}
+
+	groups := make([]string, 0, len(balancers))
+	for group := range balancers {
+		groups = append(groups, group)
+	}
+	sort.Strings(groups)
+
+	// failure of one group doesn't prevent publishing states of the others
+	var states []BackendGroupTargetStates
+	var errs []error
+	for _, group := range groups {
+		ret, err := p.pollBalancer(ctx, group, balancers[group])
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to poll target states of ingress group %s: %w", group, err))
+		}
+		states = append(states, ret...)
+	}
+
+	if err = p.Publish(ctx, states, len(errs) == 0); err != nil {
+		errs = append(errs, fmt.Errorf("failed to publish target states: %w", err))
+	}
+	return errors.Join(errs...)
+}
+
+func (p *TargetStatesPoller) pollBalancer(ctx context.Context, group, balancerID string) ([]BackendGroupTargetStates, error) {
+	bgIDs, err := BalancerBackendGroupIDs(ctx, p.Repo, balancerID)
+	if err != nil {
+		return nil, err
+	}
+
+	// states of the rest backend groups are returned along with errors
+	var ret []BackendGroupTargetStates
+	var errs []error
+	for _, bgID := range bgIDs {
+		states, err := p.pollBackendGroup(ctx, group, balancerID, bgID)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		ret = append(ret, states)
+	}
+	return ret, errors.Join(errs...)
+}
+
+func (p *TargetStatesPoller) pollBackendGroup(ctx context.Context, group, balancerID, bgID string) (BackendGroupTargetStates, error) {
+	bg, err := p.Repo.GetBackendGroup(ctx, bgID)
+	if err != nil {
+		return BackendGroupTargetStates{}, fmt.Errorf("failed to get backend group %s: %w", bgID, err)
+	}
+
+	states := BackendGroupTargetStates{Group: group, BalancerID: balancerID, BackendGroup: bg}
+	for _, backend := range backendTargetGroups(bg) {
+		zones := make(map[string]*ZoneTargetStates)
+		for _, tgID := range backend.tgIDs {
+			targetStates, err := p.Repo.GetTargetStates(ctx, balancerID, bgID, tgID)
+			if err != nil {
+				return BackendGroupTargetStates{}, fmt.Errorf("failed to get target states of backend group %s: %w", bgID, err)
+			}
+			countTargetStates(zones, targetStates)
+		}
+		states.Backends = append(states.Backends, BackendTargetStates{Name: backend.name, Zones: sortedZones(zones)})
+	}
+	return states, nil
+}
+
+// BalancerBackendGroupIDs returns IDs of backend groups referenced by routes of balancer routers and by its stream listeners
+func BalancerBackendGroupIDs(ctx context.Context, repo BalancerRepository, balancerID string) ([]string, error) {
+	balancer, err := repo.GetLoadBalancer(ctx, balancerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get balancer %s: %w", balancerID, err)
+	}
+
+	routerIDs := make(map[string]struct{})
+	bgIDs := make(map[string]struct{})
+	addRouter := func(id string) {
+		if id != "" {
+			routerIDs[id] = struct{}{}
+		}
+	}
+	for _, l := range balancer.GetListeners() {
+		addRouter(l.GetHttp().GetHandler().GetHttpRouterId())
+		addRouter(l.GetTls().GetDefaultHandler().GetHttpHandler().GetHttpRouterId())
+		for _, sni := range l.GetTls().GetSniHandlers() {
+			addRouter(sni.GetHandler().GetHttpHandler().GetHttpRouterId())
+		}
+		if id := l.GetStream().GetHandler().GetBackendGroupId(); id != "" {
+			bgIDs[id] = struct{}{}
+		}
+	}
+
+	for routerID := range routerIDs {
+		router, err := repo.GetHTTPRouter(ctx, routerID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get http router %s: %w", routerID, err)
+		}
+		for _, vh := range router.GetVirtualHosts() {
+			for _, route := range vh.GetRoutes() {
+				if id := route.GetHttp().GetRoute().GetBackendGroupId(); id != "" {
+					bgIDs[id] = struct{}{}
+				}
+				if id := route.GetGrpc().GetRoute().GetBackendGroupId(); id != "" {
+					bgIDs[id] = struct{}{}
+				}
+			}
+		}
+	}
+
+	ret := make([]string, 0, len(bgIDs))
+	for id := range bgIDs {
+		ret = append(ret, id)
+	}
+	sort.Strings(ret)
+	return ret, nil
+}
+
+type backendTargetGroupIDs struct {
+	name  string
+	tgIDs []string
+}
+
+func backendTargetGroups(bg *apploadbalancer.BackendGroup) []backendTargetGroupIDs {
+	var ret []backendTargetGroupIDs
+	for _, b := range bg.GetHttp().GetBackends() {
+		ret = append(ret, backendTargetGroupIDs{name: b.Name, tgIDs: b.GetTargetGroups().GetTargetGroupIds()})
+	}
+	for _, b := range bg.GetGrpc().GetBackends() {
+		ret = append(ret, backendTargetGroupIDs{name: b.Name, tgIDs: b.GetTargetGroups().GetTargetGroupIds()})
+	}
+	for _, b := range bg.GetStream().GetBackends() {
+		ret = append(ret, backendTargetGroupIDs{name: b.Name, tgIDs: b.GetTargetGroups().GetTargetGroupIds()})
+	}
+	return ret
+}
+
+// countTargetStates counts targets receiving traffic as healthy and failing health checks as unhealthy,
+// draining targets are not counted
+func countTargetStates(zones map[string]*ZoneTargetStates, states []*apploadbalancer.TargetState) {
+	for _, state := range states {
+		for _, zs := range state.GetStatus().GetZoneStatuses() {
+			z, ok := zones[zs.ZoneId]
+			if !ok {
+				z = &ZoneTargetStates{ZoneID: zs.ZoneId}
+				zones[zs.ZoneId] = z
+			}
+			switch zs.Status {
+			case apploadbalancer.TargetState_HEALTHY, apploadbalancer.TargetState_PARTIALLY_HEALTHY:
+				z.Healthy++
+			case apploadbalancer.TargetState_UNHEALTHY, apploadbalancer.TargetState_TIMEOUT:
+				z.Unhealthy++
+			}
+		}
+	}
+}
+
+func sortedZones(zones map[string]*ZoneTargetStates) []ZoneTargetStates {
+	ret := make([]ZoneTargetStates, 0, len(zones))
+	for _, z := range zones {
+		ret = append(ret, *z)
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].ZoneID < ret[j].ZoneID })
+	return ret
+}
@@ -0,0 +1,218 @@
+package yc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yandex-cloud/go-genproto/yandex/cloud/apploadbalancer/v1"
+)
+
+type fakeTargetStatesRepo struct {
+	balancers    map[string]*apploadbalancer.LoadBalancer
+	routers      map[string]*apploadbalancer.HttpRouter
+	bgs          map[string]*apploadbalancer.BackendGroup
+	targetStates map[string][]*apploadbalancer.TargetState
+	failedBGs    map[string]bool
+}
+
+func (r *fakeTargetStatesRepo) GetLoadBalancer(_ context.Context, id string) (*apploadbalancer.LoadBalancer, error) {
+	return r.balancers[id], nil
+}
+
+func (r *fakeTargetStatesRepo) GetHTTPRouter(_ context.Context, id string) (*apploadbalancer.HttpRouter, error) {
+	return r.routers[id], nil
+}
+
+func (r *fakeTargetStatesRepo) GetBackendGroup(_ context.Context, id string) (*apploadbalancer.BackendGroup, error) {
+	if r.failedBGs[id] {
+		return nil, assert.AnError
+	}
+	return r.bgs[id], nil
+}
+
+func (r *fakeTargetStatesRepo) GetTargetStates(_ context.Context, _, bgID, tgID string) ([]*apploadbalancer.TargetState, error) {
+	return r.targetStates[bgID+"/"+tgID], nil
+}
+
+func targetState(statuses ...*apploadbalancer.TargetState_ZoneHealthcheckStatus) *apploadbalancer.TargetState {
+	return &apploadbalancer.TargetState{Status: &apploadbalancer.TargetState_HealthcheckStatus{ZoneStatuses: statuses}}
+}
+
+func zoneStatus(zone string, status apploadbalancer.TargetState_Status) *apploadbalancer.TargetState_ZoneHealthcheckStatus {
+	return &apploadbalancer.TargetState_ZoneHealthcheckStatus{ZoneId: zone, Status: status}
+}
+
+func TestTargetStatesPoller_Poll(t *testing.T) {
+	httpBG := &apploadbalancer.BackendGroup{
+		Id:   "bg-http",
+		Name: "http",
+		Backend: &apploadbalancer.BackendGroup_Http{Http: &apploadbalancer.HttpBackendGroup{
+			Backends: []*apploadbalancer.HttpBackend{{
+				Name: "backend-http",
+				BackendType: &apploadbalancer.HttpBackend_TargetGroups{
+					TargetGroups: &apploadbalancer.TargetGroupsBackend{TargetGroupIds: []string{"tg-1", "tg-2"}},
+				},
+			}},
+		}},
+	}
+	streamBG := &apploadbalancer.BackendGroup{
+		Id:   "bg-stream",
+		Name: "stream",
+		Backend: &apploadbalancer.BackendGroup_Stream{Stream: &apploadbalancer.StreamBackendGroup{
+			Backends: []*apploadbalancer.StreamBackend{{
+				Name: "backend-stream",
+				BackendType: &apploadbalancer.StreamBackend_TargetGroups{
+					TargetGroups: &apploadbalancer.TargetGroupsBackend{TargetGroupIds: []string{"tg-3"}},
+				},
+			}},
+		}},
+	}
+
+	repo := &fakeTargetStatesRepo{
+		balancers: map[string]*apploadbalancer.LoadBalancer{
+			"alb-1": {
+				Id: "alb-1",
+				Listeners: []*apploadbalancer.Listener{
+					{
+						Name: "http",
+						Listener: &apploadbalancer.Listener_Http{Http: &apploadbalancer.HttpListener{
+							Handler: &apploadbalancer.HttpHandler{HttpRouterId: "router-1"},
+						}},
+					},
+					{
+						Name: "stream",
+						Listener: &apploadbalancer.Listener_Stream{Stream: &apploadbalancer.StreamListener{
+							Handler: &apploadbalancer.StreamHandler{BackendGroupId: "bg-stream"},
+						}},
+					},
+				},
+			},
+		},
+		routers: map[string]*apploadbalancer.HttpRouter{
+			"router-1": {
+				Id: "router-1",
+				VirtualHosts: []*apploadbalancer.VirtualHost{{
+					Routes: []*apploadbalancer.Route{
+						{Route: &apploadbalancer.Route_Http{Http: &apploadbalancer.HttpRoute{
+							Action: &apploadbalancer.HttpRoute_Route{Route: &apploadbalancer.HttpRouteAction{BackendGroupId: "bg-http"}},
+						}}},
+						{Route: &apploadbalancer.Route_Http{Http: &apploadbalancer.HttpRoute{
+							Action: &apploadbalancer.HttpRoute_Redirect{Redirect: &apploadbalancer.RedirectAction{}},
+						}}},
+					},
+				}},
+			},
+		},
+		bgs: map[string]*apploadbalancer.BackendGroup{"bg-http": httpBG, "bg-stream": streamBG},
+		targetStates: map[string][]*apploadbalancer.TargetState{
+			"bg-http/tg-1": {
+				targetState(zoneStatus("ru-central1-a", apploadbalancer.TargetState_HEALTHY), zoneStatus("ru-central1-b", apploadbalancer.TargetState_UNHEALTHY)),
+				targetState(zoneStatus("ru-central1-a", apploadbalancer.TargetState_DRAINING)),
+			},
+			"bg-http/tg-2": {
+				targetState(zoneStatus("ru-central1-a", apploadbalancer.TargetState_PARTIALLY_HEALTHY), zoneStatus("ru-central1-b", apploadbalancer.TargetState_TIMEOUT)),
+			},
+			"bg-stream/tg-3": {
+				targetState(zoneStatus("ru-central1-b", apploadbalancer.TargetState_HEALTHY)),
+			},
+		},
+	}
+
+	var published []BackendGroupTargetStates
+	p := &TargetStatesPoller{
+		Repo: repo,
+		Balancers: func(context.Context) (map[string]string, error) {
+			return map[string]string{"group": "alb-1"}, nil
+		},
+		Publish: func(_ context.Context, states []BackendGroupTargetStates, complete bool) error {
+			assert.True(t, complete)
+			published = states
+			return nil
+		},
+	}
+
+	require.NoError(t, p.Poll(context.Background()))
+	assert.Equal(t, []BackendGroupTargetStates{
+		{
+			Group:        "group",
+			BalancerID:   "alb-1",
+			BackendGroup: httpBG,
+			Backends: []BackendTargetStates{{
+				Name: "backend-http",
+				Zones: []ZoneTargetStates{
+					{ZoneID: "ru-central1-a", Healthy: 2},
+					{ZoneID: "ru-central1-b", Unhealthy: 2},
+				},
+			}},
+		},
+		{
+			Group:        "group",
+			BalancerID:   "alb-1",
+			BackendGroup: streamBG,
+			Backends: []BackendTargetStates{{
+				Name:  "backend-stream",
+				Zones: []ZoneTargetStates{{ZoneID: "ru-central1-b", Healthy: 1}},
+			}},
+		},
+	}, published)
+}
+
+func TestTargetStatesPoller_Poll_PartialFailure(t *testing.T) {
+	streamBG := &apploadbalancer.BackendGroup{
+		Id:   "bg-stream",
+		Name: "stream",
+		Backend: &apploadbalancer.BackendGroup_Stream{Stream: &apploadbalancer.StreamBackendGroup{
+			Backends: []*apploadbalancer.StreamBackend{{
+				Name: "backend-stream",
+				BackendType: &apploadbalancer.StreamBackend_TargetGroups{
+					TargetGroups: &apploadbalancer.TargetGroupsBackend{TargetGroupIds: []string{"tg-1"}},
+				},
+			}},
+		}},
+	}
+	streamListener := func(bgID string) *apploadbalancer.Listener {
+		return &apploadbalancer.Listener{Listener: &apploadbalancer.Listener_Stream{Stream: &apploadbalancer.StreamListener{
+			Handler: &apploadbalancer.StreamHandler{BackendGroupId: bgID},
+		}}}
+	}
+
+	repo := &fakeTargetStatesRepo{
+		balancers: map[string]*apploadbalancer.LoadBalancer{
+			"alb-1": {Id: "alb-1", Listeners: []*apploadbalancer.Listener{streamListener("bg-failed")}},
+			"alb-2": {Id: "alb-2", Listeners: []*apploadbalancer.Listener{streamListener("bg-stream")}},
+		},
+		bgs:       map[string]*apploadbalancer.BackendGroup{"bg-stream": streamBG},
+		failedBGs: map[string]bool{"bg-failed": true},
+		targetStates: map[string][]*apploadbalancer.TargetState{
+			"bg-stream/tg-1": {targetState(zoneStatus("ru-central1-a", apploadbalancer.TargetState_HEALTHY))},
+		},
+	}
+
+	var published []BackendGroupTargetStates
+	var publishedComplete bool
+	p := &TargetStatesPoller{
+		Repo: repo,
+		Balancers: func(context.Context) (map[string]string, error) {
+			return map[string]string{"failed": "alb-1", "group": "alb-2"}, nil
+		},
+		Publish: func(_ context.Context, states []BackendGroupTargetStates, complete bool) error {
+			published, publishedComplete = states, complete
+			return nil
+		},
+	}
+
+	err := p.Poll(context.Background())
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.False(t, publishedComplete)
+	assert.Equal(t, []BackendGroupTargetStates{{
+		Group:        "group",
+		BalancerID:   "alb-2",
+		BackendGroup: streamBG,
+		Backends: []BackendTargetStates{{
+			Name:  "backend-stream",
+			Zones: []ZoneTargetStates{{ZoneID: "ru-central1-a", Healthy: 1}},
+		}},
+	}}, published)
+}